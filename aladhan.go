@@ -2,12 +2,15 @@
 package schedule
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 )
 
+const aladhanBaseURL = "https://api.aladhan.com/v1"
+
 // FiveDailyPrayers is all of the five prayers for the day in index +-1 as well as the time of prayer
 type FiveDailyPrayers struct {
 	Fajr    string `json:"Fajr"`
@@ -16,25 +19,136 @@ type FiveDailyPrayers struct {
 	Asr     string `json:"Asr"`
 	Maghrib string `json:"Maghrib"`
 	Isha    string `json:"Isha"`
+	Imsak   string `json:"Imsak" mapstructure:"-"` // Start of the fast, not a prayer
+}
+
+// prayerTimingNames are the names of the FiveDailyPrayers timings in the order of the day
+var prayerTimingNames = []string{"Imsak", "Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// timing returns the timing called name, or an empty string when there is no such timing
+func (f *FiveDailyPrayers) timing(name string) string {
+	switch name {
+	case "Imsak":
+		return f.Imsak
+	case "Fajr":
+		return f.Fajr
+	case "Sunrise":
+		return f.Sunrise
+	case "Dhuhr":
+		return f.Dhuhr
+	case "Asr":
+		return f.Asr
+	case "Maghrib":
+		return f.Maghrib
+	case "Isha":
+		return f.Isha
+	}
+	return ""
+}
+
+// setTiming sets the timing called name, returning false when there is no such timing
+func (f *FiveDailyPrayers) setTiming(name string, value string) bool {
+	switch name {
+	case "Imsak":
+		f.Imsak = value
+	case "Fajr":
+		f.Fajr = value
+	case "Sunrise":
+		f.Sunrise = value
+	case "Dhuhr":
+		f.Dhuhr = value
+	case "Asr":
+		f.Asr = value
+	case "Maghrib":
+		f.Maghrib = value
+	case "Isha":
+		f.Isha = value
+	default:
+		return false
+	}
+	return true
 }
 
 // PCalInput is the customer geolocation and prayer source method
 type PCalInput struct {
-	CustTime    time.Time
-	Institution int     // Aladhan prayer data source method
-	Latitude    float32 // Client latitude to use with aladhan
-	Longitude   float32 // Client longitude to use with aladhan
+	CustTime                 time.Time
+	Institution              int           // Aladhan prayer data source method
+	Latitude                 float32       // Client latitude to use with aladhan
+	Longitude                float32       // Client longitude to use with aladhan
+	School                   int           // Asr juristic school.  0 for Shafi and 1 for Hanafi
+	LatitudeAdjustmentMethod int           // High latitude rule.  0 for the provider default, 1 middle of the night, 2 one seventh, 3 angle based
+	Offsets                  PrayerOffsets // Minutes added to each calculated time
+}
+
+// PrayerOffsets are minutes added to each calculated time, such as to match a local mosque.  Negative values move the time earlier
+type PrayerOffsets struct {
+	Imsak   int
+	Fajr    int
+	Sunrise int
+	Dhuhr   int
+	Asr     int
+	Maghrib int
+	Isha    int
 }
 
 // PCalOutput contains the prayer time of the month as well as the return code
 type PCalOutput struct {
 	Code   int    `json:"code"`
 	Status string `json:"status"`
-	Data   []struct {
-		Timings FiveDailyPrayers
-	}
+	Data   []PCalDay
 	Latitude  float32 // Client latitude to use with aladhan
 	Longitude float32 // Client longitude to use with aladhan
+	Source    string  `json:"source,omitempty"` // Provider which produced the timings, such as SourceAladhan
+	Stale     bool    `json:"stale,omitempty"`  // Whether the timings are an expired cache entry served because every provider failed
+}
+
+// Sources of a PCalOutput
+const (
+	SourceAladhan = "aladhan"
+	SourceLocal   = "local"
+)
+
+// PCalDay is a single day of prayer timings within a PCalOutput month
+type PCalDay struct {
+	Timings FiveDailyPrayers
+	Date    PCalDate       `json:"date"`
+	Meta    PCalMeta       `json:"meta"`
+	Hijri   HijriDate      `json:"hijri"`            // Set by PCalOutput.SetHijriDates
+	Events  []IslamicEvent `json:"events,omitempty"` // Set by PCalOutput.SetIslamicEvents
+}
+
+// PCalDate is the Gregorian date of a PCalDay as returned by aladhan
+type PCalDate struct {
+	Readable  string `json:"readable"`
+	Timestamp string `json:"timestamp"`
+	Gregorian struct {
+		Date string `json:"date"` // DD-MM-YYYY
+	} `json:"gregorian"`
+}
+
+// PCalMeta contains the location details the timings of a PCalDay were calculated for
+type PCalMeta struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+}
+
+// Day returns midnight of the PCalDay date in the timezone the timings were calculated for
+func (d *PCalDay) Day() (time.Time, error) {
+	location := time.UTC
+	if d.Meta.Timezone != "" {
+		tz, err := time.LoadLocation(d.Meta.Timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unable to load timezone %s: %s", d.Meta.Timezone, err)
+		}
+		location = tz
+	}
+
+	day, err := time.ParseInLocation("02-01-2006", d.Date.Gregorian.Date, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse day date %q: %s", d.Date.Gregorian.Date, err)
+	}
+	return day, nil
 }
 
 func aladhanReq(reqURL <-chan string, pcalOutput chan <-*PCalOutput) {
@@ -74,14 +188,7 @@ https://api.aladhan.com/v1/calendar?latitude=51.508515&longitude=-0.1254872&meth
 */
 func AladhanData(input *PCalInput) (*PCalOutput, error) {
 	// Use HERE API to get client coordinates
-	reqURL := fmt.Sprintf(
-		"https://api.aladhan.com/v1/calendar?latitude=%v&longitude=%v&method=%d&month=%d&year=%d",
-		input.Latitude,
-		input.Longitude,
-		input.Institution,
-		input.CustTime.Month(),
-		input.CustTime.Year(),
-	)
+	reqURL := aladhanCalendarURL(aladhanBaseURL, input)
 
     var urlChan = make(chan string)
 
@@ -96,3 +203,136 @@ func AladhanData(input *PCalInput) (*PCalOutput, error) {
 	return monthOutput, nil
 }
 
+
+// AladhanProvider is a MonthProvider which requests the monthly prayer timings from aladhan
+type AladhanProvider struct {
+	Client  *http.Client // Defaults to http.DefaultClient
+	BaseURL string       // Defaults to https://api.aladhan.com/v1
+}
+
+// MonthlyPrayers returns the aladhan prayer timings of the month of input.CustTime
+func (a *AladhanProvider) MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error) {
+	output := new(PCalOutput)
+	if err := a.getJSON(ctx, aladhanCalendarURL(a.baseURL(), input), output); err != nil {
+		return nil, err
+	}
+	if output.Code != 200 {
+		return nil, fmt.Errorf("aladhan response is not 200: %d %s", output.Code, output.Status)
+	}
+	output.Latitude = input.Latitude
+	output.Longitude = input.Longitude
+	output.Source = SourceAladhan
+	return output, nil
+}
+
+// YearlyPrayers returns the aladhan prayer timings of every month of the year of input.CustTime in a single request
+func (a *AladhanProvider) YearlyPrayers(ctx context.Context, input *PCalInput) ([]*PCalOutput, error) {
+	reqURL := fmt.Sprintf(
+		"%s/calendar?latitude=%v&longitude=%v&method=%d&year=%d&annual=true%s",
+		a.baseURL(),
+		input.Latitude,
+		input.Longitude,
+		input.Institution,
+		input.CustTime.Year(),
+		aladhanSettingsQuery(input),
+	)
+
+	annual := struct {
+		Code   int                  `json:"code"`
+		Status string               `json:"status"`
+		Data   map[string][]PCalDay `json:"data"`
+	}{}
+	if err := a.getJSON(ctx, reqURL, &annual); err != nil {
+		return nil, err
+	}
+	if annual.Code != 200 {
+		return nil, fmt.Errorf("aladhan response is not 200: %d %s", annual.Code, annual.Status)
+	}
+
+	output := make([]*PCalOutput, 12)
+	for month := 1; month <= 12; month++ {
+		days, ok := annual.Data[fmt.Sprint(month)]
+		if !ok {
+			return nil, fmt.Errorf("aladhan annual response is missing month %d", month)
+		}
+		output[month-1] = &PCalOutput{
+			Code:      annual.Code,
+			Status:    annual.Status,
+			Data:      days,
+			Latitude:  input.Latitude,
+			Longitude: input.Longitude,
+			Source:    SourceAladhan,
+		}
+	}
+	return output, nil
+}
+
+func (a *AladhanProvider) baseURL() string {
+	if a.BaseURL == "" {
+		return aladhanBaseURL
+	}
+	return a.BaseURL
+}
+
+// getJSON requests reqURL from aladhan and decodes the response body into output
+func (a *AladhanProvider) getJSON(ctx context.Context, reqURL string, output interface{}) error {
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("unable to build aladhan request: %s", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to request aladhan calendar: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(output); err != nil {
+		return fmt.Errorf("unable to decode aladhan response: %s", err)
+	}
+	return nil
+}
+
+// aladhanCalendarURL returns the aladhan monthly calendar url of input
+func aladhanCalendarURL(baseURL string, input *PCalInput) string {
+	return fmt.Sprintf(
+		"%s/calendar?latitude=%v&longitude=%v&method=%d&month=%d&year=%d%s",
+		baseURL,
+		input.Latitude,
+		input.Longitude,
+		input.Institution,
+		input.CustTime.Month(),
+		input.CustTime.Year(),
+		aladhanSettingsQuery(input),
+	)
+}
+
+// aladhanSettingsQuery returns the query parameters of the calculation settings which differ from the aladhan defaults
+func aladhanSettingsQuery(input *PCalInput) string {
+	query := ""
+	if input.School != 0 {
+		query += fmt.Sprintf("&school=%d", input.School)
+	}
+	if input.LatitudeAdjustmentMethod != 0 {
+		query += fmt.Sprintf("&latitudeAdjustmentMethod=%d", input.LatitudeAdjustmentMethod)
+	}
+	if input.Offsets != (PrayerOffsets{}) {
+		// Aladhan tunes Imsak,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Sunset,Isha,Midnight
+		offsets := input.Offsets
+		query += fmt.Sprintf(
+			"&tune=%d,%d,%d,%d,%d,%d,0,%d,0",
+			offsets.Imsak,
+			offsets.Fajr,
+			offsets.Sunrise,
+			offsets.Dhuhr,
+			offsets.Asr,
+			offsets.Maghrib,
+			offsets.Isha,
+		)
+	}
+	return query
+}
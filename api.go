@@ -0,0 +1,612 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// apiDefaultMethod is the calculation method used when a request has none
+	apiDefaultMethod = 2
+	// apiMaxRangeDays is the longest date range served in one request
+	apiMaxRangeDays = 366
+)
+
+/*
+APIServer is an http.Handler serving prayer schedules as JSON.  Every endpoint takes the location as latitude and
+longitude or as country and postal_code, and the calculation settings, as query parameters.  The API is described
+by the OpenAPI 3 document served at /openapi.json, which is generated from the same route table as the handlers.
+*/
+type APIServer struct {
+	Provider   MonthProvider   // Source of the monthly prayer timings.  Defaults to AladhanProvider
+	Geocoder   Geocoder        // Looks up country and postal code locations.  Defaults to HERE with HEREAPIKey
+	HEREAPIKey string          // Enables country and postal code locations without a Geocoder
+	Magnetic   *MagneticModel  // Adds the magnetic bearing to qibla responses
+	Clock      Clock           // Defaults to SystemClock
+	OnError    func(err error) // Called with the upstream error of a request answered with 502, which only gets a generic message
+}
+
+// apiParameter is a query parameter of an API route
+type apiParameter struct {
+	name        string
+	kind        string // OpenAPI type: string, number, integer or boolean
+	format      string // OpenAPI format, such as date
+	description string
+	required    bool
+	example     string
+}
+
+// apiRoute is an endpoint of the API, used both to serve it and to document it
+type apiRoute struct {
+	path        string
+	operationID string
+	summary     string
+	parameters  []apiParameter
+	response    interface{} // Value of the response type, described in the OpenAPI document
+	handle      func(s *APIServer, ctx context.Context, query url.Values) (interface{}, error)
+}
+
+// apiError is an error with the HTTP status it is served with
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// apiErrorResponse is the body of every error response
+type apiErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// apiPrayerNow is the response of /v1/now
+type apiPrayerNow struct {
+	Time              time.Time `json:"time"` // Time of the request in the timezone of the location
+	CurrentPrayer     string    `json:"current_prayer"`
+	CurrentPrayerTime string    `json:"current_prayer_time"`
+	PreviousDayIsha   bool      `json:"previous_day_isha"` // Whether the current prayer is Isha of the previous day
+	NextPrayer        string    `json:"next_prayer"`
+	NextPrayerTime    string    `json:"next_prayer_time"`
+	SecondsUntilNext  int       `json:"seconds_until_next"`
+}
+
+// apiHijri is the response of /v1/hijri
+type apiHijri struct {
+	Gregorian string    `json:"gregorian"` // YYYY-MM-DD
+	Hijri     HijriDate `json:"hijri"`
+	MonthName string    `json:"month_name"`
+	Text      string    `json:"text"`
+}
+
+var (
+	apiLocationParameters = []apiParameter{
+		{name: "latitude", kind: "number", description: "Latitude of the location.  Required with longitude unless country and postal_code are given", example: "34.103"},
+		{name: "longitude", kind: "number", description: "Longitude of the location", example: "-118.4105"},
+		{name: "country", kind: "string", description: "ISO 3166 country code of the location.  Required with postal_code unless latitude and longitude are given", example: "US"},
+		{name: "postal_code", kind: "string", description: "Postal code of the location", example: "90210"},
+	}
+	apiSettingsParameters = []apiParameter{
+		{name: "method", kind: "integer", description: "Aladhan calculation method.  Defaults to 2, the Islamic Society of North America", example: "2"},
+		{name: "school", kind: "integer", description: "Asr juristic school.  0 for Shafi and 1 for Hanafi", example: "0"},
+		{name: "latitude_adjustment", kind: "integer", description: "High latitude rule.  1 middle of the night, 2 one seventh, 3 angle based"},
+		{name: "tune", kind: "string", description: "Comma separated minutes added to Imsak, Fajr, Sunrise, Dhuhr, Asr, Maghrib and Isha", example: "0,2,0,0,0,3,0"},
+		{name: "hijri_calendar", kind: "string", description: "ummalqura or tabular.  Defaults to ummalqura"},
+		{name: "hijri_adjustment", kind: "integer", description: "Days added to Hijri dates for local moon sighting, between -2 and 2"},
+	}
+	apiDateParameter = apiParameter{name: "date", kind: "string", format: "date", description: "Day in YYYY-MM-DD.  Defaults to today at the location", example: "2022-10-12"}
+)
+
+// apiRoutes are the endpoints of the API
+var apiRoutes = []apiRoute{
+	{
+		path:        "/v1/calendar/month",
+		operationID: "getMonthCalendar",
+		summary:     "Prayer timings of every day of a month",
+		parameters:  joinAPIParameters(apiLocationParameters, []apiParameter{{name: "month", kind: "string", description: "Month in YYYY-MM", required: true, example: "2022-10"}}, apiSettingsParameters),
+		response:    PCalOutput{},
+		handle:      (*APIServer).month,
+	},
+	{
+		path:        "/v1/calendar/range",
+		operationID: "getRangeCalendar",
+		summary:     "Prayer timings of every day of a date range of at most 366 days",
+		parameters: joinAPIParameters(apiLocationParameters, []apiParameter{
+			{name: "start", kind: "string", format: "date", description: "First day in YYYY-MM-DD", required: true, example: "2022-10-01"},
+			{name: "end", kind: "string", format: "date", description: "Last day in YYYY-MM-DD", required: true, example: "2022-12-31"},
+		}, apiSettingsParameters),
+		response: PCalRangeOutput{},
+		handle:   (*APIServer).dateRange,
+	},
+	{
+		path:        "/v1/today",
+		operationID: "getDay",
+		summary:     "Prayer timings of a single day",
+		parameters:  joinAPIParameters(apiLocationParameters, []apiParameter{apiDateParameter}, apiSettingsParameters),
+		response:    PCalDay{},
+		handle:      (*APIServer).today,
+	},
+	{
+		path:        "/v1/now",
+		operationID: "getPrayerNow",
+		summary:     "Current and next prayer",
+		parameters:  joinAPIParameters(apiLocationParameters, apiSettingsParameters),
+		response:    apiPrayerNow{},
+		handle:      (*APIServer).now,
+	},
+	{
+		path:        "/v1/qibla",
+		operationID: "getQibla",
+		summary:     "Qibla bearing and distance, and the times the sun lines up with the qibla",
+		parameters: joinAPIParameters(apiLocationParameters, []apiParameter{
+			apiDateParameter,
+			{name: "timezone", kind: "string", description: "IANA timezone of the returned times.  Defaults to UTC", example: "America/Los_Angeles"},
+		}),
+		response: QiblaOutput{},
+		handle:   (*APIServer).qibla,
+	},
+	{
+		path:        "/v1/hijri",
+		operationID: "getHijriDate",
+		summary:     "Hijri date of a Gregorian day",
+		parameters: []apiParameter{
+			{name: "date", kind: "string", format: "date", description: "Gregorian day in YYYY-MM-DD.  Defaults to today in UTC", example: "2022-10-12"},
+			apiSettingsParameters[4],
+			apiSettingsParameters[5],
+		},
+		response: apiHijri{},
+		handle:   (*APIServer).hijri,
+	},
+}
+
+// joinAPIParameters returns the parameters of every group in order
+func joinAPIParameters(groups ...[]apiParameter) []apiParameter {
+	var parameters []apiParameter
+	for _, group := range groups {
+		parameters = append(parameters, group...)
+	}
+	return parameters
+}
+
+// ServeHTTP serves the API endpoints and the OpenAPI document
+func (s *APIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/openapi.json" {
+		w.Header().Set("Content-Type", "application/json")
+		s.WriteOpenAPI(w)
+		return
+	}
+	for _, route := range apiRoutes {
+		if r.URL.Path != route.path {
+			continue
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeAPIJSON(w, http.StatusMethodNotAllowed, apiErrorResponse{Error: "only GET is allowed"})
+			return
+		}
+		response, err := route.handle(s, r.Context(), r.URL.Query())
+		if apiErr, ok := err.(*apiError); ok {
+			writeAPIJSON(w, apiErr.status, apiErrorResponse{Error: apiErr.message})
+			return
+		}
+		if err != nil {
+			// Upstream errors can carry the details of the upstream requests, such as the HERE API key
+			if s.OnError != nil {
+				s.OnError(err)
+			}
+			writeAPIJSON(w, http.StatusBadGateway, apiErrorResponse{Error: "unable to look up the prayer timings upstream"})
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, response)
+		return
+	}
+	writeAPIJSON(w, http.StatusNotFound, apiErrorResponse{Error: "not found"})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *APIServer) month(ctx context.Context, query url.Values) (interface{}, error) {
+	customer, err := s.customer(query)
+	if err != nil {
+		return nil, err
+	}
+	month, err := time.Parse("2006-01", query.Get("month"))
+	if err != nil {
+		return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("month %q is not YYYY-MM", query.Get("month"))}
+	}
+	customer.CustTime = month
+	return customer.PrayerCalendarContext(ctx)
+}
+
+func (s *APIServer) dateRange(ctx context.Context, query url.Values) (interface{}, error) {
+	customer, err := s.customer(query)
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseAPIDate(query, "start")
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseAPIDate(query, "end")
+	if err != nil {
+		return nil, err
+	}
+	if start.IsZero() || end.IsZero() {
+		return nil, &apiError{http.StatusBadRequest, "start and end are required"}
+	}
+	if end.Before(start) || end.Sub(start) >= apiMaxRangeDays*24*time.Hour {
+		return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("range must be from 1 to %d days", apiMaxRangeDays)}
+	}
+	return customer.PrayerCalendarRangeContext(ctx, start, end, 0)
+}
+
+func (s *APIServer) today(ctx context.Context, query url.Values) (interface{}, error) {
+	customer, err := s.customer(query)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseAPIDate(query, "date")
+	if err != nil {
+		return nil, err
+	}
+	if date.IsZero() {
+		return customer.PrayerDayContext(ctx, clockOrSystem(s.Clock).Now())
+	}
+	days, err := customer.PrayerCalendarRangeContext(ctx, date, date, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(days.Days) != 1 {
+		return nil, fmt.Errorf("no prayer timings were found for %s", date.Format("2006-01-02"))
+	}
+	return days.Days[0], nil
+}
+
+func (s *APIServer) now(ctx context.Context, query url.Values) (interface{}, error) {
+	customer, err := s.customer(query)
+	if err != nil {
+		return nil, err
+	}
+	days, localNow, err := customer.adjacentDays(ctx, clockOrSystem(s.Clock).Now())
+	if err != nil {
+		return nil, err
+	}
+	determined, err := days.prayerNow(localNow)
+	if err != nil {
+		return nil, err
+	}
+	return &apiPrayerNow{
+		Time:              localNow,
+		CurrentPrayer:     determined.CurrentPrayerName,
+		CurrentPrayerTime: determined.CurrentPrayerTime,
+		PreviousDayIsha:   determined.PreviousDayIsha,
+		NextPrayer:        determined.NextPrayerName,
+		NextPrayerTime:    determined.NextPrayerTime,
+		SecondsUntilNext:  int(determined.TimeDiff.Seconds()),
+	}, nil
+}
+
+func (s *APIServer) qibla(ctx context.Context, query url.Values) (interface{}, error) {
+	customer, err := s.customer(query)
+	if err != nil {
+		return nil, err
+	}
+	location := time.UTC
+	if name := query.Get("timezone"); name != "" {
+		if location, err = time.LoadLocation(name); err != nil {
+			return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("timezone %q is unknown", name)}
+		}
+	}
+	date, err := parseAPIDate(query, "date")
+	if err != nil {
+		return nil, err
+	}
+	if date.IsZero() {
+		now := clockOrSystem(s.Clock).Now().In(location)
+		date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location)
+
+	input, err := customer.resolvePCalInput(ctx)
+	if err != nil {
+		return nil, err
+	}
+	coordinates := PrayerCalendarInputCoordinates{Latitude: input.Latitude, Longitude: input.Longitude}
+	return coordinates.Qibla(date, s.Magnetic)
+}
+
+func (s *APIServer) hijri(ctx context.Context, query url.Values) (interface{}, error) {
+	calendar, adjustment, err := parseAPIHijriSettings(query)
+	if err != nil {
+		return nil, err
+	}
+	date, err := parseAPIDate(query, "date")
+	if err != nil {
+		return nil, err
+	}
+	if date.IsZero() {
+		now := clockOrSystem(s.Clock).Now().UTC()
+		date = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	hijri, err := ToHijri(date, calendar, adjustment)
+	if err != nil {
+		return nil, &apiError{http.StatusBadRequest, err.Error()}
+	}
+	return &apiHijri{Gregorian: date.Format("2006-01-02"), Hijri: hijri, MonthName: hijri.MonthName(), Text: hijri.String()}, nil
+}
+
+// customer reads the location and calculation settings of query
+func (s *APIServer) customer(query url.Values) (*CustomerLocationInput, error) {
+	customer := &CustomerLocationInput{Provider: s.Provider, Institution: apiDefaultMethod}
+
+	hasCoordinates := query.Get("latitude") != "" || query.Get("longitude") != ""
+	hasPostalCode := query.Get("country") != "" || query.Get("postal_code") != ""
+	switch {
+	case hasCoordinates && hasPostalCode:
+		return nil, &apiError{http.StatusBadRequest, "give either latitude and longitude or country and postal_code, not both"}
+	case hasCoordinates:
+		latitude, latitudeErr := strconv.ParseFloat(query.Get("latitude"), 32)
+		longitude, longitudeErr := strconv.ParseFloat(query.Get("longitude"), 32)
+		if latitudeErr != nil || longitudeErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			return nil, &apiError{http.StatusBadRequest, "latitude and longitude must be numbers within -90 to 90 and -180 to 180"}
+		}
+		if latitude == 0 || longitude == 0 {
+			return nil, &apiError{http.StatusBadRequest, "latitude and longitude on the equator or the prime meridian are not supported"}
+		}
+		customer.Coordinates = PrayerCalendarInputCoordinates{Latitude: float32(latitude), Longitude: float32(longitude)}
+	case hasPostalCode:
+		if query.Get("country") == "" || query.Get("postal_code") == "" {
+			return nil, &apiError{http.StatusBadRequest, "country and postal_code are both required"}
+		}
+		if s.Geocoder == nil && s.HEREAPIKey == "" {
+			return nil, &apiError{http.StatusBadRequest, "postal code locations are not enabled on this server"}
+		}
+		customer.CountryCode = query.Get("country")
+		customer.PostalCode = query.Get("postal_code")
+		customer.Geocoder = s.Geocoder
+		customer.HEREAPIKey = s.HEREAPIKey
+	default:
+		return nil, &apiError{http.StatusBadRequest, "a location is required: latitude and longitude, or country and postal_code"}
+	}
+
+	integers := []struct {
+		name  string
+		value *int
+	}{
+		{"method", &customer.Institution},
+		{"school", &customer.School},
+		{"latitude_adjustment", &customer.LatitudeAdjustmentMethod},
+	}
+	for _, parameter := range integers {
+		if value := query.Get(parameter.name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("%s %q is not an integer", parameter.name, value)}
+			}
+			*parameter.value = parsed
+		}
+	}
+	if _, ok := CalculationMethods[customer.Institution]; !ok {
+		return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("method %d is unknown", customer.Institution)}
+	}
+	if customer.School != 0 && customer.School != 1 {
+		return nil, &apiError{http.StatusBadRequest, "school must be 0 or 1"}
+	}
+	if customer.LatitudeAdjustmentMethod < 0 || customer.LatitudeAdjustmentMethod > LatitudeAdjustmentAngleBased {
+		return nil, &apiError{http.StatusBadRequest, "latitude_adjustment must be from 0 to 3"}
+	}
+
+	if tune := query.Get("tune"); tune != "" {
+		minutes := strings.Split(tune, ",")
+		if len(minutes) != len(prayerTimingNames) {
+			return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("tune must have %d comma separated minutes", len(prayerTimingNames))}
+		}
+		offsets := []*int{
+			&customer.Offsets.Imsak, &customer.Offsets.Fajr, &customer.Offsets.Sunrise, &customer.Offsets.Dhuhr,
+			&customer.Offsets.Asr, &customer.Offsets.Maghrib, &customer.Offsets.Isha,
+		}
+		for i, value := range minutes {
+			parsed, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, &apiError{http.StatusBadRequest, fmt.Sprintf("tune %q is not minutes", value)}
+			}
+			*offsets[i] = parsed
+		}
+	}
+
+	var err error
+	if customer.HijriCalendar, customer.HijriAdjustment, err = parseAPIHijriSettings(query); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// parseAPIDate parses the YYYY-MM-DD date parameter name, returning the zero time when it is not given
+func parseAPIDate(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, &apiError{http.StatusBadRequest, fmt.Sprintf("%s %q is not YYYY-MM-DD", name, value)}
+	}
+	return date, nil
+}
+
+func parseAPIHijriSettings(query url.Values) (HijriCalendar, int, error) {
+	calendar := UmmAlQura
+	switch strings.ToLower(query.Get("hijri_calendar")) {
+	case "", "ummalqura":
+	case "tabular":
+		calendar = TabularHijri
+	default:
+		return 0, 0, &apiError{http.StatusBadRequest, "hijri_calendar must be ummalqura or tabular"}
+	}
+	adjustment := 0
+	if value := query.Get("hijri_adjustment"); value != "" {
+		var err error
+		if adjustment, err = strconv.Atoi(value); err != nil || checkHijriAdjustment(adjustment) != nil {
+			return 0, 0, &apiError{http.StatusBadRequest, fmt.Sprintf("hijri_adjustment must be from -%d to %d", maxHijriAdjustment, maxHijriAdjustment)}
+		}
+	}
+	return calendar, adjustment, nil
+}
+
+// WriteOpenAPI writes the OpenAPI 3 document of the API as JSON
+func (s *APIServer) WriteOpenAPI(w io.Writer) error {
+	schemas := make(map[string]interface{})
+	errorResponse := map[string]interface{}{
+		"description": "Error",
+		"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": openAPISchema(reflect.TypeOf(apiErrorResponse{}), schemas)}},
+	}
+
+	paths := make(map[string]interface{})
+	for _, route := range apiRoutes {
+		var parameters []interface{}
+		for _, parameter := range route.parameters {
+			schema := map[string]interface{}{"type": parameter.kind}
+			if parameter.format != "" {
+				schema["format"] = parameter.format
+			}
+			definition := map[string]interface{}{
+				"name":        parameter.name,
+				"in":          "query",
+				"required":    parameter.required,
+				"description": parameter.description,
+				"schema":      schema,
+			}
+			if parameter.example != "" {
+				definition["example"] = parameter.example
+			}
+			parameters = append(parameters, definition)
+		}
+		paths[route.path] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": route.operationID,
+				"summary":     route.summary,
+				"parameters":  parameters,
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": route.summary,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{"schema": openAPISchema(reflect.TypeOf(route.response), schemas)},
+						},
+					},
+					"400": errorResponse,
+					"502": errorResponse,
+				},
+			},
+		}
+	}
+
+	document := map[string]interface{}{
+		"openapi":    "3.0.3",
+		"info":       map[string]interface{}{"title": "Prayer Schedule API", "version": "1.0.0"},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+/*
+openAPISchema returns the OpenAPI schema of how encoding/json encodes t.  Named structs are added to schemas, with
+the leading api of unexported response types dropped from their names, and referred to.
+*/
+func openAPISchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "nullable": true, "items": openAPISchema(t.Elem(), schemas)}
+	case reflect.Array:
+		return map[string]interface{}{"type": "array", "minItems": t.Len(), "maxItems": t.Len(), "items": openAPISchema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": openAPISchema(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return openAPIObject(t, schemas)
+		}
+		name := t.Name()
+		if strings.HasPrefix(name, "api") {
+			name = strings.ToUpper(name[3:4]) + name[4:]
+		}
+		if _, ok := schemas[name]; !ok {
+			// The placeholder stops types which refer to themselves from recursing forever
+			schemas[name] = nil
+			schemas[name] = openAPIObject(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// openAPIObject returns the object schema of the exported fields of the struct t
+func openAPIObject(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = openAPISchema(field.Type, schemas)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	object := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
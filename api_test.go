@@ -0,0 +1,342 @@
+package schedule_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// recordingMonthProvider records the last input it was asked for
+type recordingMonthProvider struct {
+	testMonthProvider
+	mu    sync.Mutex
+	input psched.PCalInput
+}
+
+func (p *recordingMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.mu.Lock()
+	p.input = *input
+	p.mu.Unlock()
+	return p.testMonthProvider.MonthlyPrayers(ctx, input)
+}
+
+func apiTestServer(t *testing.T) (*httptest.Server, *recordingMonthProvider) {
+	t.Helper()
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &recordingMonthProvider{testMonthProvider: testMonthProvider{location: location}}
+	server := httptest.NewServer(&psched.APIServer{
+		Provider: provider,
+		Geocoder: &testGeocoder{},
+		// 20:00 on the 12th of October in Los Angeles
+		Clock: psched.NewFakeClock(time.Date(2022, time.October, 13, 3, 0, 0, 0, time.UTC)),
+	})
+	t.Cleanup(server.Close)
+	return server, provider
+}
+
+// apiGet requests path and decodes the JSON response into response
+func apiGet(t *testing.T, server *httptest.Server, path string, response interface{}) int {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("%s returned content type %s", path, resp.Header.Get("Content-Type"))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Fatalf("unable to decode %s: %s", path, err)
+	}
+	return resp.StatusCode
+}
+
+func TestAPIServerEndpoints(t *testing.T) {
+	server, provider := apiTestServer(t)
+	location := "latitude=34.103&longitude=-118.4105"
+
+	month := new(psched.PCalOutput)
+	if status := apiGet(t, server, "/v1/calendar/month?"+location+"&month=2022-10&method=3&school=1&latitude_adjustment=1&tune=0,2,0,0,0,3,-1", month); status != http.StatusOK {
+		t.Fatalf("month returned %d", status)
+	}
+	if len(month.Data) != 31 || month.Data[11].Hijri != (psched.HijriDate{Year: 1444, Month: 3, Day: 16}) {
+		t.Errorf("unexpected month with %d days", len(month.Data))
+	}
+	expectedOffsets := psched.PrayerOffsets{Fajr: 2, Maghrib: 3, Isha: -1}
+	if provider.input.Institution != 3 || provider.input.School != 1 || provider.input.LatitudeAdjustmentMethod != 1 || provider.input.Offsets != expectedOffsets {
+		t.Errorf("settings were not passed to the provider: %+v", provider.input)
+	}
+
+	days := new(psched.PCalRangeOutput)
+	if status := apiGet(t, server, "/v1/calendar/range?"+location+"&start=2022-10-30&end=2022-11-02", days); status != http.StatusOK || len(days.Days) != 4 {
+		t.Errorf("range returned %d with %d days", status, len(days.Days))
+	}
+	if provider.input.Institution != 2 {
+		t.Errorf("expected the default method 2, got %d", provider.input.Institution)
+	}
+
+	today := new(psched.PCalDay)
+	if status := apiGet(t, server, "/v1/today?"+location, today); status != http.StatusOK || today.Date.Gregorian.Date != "12-10-2022" {
+		t.Errorf("today returned %d with %s", status, today.Date.Gregorian.Date)
+	}
+	if status := apiGet(t, server, "/v1/today?country=US&postal_code=90210&date=2022-11-05", today); status != http.StatusOK || today.Date.Gregorian.Date != "05-11-2022" {
+		t.Errorf("today by postal code returned %d with %s", status, today.Date.Gregorian.Date)
+	}
+
+	var now struct {
+		Time             time.Time `json:"time"`
+		CurrentPrayer    string    `json:"current_prayer"`
+		NextPrayer       string    `json:"next_prayer"`
+		NextPrayerTime   string    `json:"next_prayer_time"`
+		SecondsUntilNext int       `json:"seconds_until_next"`
+	}
+	if status := apiGet(t, server, "/v1/now?"+location, &now); status != http.StatusOK {
+		t.Fatalf("now returned %d", status)
+	}
+	if now.CurrentPrayer != "Isha" || now.NextPrayer != "Fajr" || now.NextPrayerTime != "05:22 (PDT)" || now.SecondsUntilNext != (9*60+22)*60 || now.Time.Format("15:04 -0700") != "20:00 -0700" {
+		t.Errorf("unexpected current prayer: %+v", now)
+	}
+
+	var qibla psched.QiblaOutput
+	if status := apiGet(t, server, "/v1/qibla?"+location+"&date=2022-10-12&timezone=America/Los_Angeles", &qibla); status != http.StatusOK {
+		t.Fatalf("qibla returned %d", status)
+	}
+	if qibla.Bearing < 23 || qibla.Bearing > 24 || len(qibla.SunAwayFromQibla) != 1 || qibla.SunAwayFromQibla[0].Format("2006-01-02 -0700") != "2022-10-12 -0700" {
+		t.Errorf("unexpected qibla: %+v", qibla)
+	}
+
+	var hijri struct {
+		Hijri psched.HijriDate `json:"hijri"`
+		Text  string           `json:"text"`
+	}
+	if status := apiGet(t, server, "/v1/hijri?date=2022-10-12&hijri_adjustment=1", &hijri); status != http.StatusOK || hijri.Text != "17 Rabi al-Awwal 1444 AH" {
+		t.Errorf("hijri returned %d with %+v", status, hijri)
+	}
+}
+
+func TestAPIServerOneLookupPerRequest(t *testing.T) {
+	for _, path := range []string{"/v1/now", "/v1/today"} {
+		provider := &testMonthProvider{}
+		geocoder := &testGeocoder{}
+		server := httptest.NewServer(&psched.APIServer{
+			Provider: provider,
+			Geocoder: geocoder,
+			Clock:    psched.NewFakeClock(time.Date(2022, time.October, 13, 3, 0, 0, 0, time.UTC)),
+		})
+
+		var response map[string]interface{}
+		if status := apiGet(t, server, path+"?country=US&postal_code=90210", &response); status != http.StatusOK {
+			t.Errorf("%s returned %d", path, status)
+		}
+		if provider.Calls() != 1 || geocoder.calls != 1 {
+			t.Errorf("%s made %d month lookups and %d geocodes, want 1 of each", path, provider.Calls(), geocoder.calls)
+		}
+		server.Close()
+	}
+}
+
+func TestAPIServerQiblaToday(t *testing.T) {
+	model, err := psched.DefaultMagneticModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, today := range []time.Time{
+		time.Date(2022, time.October, 12, 19, 0, 0, 0, time.UTC),
+		time.Date(2026, time.October, 19, 19, 0, 0, 0, time.UTC),
+	} {
+		server := httptest.NewServer(&psched.APIServer{
+			Provider: &testMonthProvider{},
+			Magnetic: model,
+			Clock:    psched.NewFakeClock(today),
+		})
+
+		// Today's qibla succeeds whether or not the magnetic model covers today
+		var qibla psched.QiblaOutput
+		if status := apiGet(t, server, "/v1/qibla?latitude=34.103&longitude=-118.4105", &qibla); status != http.StatusOK {
+			t.Fatalf("qibla for %s returned %d", today.Format("2006-01-02"), status)
+		}
+		server.Close()
+		if qibla.HasMagnetic != model.Covers(today) {
+			t.Errorf("qibla for %s has magnetic bearing %v, want %v", today.Format("2006-01-02"), qibla.HasMagnetic, model.Covers(today))
+		}
+		if qibla.Bearing < 23 || qibla.Bearing > 24 {
+			t.Errorf("unexpected qibla bearing %.2f", qibla.Bearing)
+		}
+	}
+}
+
+// failingGeocoder fails every lookup with an error carrying the upstream request, as a HERE error could
+type failingGeocoder struct {
+	apiKey string
+}
+
+func (g failingGeocoder) Geocode(ctx context.Context, countryCode string, postalCode string) (*psched.CustomerCoordinatesOutput, error) {
+	return nil, fmt.Errorf("lookup of %s %s with API key %s failed", countryCode, postalCode, g.apiKey)
+}
+
+func TestAPIServerUpstreamErrorHidden(t *testing.T) {
+	logged := make(chan error, 1)
+	server := httptest.NewServer(&psched.APIServer{
+		Provider:   &testMonthProvider{},
+		Geocoder:   failingGeocoder{apiKey: "SUPERSECRETKEY"},
+		HEREAPIKey: "SUPERSECRETKEY",
+		OnError:    func(err error) { logged <- err },
+	})
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/today?country=US&postal_code=10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("failed upstream lookup returned %d, want %d", resp.StatusCode, http.StatusBadGateway)
+	}
+	if strings.Contains(string(body), "SUPERSECRETKEY") {
+		t.Errorf("response body contains the API key: %s", body)
+	}
+	if err := <-logged; !strings.Contains(err.Error(), "SUPERSECRETKEY") {
+		t.Errorf("upstream error was not passed to OnError: %s", err)
+	}
+}
+
+// cancelledMonthProvider holds every lookup until its context is done
+type cancelledMonthProvider struct {
+	started   chan struct{}
+	cancelled chan struct{}
+}
+
+func (p *cancelledMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	close(p.started)
+	<-ctx.Done()
+	close(p.cancelled)
+	return nil, ctx.Err()
+}
+
+func TestAPIServerRequestContext(t *testing.T) {
+	provider := &cancelledMonthProvider{started: make(chan struct{}), cancelled: make(chan struct{})}
+	server := httptest.NewServer(&psched.APIServer{Provider: provider})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/calendar/month?latitude=34.103&longitude=-118.4105&month=2022-10", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		if resp, err := http.DefaultClient.Do(request); err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	// The client giving up cancels the upstream lookup of its request
+	<-provider.started
+	cancel()
+	select {
+	case <-provider.cancelled:
+	case <-time.After(10 * time.Second):
+		t.Fatal("upstream lookup was not cancelled with the request")
+	}
+}
+
+func TestAPIServerErrors(t *testing.T) {
+	server, _ := apiTestServer(t)
+	for path, expected := range map[string]int{
+		"/v1/today":                                 http.StatusBadRequest,
+		"/v1/today?latitude=34.1&country=US":        http.StatusBadRequest,
+		"/v1/today?latitude=134.1&longitude=-118.4": http.StatusBadRequest,
+		"/v1/today?country=US":                      http.StatusBadRequest,
+		"/v1/calendar/month?latitude=34.1&longitude=-118.4&month=October":                   http.StatusBadRequest,
+		"/v1/calendar/range?latitude=34.1&longitude=-118.4&start=2022-01-01&end=2023-06-01": http.StatusBadRequest,
+		"/v1/calendar/range?latitude=34.1&longitude=-118.4&start=2022-01-01":                http.StatusBadRequest,
+		"/v1/now?latitude=34.1&longitude=-118.4&method=6":                                   http.StatusBadRequest,
+		"/v1/now?latitude=34.1&longitude=-118.4&tune=1,2":                                   http.StatusBadRequest,
+		"/v1/hijri?hijri_calendar=lunar":                                                    http.StatusBadRequest,
+		"/v1/qibla?latitude=34.1&longitude=-118.4&timezone=Mars/Olympus_Mons":               http.StatusBadRequest,
+		"/v1/missing": http.StatusNotFound,
+	} {
+		var response struct {
+			Error string `json:"error"`
+		}
+		if status := apiGet(t, server, path, &response); status != expected || response.Error == "" {
+			t.Errorf("%s returned %d %q, expected %d", path, status, response.Error, expected)
+		}
+	}
+
+	resp, err := http.Post(server.URL+"/v1/hijri", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST returned %d", resp.StatusCode)
+	}
+}
+
+func TestAPIServerOpenAPI(t *testing.T) {
+	server, _ := apiTestServer(t)
+	var document struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]struct {
+			Get struct {
+				Parameters []struct {
+					Name string `json:"name"`
+				} `json:"parameters"`
+				Responses map[string]json.RawMessage `json:"responses"`
+			} `json:"get"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	resp, err := http.Get(server.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	contents := new(strings.Builder)
+	if err := json.NewDecoder(io.TeeReader(resp.Body, contents)).Decode(&document); err != nil {
+		t.Fatal(err)
+	}
+
+	if document.OpenAPI != "3.0.3" || len(document.Paths) != 6 {
+		t.Fatalf("unexpected document: openapi %s with %d paths", document.OpenAPI, len(document.Paths))
+	}
+	parameters := make(map[string]bool)
+	for _, parameter := range document.Paths["/v1/calendar/month"].Get.Parameters {
+		parameters[parameter.Name] = true
+	}
+	for _, name := range []string{"latitude", "longitude", "country", "postal_code", "month", "method", "school", "latitude_adjustment", "tune"} {
+		if !parameters[name] {
+			t.Errorf("month calendar does not document %s", name)
+		}
+	}
+	for _, name := range []string{"PCalOutput", "PCalDay", "FiveDailyPrayers", "HijriDate", "IslamicEvent", "PrayerNow", "QiblaOutput", "ErrorResponse"} {
+		if _, ok := document.Components.Schemas[name]; !ok {
+			t.Errorf("schema %s is missing", name)
+		}
+	}
+	// Every reference resolves to a schema
+	for _, reference := range strings.Split(contents.String(), `"$ref": "#/components/schemas/`)[1:] {
+		name := reference[:strings.Index(reference, `"`)]
+		if _, ok := document.Components.Schemas[name]; !ok {
+			t.Errorf("reference to missing schema %s", name)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// maxGeohashPrecision is the longest geohash, about 3.7cm by 1.9cm
+const maxGeohashPrecision = 12
+
+/*
+BucketPolicy snaps coordinates to the center of a spatial bucket so nearby customers share one prayer calendar.
+Buckets are either a grid of GridKm square cells or geohash cells of GeohashPrecision characters.  Exactly one of
+the two must be set.
+*/
+type BucketPolicy struct {
+	GridKm           float64 // Side of a grid cell in km
+	GeohashPrecision int     // Number of geohash characters, from 1 to 12
+}
+
+// Bucket returns the center of the bucket containing the coordinates
+func (b BucketPolicy) Bucket(latitude float32, longitude float32) (float32, float32, error) {
+	if err := b.validate(); err != nil {
+		return 0, 0, err
+	}
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return 0, 0, fmt.Errorf("coordinates are out of range: %v, %v", latitude, longitude)
+	}
+
+	var minLatitude, maxLatitude, minLongitude, maxLongitude float64
+	if b.GridKm > 0 {
+		minLatitude, maxLatitude, minLongitude, maxLongitude = b.gridCell(float64(latitude), float64(longitude))
+	} else {
+		minLatitude, maxLatitude, minLongitude, maxLongitude = geohashCell(float64(latitude), float64(longitude), b.GeohashPrecision)
+	}
+	return float32((minLatitude + maxLatitude) / 2), float32((minLongitude + maxLongitude) / 2), nil
+}
+
+/*
+MaxTimeError returns the largest difference over a year between a prayer time anywhere in a bucket at latitude and
+the prayer time at the bucket center.  Sunrise, sunset, both Asr schools and twilight angles from 12 to 19.5 degrees
+are considered.  Near the poles, where the sun does not reach some of those angles, only the remaining times count.
+Around the latitude where the sun only just reaches a twilight angle the times move quickly with latitude, so even
+small buckets there report errors of minutes.
+*/
+func (b BucketPolicy) MaxTimeError(latitude float64) (time.Duration, error) {
+	if err := b.validate(); err != nil {
+		return 0, err
+	}
+	if latitude < -90 || latitude > 90 {
+		return 0, fmt.Errorf("latitude is out of range: %v", latitude)
+	}
+
+	var minLatitude, maxLatitude, minLongitude, maxLongitude float64
+	if b.GridKm > 0 {
+		minLatitude, maxLatitude, minLongitude, maxLongitude = b.gridCell(latitude, 0)
+	} else {
+		minLatitude, maxLatitude, minLongitude, maxLongitude = geohashCell(latitude, 0, b.GeohashPrecision)
+	}
+	center := (minLatitude + maxLatitude) / 2
+	// Solar time moves by 4 minutes for every degree of longitude
+	longitudeMinutes := (maxLongitude - minLongitude) / 2 * 4
+
+	altitudes := []func(latitude float64, declination float64) float64{
+		func(float64, float64) float64 { return -0.833 },
+		func(float64, float64) float64 { return -12 },
+		func(float64, float64) float64 { return -15 },
+		func(float64, float64) float64 { return -18 },
+		func(float64, float64) float64 { return -19.5 },
+		func(latitude float64, declination float64) float64 { return asrAltitude(1, latitude, declination) },
+		func(latitude float64, declination float64) float64 { return asrAltitude(2, latitude, declination) },
+	}
+
+	latitudeMinutes := 0.0
+	for day := 0; day < 366; day += 2 {
+		declination, _ := sunPosition(2451545.0 + float64(day))
+		for _, altitude := range altitudes {
+			centerHours, ok := hourAngleHours(center, declination, altitude(center, declination))
+			if !ok {
+				continue
+			}
+			for _, edge := range []float64{minLatitude, maxLatitude} {
+				edge = math.Max(-89.999, math.Min(89.999, edge))
+				edgeHours, ok := hourAngleHours(edge, declination, altitude(edge, declination))
+				if ok {
+					latitudeMinutes = math.Max(latitudeMinutes, math.Abs(edgeHours-centerHours)*60)
+				}
+			}
+		}
+	}
+	return time.Duration((latitudeMinutes + longitudeMinutes) * float64(time.Minute)).Round(time.Second), nil
+}
+
+func (b BucketPolicy) validate() error {
+	if b.GridKm > 0 && b.GeohashPrecision > 0 {
+		return fmt.Errorf("bucket policy cannot set both a grid and a geohash precision")
+	}
+	if b.GridKm <= 0 && (b.GeohashPrecision <= 0 || b.GeohashPrecision > maxGeohashPrecision) {
+		return fmt.Errorf("bucket policy requires a positive grid size or a geohash precision from 1 to %d", maxGeohashPrecision)
+	}
+	return nil
+}
+
+// gridCell returns the bounds of the grid cell containing the coordinates.  Cells are GridKm tall and as close to
+// GridKm wide as a whole number of cells around their row of latitude allows
+func (b BucketPolicy) gridCell(latitude float64, longitude float64) (float64, float64, float64, float64) {
+	cellLatitude := b.GridKm / (earthRadiusKm * math.Pi / 180)
+	row := math.Floor(latitude / cellLatitude)
+	minLatitude := math.Max(-90, row*cellLatitude)
+	maxLatitude := math.Min(90, (row+1)*cellLatitude)
+
+	rowWidthKm := 2 * math.Pi * earthRadiusKm * dcos((minLatitude+maxLatitude)/2)
+	cells := math.Max(1, math.Floor(rowWidthKm/b.GridKm))
+	cellLongitude := 360 / cells
+	column := math.Min(cells-1, math.Floor((longitude+180)/cellLongitude))
+	return minLatitude, maxLatitude, -180 + column*cellLongitude, -180 + (column+1)*cellLongitude
+}
+
+// geohashCell returns the bounds of the geohash cell of precision characters containing the coordinates
+func geohashCell(latitude float64, longitude float64, precision int) (float64, float64, float64, float64) {
+	minLatitude, maxLatitude := -90.0, 90.0
+	minLongitude, maxLongitude := -180.0, 180.0
+	// Geohash bits alternate between longitude and latitude, starting with longitude
+	for bit := 0; bit < precision*5; bit++ {
+		if bit%2 == 0 {
+			middle := (minLongitude + maxLongitude) / 2
+			if longitude >= middle {
+				minLongitude = middle
+			} else {
+				maxLongitude = middle
+			}
+		} else {
+			middle := (minLatitude + maxLatitude) / 2
+			if latitude >= middle {
+				minLatitude = middle
+			} else {
+				maxLatitude = middle
+			}
+		}
+	}
+	return minLatitude, maxLatitude, minLongitude, maxLongitude
+}
+
+// hourAngleHours returns the hours between solar noon and the sun reaching altitude, and false when it never does
+func hourAngleHours(latitude float64, declination float64, altitude float64) (float64, bool) {
+	cosine := (dsin(altitude) - dsin(latitude)*dsin(declination)) / (dcos(latitude) * dcos(declination))
+	if cosine < -1 || cosine > 1 {
+		return 0, false
+	}
+	return darccos(cosine) / 15, true
+}
+
+// asrAltitude returns the altitude of the sun when shadows are shadowFactor times their length plus the noon shadow
+func asrAltitude(shadowFactor float64, latitude float64, declination float64) float64 {
+	return darctan2(1, shadowFactor+dtan(math.Abs(latitude-declination)))
+}
+
+// BucketedProvider is a MonthProvider which looks up every month at the center of the bucket of the input coordinates.
+// Put it in front of a CachedProvider or DiskCachedProvider so the whole bucket shares one cache entry.
+type BucketedProvider struct {
+	Provider MonthProvider
+	Policy   BucketPolicy
+}
+
+// MonthlyPrayers returns the month of input at the center of its bucket
+func (p *BucketedProvider) MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error) {
+	latitude, longitude, err := p.Policy.Bucket(input.Latitude, input.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	bucketInput := *input
+	bucketInput.Latitude = latitude
+	bucketInput.Longitude = longitude
+	return p.Provider.MonthlyPrayers(ctx, &bucketInput)
+}
@@ -0,0 +1,102 @@
+package schedule_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// coordinateMonthProvider records the coordinates it was asked for
+type coordinateMonthProvider struct {
+	testMonthProvider
+	latitude, longitude float32
+}
+
+func (p *coordinateMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.latitude, p.longitude = input.Latitude, input.Longitude
+	return p.testMonthProvider.MonthlyPrayers(ctx, input)
+}
+
+func TestBucketPolicyGrid(t *testing.T) {
+	policy := psched.BucketPolicy{GridKm: 5}
+	latitude, longitude, err := policy.Bucket(34.103, -118.4105)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nearbyLatitude, nearbyLongitude, _ := policy.Bucket(34.104, -118.4125)
+	if latitude != nearbyLatitude || longitude != nearbyLongitude {
+		t.Errorf("nearby coordinates are in different buckets: %v,%v and %v,%v", latitude, longitude, nearbyLatitude, nearbyLongitude)
+	}
+	if latitude-34.103 > 0.03 || 34.103-latitude > 0.03 || longitude+118.4105 > 0.04 || -118.4105-longitude > 0.04 {
+		t.Errorf("bucket center %v,%v is far from the coordinates", latitude, longitude)
+	}
+	farLatitude, farLongitude, _ := policy.Bucket(34.2, -118.4105)
+	if latitude == farLatitude && longitude == farLongitude {
+		t.Error("coordinates 10km apart are in the same bucket")
+	}
+}
+
+func TestBucketPolicyGeohash(t *testing.T) {
+	// 57.64911,10.40744 is in geohash u4pru, which spans 57.6123 to 57.6563 and 10.3711 to 10.4150
+	latitude, longitude, err := psched.BucketPolicy{GeohashPrecision: 5}.Bucket(57.64911, 10.40744)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latitude != 57.634277 || longitude != 10.393066 {
+		t.Errorf("unexpected geohash center: %v,%v", latitude, longitude)
+	}
+}
+
+func TestBucketPolicyMaxTimeError(t *testing.T) {
+	small, err := psched.BucketPolicy{GridKm: 1}.MaxTimeError(34)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := psched.BucketPolicy{GridKm: 50}.MaxTimeError(34)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small > 30*time.Second {
+		t.Errorf("1km buckets at 34 degrees should be within 30 seconds, got %s", small)
+	}
+	if large < time.Minute || large > 5*time.Minute {
+		t.Errorf("50km buckets at 34 degrees should be within 1 to 5 minutes, got %s", large)
+	}
+	geohash, err := psched.BucketPolicy{GeohashPrecision: 6}.MaxTimeError(34)
+	if err != nil || geohash > small*2 {
+		t.Errorf("precision 6 geohash buckets should be close to 1km buckets, got %s %v", geohash, err)
+	}
+
+	for _, policy := range []psched.BucketPolicy{{}, {GridKm: 1, GeohashPrecision: 5}, {GeohashPrecision: 13}} {
+		if _, err := policy.MaxTimeError(34); err == nil {
+			t.Errorf("invalid policy %+v did not return an error", policy)
+		}
+	}
+}
+
+func TestBucketedProvider(t *testing.T) {
+	upstream := &coordinateMonthProvider{}
+	cache, err := psched.NewCachedProvider(upstream, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &psched.BucketedProvider{Provider: cache, Policy: psched.BucketPolicy{GridKm: 5}}
+
+	first := cacheTestInput(time.October)
+	second := cacheTestInput(time.October)
+	second.Latitude, second.Longitude = 34.104, -118.4125
+	for _, input := range []*psched.PCalInput{first, second} {
+		if _, err := provider.MonthlyPrayers(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if upstream.Calls() != 1 {
+		t.Errorf("expected nearby customers to share 1 provider call, got %d", upstream.Calls())
+	}
+	latitude, longitude, _ := provider.Policy.Bucket(first.Latitude, first.Longitude)
+	if upstream.latitude != latitude || upstream.longitude != longitude {
+		t.Errorf("provider was not asked for the bucket center: %v,%v", upstream.latitude, upstream.longitude)
+	}
+}
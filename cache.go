@@ -0,0 +1,220 @@
+package schedule
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultCoordinatePrecision is the number of decimal places coordinates are rounded to in cache keys, about 110m
+const defaultCoordinatePrecision = 3
+
+// defaultCacheSize is the number of entries held by the zero value of a CachedProvider or CachedGeocoder
+const defaultCacheSize = 1024
+
+// DefaultCachedLookupTimeout is the longest a lookup shared by the callers of a CachedProvider is given
+const DefaultCachedLookupTimeout = 30 * time.Second
+
+/*
+CachedProvider is a MonthProvider which keeps the most recently used months of another provider in memory.
+Months are keyed by the rounded coordinates, month and calculation settings of the input, so nearby customers share
+an entry.  Concurrent lookups of the same month which is not cached are sent to the provider once.  The zero value
+with a Provider holds 1024 months which never expire.
+*/
+type CachedProvider struct {
+	Provider            MonthProvider
+	CoordinatePrecision int           // Decimal places coordinates are rounded to.  Defaults to 3
+	Clock               Clock         // Time months are stored and expire at.  Defaults to SystemClock
+	Timeout             time.Duration // Longest a lookup from the provider is given.  Defaults to DefaultCachedLookupTimeout
+
+	cache lruCache
+}
+
+// NewCachedProvider returns a CachedProvider holding at most size months of provider for ttl.  A ttl of 0 never expires months
+func NewCachedProvider(provider MonthProvider, size int, ttl time.Duration) (*CachedProvider, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("cached provider requires a provider")
+	}
+	if err := validateCacheSettings(size, ttl); err != nil {
+		return nil, err
+	}
+	return &CachedProvider{Provider: provider, cache: lruCache{size: size, ttl: ttl}}, nil
+}
+
+// MonthlyPrayers returns the cached month of input, looking it up from the provider when it is missing or expired
+func (c *CachedProvider) MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error) {
+	output, err := c.cache.lookup(ctx, c.key(input), c.Clock, c.Timeout, func(lookupCtx context.Context) (interface{}, error) {
+		output, err := c.Provider.MonthlyPrayers(lookupCtx, input)
+		if err != nil {
+			return nil, err
+		}
+		return clonePCalOutput(output), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clonePCalOutput(output.(*PCalOutput)), nil
+}
+
+// Stale returns the month of input even when it has expired, for use when the provider is unavailable
+func (c *CachedProvider) Stale(input *PCalInput) (*PCalOutput, bool) {
+	output, ok := c.cache.stale(c.key(input))
+	if !ok {
+		return nil, false
+	}
+	return clonePCalOutput(output.(*PCalOutput)), true
+}
+
+// Len returns the number of months held, including expired months which have not been evicted yet
+func (c *CachedProvider) Len() int {
+	return c.cache.len()
+}
+
+// key returns the cache key of the month of input
+func (c *CachedProvider) key(input *PCalInput) string {
+	precision := c.CoordinatePrecision
+	if precision <= 0 {
+		precision = defaultCoordinatePrecision
+	}
+	return fmt.Sprintf(
+		"%.*f,%.*f|%04d-%02d|%s|%d|%d|%d|%+v",
+		precision, input.Latitude,
+		precision, input.Longitude,
+		input.CustTime.Year(), input.CustTime.Month(),
+		input.CustTime.Location(),
+		input.Institution,
+		input.School,
+		input.LatitudeAdjustmentMethod,
+		input.Offsets,
+	)
+}
+
+// clonePCalOutput copies output so callers setting hijri dates or events do not change the cached month
+func clonePCalOutput(output *PCalOutput) *PCalOutput {
+	clone := *output
+	clone.Data = make([]PCalDay, len(output.Data))
+	copy(clone.Data, output.Data)
+	for i := range clone.Data {
+		if clone.Data[i].Events != nil {
+			clone.Data[i].Events = append([]IslamicEvent(nil), clone.Data[i].Events...)
+		}
+	}
+	return &clone
+}
+
+// lruCache holds the most recently used values of a cache, looking up missing values once for concurrent callers.
+// The zero value holds defaultCacheSize values which never expire
+type lruCache struct {
+	size    int
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used entry first
+	group   singleflight.Group
+}
+
+type cacheEntry struct {
+	key    string
+	value  interface{}
+	stored time.Time
+}
+
+// validateCacheSettings checks the size and ttl given to the constructor of a cache
+func validateCacheSettings(size int, ttl time.Duration) error {
+	if size <= 0 {
+		return fmt.Errorf("cache size must be positive, got %d", size)
+	}
+	if ttl < 0 {
+		return fmt.Errorf("cache ttl must not be negative, got %s", ttl)
+	}
+	return nil
+}
+
+// lookup returns the value of key, calling fetch when it is missing or expired.  Values must not be changed once stored
+func (l *lruCache) lookup(ctx context.Context, key string, clock Clock, timeout time.Duration, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if value, ok := l.get(key, clock); ok {
+		return value, nil
+	}
+
+	// The lookup is shared by every caller of the same key, so it runs on its own context rather than that of the
+	// caller which started it.  Each caller can still give up on its own context
+	lookup := l.group.DoChan(key, func() (interface{}, error) {
+		if timeout <= 0 {
+			timeout = DefaultCachedLookupTimeout
+		}
+		lookupCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		value, err := fetch(lookupCtx)
+		if err != nil {
+			return nil, err
+		}
+		l.put(key, value, clock)
+		return value, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-lookup:
+		return result.Val, result.Err
+	}
+}
+
+func (l *lruCache) get(key string, clock Clock) (interface{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if l.ttl > 0 && clockOrSystem(clock).Now().Sub(entry.stored) >= l.ttl {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// stale returns the value of key even when it has expired
+func (l *lruCache) stale(key string) (interface{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return element.Value.(*cacheEntry).value, true
+}
+
+func (l *lruCache) put(key string, value interface{}, clock Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.entries == nil {
+		l.entries = make(map[string]*list.Element)
+		l.order = list.New()
+	}
+	if l.size <= 0 {
+		l.size = defaultCacheSize
+	}
+	entry := &cacheEntry{key: key, value: value, stored: clockOrSystem(clock).Now()}
+	if element, ok := l.entries[key]; ok {
+		element.Value = entry
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (l *lruCache) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
@@ -0,0 +1,272 @@
+package schedule_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// blockingMonthProvider holds every lookup until release is closed
+type blockingMonthProvider struct {
+	testMonthProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.started <- struct{}{}
+	<-p.release
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.testMonthProvider.MonthlyPrayers(ctx, input)
+}
+
+func cacheTestInput(month time.Month) *psched.PCalInput {
+	return &psched.PCalInput{
+		CustTime:    time.Date(2022, month, 15, 12, 0, 0, 0, time.UTC),
+		Institution: 2,
+		Latitude:    34.103,
+		Longitude:   -118.4105,
+	}
+}
+
+func TestCachedProviderHit(t *testing.T) {
+	provider := &testMonthProvider{}
+	cache, err := psched.NewCachedProvider(provider, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nearby coordinates round to the same key
+	nearby := cacheTestInput(time.October)
+	nearby.Latitude += 0.0001
+	nearby.CustTime = nearby.CustTime.AddDate(0, 0, 3)
+	second, err := cache.MonthlyPrayers(context.Background(), nearby)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Calls() != 1 {
+		t.Errorf("expected 1 provider call, got %d", provider.Calls())
+	}
+	if second.Data[0].Timings != first.Data[0].Timings {
+		t.Errorf("cached month differs: %+v %+v", first.Data[0].Timings, second.Data[0].Timings)
+	}
+
+	// Returned months are copies
+	first.Data[0].Timings.Fajr = "changed"
+	third, _ := cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if third.Data[0].Timings.Fajr == "changed" {
+		t.Error("changing a returned month changed the cached month")
+	}
+
+	settings := []func(*psched.PCalInput){
+		func(input *psched.PCalInput) { input.Institution = 3 },
+		func(input *psched.PCalInput) { input.School = 1 },
+		func(input *psched.PCalInput) { input.LatitudeAdjustmentMethod = 3 },
+		func(input *psched.PCalInput) { input.Offsets.Isha = 5 },
+		func(input *psched.PCalInput) { input.CustTime = input.CustTime.AddDate(0, 1, 0) },
+	}
+	for i, setting := range settings {
+		input := cacheTestInput(time.October)
+		setting(input)
+		if _, err := cache.MonthlyPrayers(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+		if provider.Calls() != i+2 {
+			t.Errorf("setting %d shared a cache entry", i)
+		}
+	}
+}
+
+func TestCachedProviderEviction(t *testing.T) {
+	provider := &testMonthProvider{}
+	cache, err := psched.NewCachedProvider(provider, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, month := range []time.Month{time.January, time.February, time.January, time.March} {
+		if _, err := cache.MonthlyPrayers(context.Background(), cacheTestInput(month)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 cached months, got %d", cache.Len())
+	}
+	calls := provider.Calls()
+
+	// February was least recently used when March was added
+	cache.MonthlyPrayers(context.Background(), cacheTestInput(time.January))
+	if provider.Calls() != calls {
+		t.Error("january was evicted")
+	}
+	cache.MonthlyPrayers(context.Background(), cacheTestInput(time.February))
+	if provider.Calls() != calls+1 {
+		t.Error("february was not evicted")
+	}
+}
+
+func TestCachedProviderExpiry(t *testing.T) {
+	provider := &testMonthProvider{}
+	cache, err := psched.NewCachedProvider(provider, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 0, 0, 0, time.UTC))
+	cache.Clock = clock
+
+	cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	clock.Advance(time.Hour - time.Second)
+	cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if provider.Calls() != 1 {
+		t.Fatalf("expected 1 provider call before expiry, got %d", provider.Calls())
+	}
+	clock.Advance(time.Second)
+	cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if provider.Calls() != 2 {
+		t.Errorf("expected 2 provider calls after expiry, got %d", provider.Calls())
+	}
+}
+
+func TestCachedProviderSingleflight(t *testing.T) {
+	provider := &blockingMonthProvider{started: make(chan struct{}, 10), release: make(chan struct{})}
+	cache, err := psched.NewCachedProvider(provider, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+			errs <- err
+		}()
+	}
+
+	// Callers arriving after the release find the month in flight or stored, so the provider is called once either way
+	<-provider.started
+	close(provider.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if provider.Calls() != 1 {
+		t.Errorf("expected 1 provider call for %d concurrent callers, got %d", callers, provider.Calls())
+	}
+}
+
+func TestCachedProviderFirstCallerCancels(t *testing.T) {
+	provider := &blockingMonthProvider{started: make(chan struct{}, 10), release: make(chan struct{})}
+	cache, err := psched.NewCachedProvider(provider, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := cache.MonthlyPrayers(ctx, cacheTestInput(time.October))
+		firstErr <- err
+	}()
+	<-provider.started
+
+	cancel()
+	if err := <-firstErr; err != context.Canceled {
+		t.Errorf("first caller returned %v, want %v", err, context.Canceled)
+	}
+
+	// The lookup the first caller gave up on is either still in flight or has stored the month, so the second caller
+	// shares it rather than calling the provider again
+	close(provider.release)
+	if _, err := cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October)); err != nil {
+		t.Errorf("second caller failed after the first caller gave up: %s", err)
+	}
+	if provider.Calls() != 1 {
+		t.Errorf("expected 1 provider call, got %d", provider.Calls())
+	}
+}
+
+func TestNewCachedProviderInvalid(t *testing.T) {
+	if _, err := psched.NewCachedProvider(nil, 10, time.Hour); err == nil {
+		t.Error("missing provider did not return an error")
+	}
+	if _, err := psched.NewCachedProvider(&testMonthProvider{}, 0, time.Hour); err == nil {
+		t.Error("zero size did not return an error")
+	}
+}
+
+func TestCachedZeroValue(t *testing.T) {
+	provider := &testMonthProvider{}
+	cache := &psched.CachedProvider{Provider: provider}
+	for i := 0; i < 2; i++ {
+		if _, err := cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if provider.Calls() != 1 || cache.Len() != 1 {
+		t.Errorf("zero value cached provider made %d provider calls holding %d months, want 1 and 1", provider.Calls(), cache.Len())
+	}
+
+	geocoder := &testGeocoder{}
+	cachedGeocoder := &psched.CachedGeocoder{Geocoder: geocoder}
+	for i := 0; i < 2; i++ {
+		if _, err := cachedGeocoder.Geocode(context.Background(), "US", "90210"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if geocoder.calls != 1 {
+		t.Errorf("zero value cached geocoder made %d geocoder calls, want 1", geocoder.calls)
+	}
+}
+
+func TestCachedGeocoder(t *testing.T) {
+	geocoder := &testGeocoder{}
+	cache, err := psched.NewCachedGeocoder(geocoder, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 0, 0, 0, time.UTC))
+	cache.Clock = clock
+
+	for i := 0; i < 2; i++ {
+		coordinates, err := cache.Geocode(context.Background(), "US", "90210")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if coordinates.Lat != 34.103 || coordinates.Lng != -118.4105 {
+			t.Errorf("unexpected coordinates: %+v", coordinates)
+		}
+		// Returned coordinates are copies
+		coordinates.Lat = 0
+	}
+	if geocoder.calls != 1 {
+		t.Errorf("expected 1 geocoder call, got %d", geocoder.calls)
+	}
+
+	cache.Geocode(context.Background(), "US", "10001")
+	clock.Advance(time.Hour)
+	cache.Geocode(context.Background(), "US", "90210")
+	if geocoder.calls != 3 {
+		t.Errorf("expected another postal code and an expired postal code to be looked up, got %d calls", geocoder.calls)
+	}
+
+	if _, err := psched.NewCachedGeocoder(nil, 10, time.Hour); err == nil {
+		t.Error("missing geocoder did not return an error")
+	}
+}
@@ -0,0 +1,123 @@
+package schedule
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+Clock is a source of the current time and of timers.  Everything in the package which reads the time or waits takes
+a Clock, so tests can replace it with a FakeClock.
+*/
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock of the system, used when no Clock is set
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockOrSystem returns clock, or SystemClock when clock is nil
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
+
+/*
+FakeClock is a Clock whose time only moves when a test moves it.  Advance moves the time forward and fires the
+timers of After which are due.  Set changes the time like a change of the system clock: timers count elapsed time, as
+the timers of the system do, so Set neither fires nor delays them.  A FakeClock is safe for concurrent use.
+*/
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	elapsed time.Duration // Time advanced since the clock was made, which the timers count
+	timers  []*fakeTimer
+}
+
+// fakeTimer is a timer of a FakeClock
+type fakeTimer struct {
+	deadline time.Duration // Elapsed time of the clock at which the timer fires
+	c        chan time.Time
+}
+
+// NewFakeClock returns a FakeClock at now
+func NewFakeClock(now time.Time) *FakeClock {
+	clock := &FakeClock{now: now}
+	clock.changed = sync.NewCond(&clock.mu)
+	return clock
+}
+
+// Now returns the time of the clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel which receives the time of the clock once it has been advanced by d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{deadline: c.elapsed + d, c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+		return timer.c
+	}
+	c.timers = append(c.timers, timer)
+	c.changed.Broadcast()
+	return timer.c
+}
+
+// Advance moves the clock forward by d and fires the timers which are due, earliest first
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	c.elapsed += d
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].deadline < c.timers[j].deadline })
+	for len(c.timers) > 0 && c.timers[0].deadline <= c.elapsed {
+		c.timers[0].c <- c.now
+		c.timers = c.timers[1:]
+	}
+	c.changed.Broadcast()
+}
+
+// Set moves the clock to now without firing or delaying timers, like a change of the system clock
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Timers returns the number of timers which have not fired
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until n timers have not fired, such as until a goroutine is waiting on the clock
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) != n {
+		c.changed.Wait()
+	}
+}
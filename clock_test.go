@@ -0,0 +1,88 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2022, time.October, 12, 12, 0, 0, 0, time.UTC)
+	clock := psched.NewFakeClock(start)
+
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("timer of no duration did not fire at once")
+	}
+
+	timer := clock.After(time.Minute)
+	if clock.Timers() != 1 {
+		t.Fatalf("clock has %d timers, want 1", clock.Timers())
+	}
+	// Changes of the time do not fire timers, which count the time advanced
+	clock.Set(start.Add(time.Hour))
+	clock.Advance(59 * time.Second)
+	select {
+	case <-timer:
+		t.Fatal("timer fired before a minute was advanced")
+	default:
+	}
+	clock.Advance(time.Second)
+	select {
+	case fired := <-timer:
+		if want := start.Add(time.Hour + time.Minute); !fired.Equal(want) {
+			t.Errorf("timer fired at %s, want %s", fired, want)
+		}
+	default:
+		t.Error("timer did not fire after a minute was advanced")
+	}
+	if clock.Timers() != 0 {
+		t.Errorf("clock has %d timers after they fired, want 0", clock.Timers())
+	}
+}
+
+func TestCurrentPrayerWithFakeClock(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for America/Los_Angeles: %s", err)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		untilFajr time.Duration
+		fajr      string
+	}{
+		// Daylight saving time ends at 02:00 on the 6th of November 2022, so the night is an hour longer
+		{"daylight saving", time.Date(2022, time.November, 5, 22, 0, 0, 0, losAngeles), 8*time.Hour + 15*time.Minute, "05:15 (PST)"},
+		{"leap day", time.Date(2024, time.February, 28, 20, 0, 0, 0, losAngeles), 9*time.Hour + 38*time.Minute, "05:38 (PST)"},
+		{"year boundary", time.Date(2022, time.December, 31, 23, 0, 0, 0, losAngeles), 6*time.Hour + 10*time.Minute, "05:10 (PST)"},
+	}
+	for _, test := range tests {
+		clock := psched.NewFakeClock(test.now)
+		customer := &psched.CustomerLocationInput{
+			Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+			Provider:    &testMonthProvider{location: losAngeles},
+			Clock:       clock,
+		}
+
+		isha, err := customer.CurrentPrayer()
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if isha.CurrentPrayerName != "Isha" || isha.NextPrayerName != "Fajr" || isha.NextPrayerTime != test.fajr || isha.TimeDiff != test.untilFajr {
+			t.Errorf("%s: prayer now is %+v, want Isha and Fajr at %s in %s", test.name, isha, test.fajr, test.untilFajr)
+		}
+
+		clock.Advance(isha.TimeDiff)
+		fajr, err := customer.CurrentPrayer()
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if fajr.CurrentPrayerName != "Fajr" || fajr.CurrentPrayerTime != test.fajr || fajr.PreviousDayIsha {
+			t.Errorf("%s: prayer after advancing to Fajr is %+v", test.name, fajr)
+		}
+	}
+}
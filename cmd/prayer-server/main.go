@@ -0,0 +1,98 @@
+// Command prayer-server serves prayer schedules over HTTP as JSON.  The API is described by the OpenAPI 3 document
+// served at /openapi.json, which is also written to standard output by the -openapi flag.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	hereAPIKey := flag.String("here-api-key", os.Getenv("HERE_API_KEY"), "HERE API key enabling country and postal code locations.  Defaults to $HERE_API_KEY")
+	cacheSize := flag.Int("cache-size", 1024, "months kept in memory")
+	cacheTTL := flag.Duration("cache-ttl", 24*time.Hour, "how long months are kept in memory")
+	cacheDir := flag.String("cache-dir", "", "directory months and postal code lookups are also kept in, so they survive restarts")
+	cacheMaxAge := flag.Duration("cache-max-age", 30*24*time.Hour, "how long months and postal code lookups are kept in -cache-dir")
+	magnetic := flag.Bool("magnetic", true, "add the magnetic bearing to qibla responses on days the magnetic model covers")
+	magneticModel := flag.String("magnetic-model", "", "WMM coefficient file used instead of the embedded model, such as a newer WMM.COF from NOAA")
+	openAPI := flag.Bool("openapi", false, "write the OpenAPI document to standard output and exit")
+	flag.Parse()
+
+	server := &psched.APIServer{HEREAPIKey: *hereAPIKey, OnError: func(err error) { log.Print(err) }}
+	if *openAPI {
+		if err := server.WriteOpenAPI(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var provider psched.MonthProvider = &psched.AladhanProvider{}
+	var geocoder psched.Geocoder = &psched.HEREGeocoder{APIKey: *hereAPIKey}
+	if *cacheDir != "" {
+		diskCache, err := psched.NewDiskCache(*cacheDir, *cacheMaxAge)
+		if err != nil {
+			log.Fatal(err)
+		}
+		diskCache.OnError = func(err error) { log.Print(err) }
+		provider = diskCache.Provider(provider)
+		if *hereAPIKey != "" {
+			geocoder = diskCache.Geocoder(geocoder)
+		}
+	}
+	cachedProvider, err := psched.NewCachedProvider(provider, *cacheSize, *cacheTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server.Provider = cachedProvider
+	// Postal codes are kept in memory so repeated requests for the same location do not each call HERE
+	if *hereAPIKey != "" {
+		if server.Geocoder, err = psched.NewCachedGeocoder(geocoder, *cacheSize, *cacheTTL); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *magnetic {
+		if server.Magnetic, err = magneticModelFile(*magneticModel); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	httpServer := &http.Server{Addr: *addr, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// magneticModelFile loads the WMM coefficient file at path, or the embedded model when path is empty
+func magneticModelFile(path string) (*psched.MagneticModel, error) {
+	if path == "" {
+		return psched.DefaultMagneticModel()
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return psched.LoadMagneticModel(file)
+}
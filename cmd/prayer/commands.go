@@ -0,0 +1,545 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// prayerNow is the JSON output of next, the same as the /v1/now response of the REST API
+type prayerNow struct {
+	Time              time.Time `json:"time"` // Time of the command in the timezone of the location
+	CurrentPrayer     string    `json:"current_prayer"`
+	CurrentPrayerTime string    `json:"current_prayer_time"`
+	PreviousDayIsha   bool      `json:"previous_day_isha"` // Whether the current prayer is Isha of the previous day
+	NextPrayer        string    `json:"next_prayer"`
+	NextPrayerTime    string    `json:"next_prayer_time"`
+	SecondsUntilNext  int       `json:"seconds_until_next"`
+}
+
+// hijriOutput is the JSON output of hijri, the same as the /v1/hijri response of the REST API
+type hijriOutput struct {
+	Gregorian string           `json:"gregorian"` // YYYY-MM-DD
+	Hijri     psched.HijriDate `json:"hijri"`
+	MonthName string           `json:"month_name"`
+	Text      string           `json:"text"`
+}
+
+func (c *cli) today(args []string) error {
+	fs, o := c.flags("today", "")
+	date := fs.String("date", "", "day in YYYY-MM-DD.  Defaults to today at the location")
+	arguments, err := c.parse(fs, o, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) != 0 {
+		fs.Usage()
+		return errUsage
+	}
+	customer, err := c.customer(o)
+	if err != nil {
+		return err
+	}
+
+	now := c.clock.Now()
+	var day *psched.PCalDay
+	if *date == "" {
+		if day, err = customer.PrayerDay(now); err != nil {
+			return err
+		}
+	} else {
+		parsed, err := parseDate(*date)
+		if err != nil {
+			return err
+		}
+		days, err := customer.PrayerCalendarRange(parsed, parsed, 1)
+		if err != nil {
+			return err
+		}
+		if len(days.Days) != 1 {
+			return fmt.Errorf("no prayer timings were found for %s", *date)
+		}
+		day = &days.Days[0]
+	}
+	if o.json {
+		return psched.WriteJSON(c.stdout, []psched.PCalDay{*day}, psched.ExportOptions{TwelveHour: o.profile.TwelveHour})
+	}
+
+	dayDate, err := day.Day()
+	if err != nil {
+		return err
+	}
+	// Today's table marks the current and next prayer
+	marks := make(map[string]string)
+	if *date == "" {
+		determined, err := customer.PrayerNow(now)
+		if err != nil {
+			return err
+		}
+		if !determined.PreviousDayIsha {
+			marks[determined.CurrentPrayerName] = "now"
+		}
+		if begins := now.Add(determined.TimeDiff).In(dayDate.Location()); begins.Format("2006-01-02") == dayDate.Format("2006-01-02") {
+			marks[determined.NextPrayerName] = "next"
+		}
+	}
+
+	fmt.Fprintf(c.stdout, "%s, %s (%s)\n\n", dayDate.Format("Monday 2 January 2006"), day.Hijri, dayDate.Location())
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	for _, prayer := range timingNames {
+		timing := timingOf(&day.Timings, prayer)
+		if timing == "" {
+			continue
+		}
+		prayerTime, err := psched.PrayerTimeOnDay(dayDate, timing)
+		if err != nil {
+			return err
+		}
+		if mark := marks[prayer]; mark != "" {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", prayer, prayerTime.Format(timeLayout(o)), mark)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\n", prayer, prayerTime.Format(timeLayout(o)))
+		}
+	}
+	return w.Flush()
+}
+
+func (c *cli) next(args []string) error {
+	fs, o := c.flags("next", "")
+	arguments, err := c.parse(fs, o, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) != 0 {
+		fs.Usage()
+		return errUsage
+	}
+	customer, err := c.customer(o)
+	if err != nil {
+		return err
+	}
+
+	now := c.clock.Now()
+	determined, err := customer.PrayerNow(now)
+	if err != nil {
+		return err
+	}
+	day, err := customer.PrayerDay(now)
+	if err != nil {
+		return err
+	}
+	date, err := day.Day()
+	if err != nil {
+		return err
+	}
+	localNow := now.In(date.Location())
+	if o.json {
+		return writeJSON(c.stdout, &prayerNow{
+			Time:              localNow,
+			CurrentPrayer:     determined.CurrentPrayerName,
+			CurrentPrayerTime: determined.CurrentPrayerTime,
+			PreviousDayIsha:   determined.PreviousDayIsha,
+			NextPrayer:        determined.NextPrayerName,
+			NextPrayerTime:    determined.NextPrayerTime,
+			SecondsUntilNext:  int(determined.TimeDiff.Seconds()),
+		})
+	}
+
+	currentDate := date
+	if determined.PreviousDayIsha {
+		currentDate = date.AddDate(0, 0, -1)
+	}
+	began, err := psched.PrayerTimeOnDay(currentDate, determined.CurrentPrayerTime)
+	if err != nil {
+		return err
+	}
+	begins := localNow.Add(determined.TimeDiff)
+
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Current\t%s\t%s\n", determined.CurrentPrayerName, began.Format(timeLayout(o)))
+	fmt.Fprintf(w, "Next\t%s\t%s\tin %s\n", determined.NextPrayerName, begins.Format(timeLayout(o)), countdown(determined.TimeDiff))
+	return w.Flush()
+}
+
+func (c *cli) month(args []string) error {
+	fs, o := c.flags("month", "[YYYY-MM]")
+	arguments, err := c.parse(fs, o, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) > 1 {
+		fs.Usage()
+		return errUsage
+	}
+	return c.writeDays(o, arguments)
+}
+
+func (c *cli) dateRange(args []string) error {
+	fs, o := c.flags("range", "START END")
+	arguments, err := c.parse(fs, o, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) != 2 {
+		fs.Usage()
+		return errUsage
+	}
+	return c.writeDays(o, arguments)
+}
+
+// writeDays writes the timings of the days of arguments as a table, or as the JSON export with -json
+func (c *cli) writeDays(o *options, arguments []string) error {
+	days, err := c.days(o, arguments)
+	if err != nil {
+		return err
+	}
+	if o.json {
+		return psched.WriteJSON(c.stdout, days, psched.ExportOptions{TwelveHour: o.profile.TwelveHour})
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Date\tHijri\t%s\n", strings.Join(tablePrayers, "\t"))
+	for i := range days {
+		date, err := days[i].Day()
+		if err != nil {
+			return err
+		}
+		row := []string{date.Format("Mon 2006-01-02"), days[i].Hijri.String()}
+		for _, prayer := range tablePrayers {
+			prayerTime, err := psched.PrayerTimeOnDay(date, timingOf(&days[i].Timings, prayer))
+			if err != nil {
+				return err
+			}
+			row = append(row, prayerTime.Format(timeLayout(o)))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (c *cli) export(args []string) (err error) {
+	fs, o := c.flags("export", "[YYYY-MM | START END]")
+	format := fs.String("format", "ics", "ics, csv, json or html")
+	output := fs.String("o", "", "file to write.  Defaults to standard output")
+	title := fs.String("title", "", "calendar name of ics exports and title of html exports")
+	alarm := fs.Int("alarm", 0, "minutes before each prayer to remind at in ics exports.  0 for no reminder")
+	arguments, err := c.parse(fs, o, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) > 2 {
+		fs.Usage()
+		return errUsage
+	}
+	switch *format {
+	case "ics", "csv", "json", "html":
+	default:
+		return fmt.Errorf("-format must be ics, csv, json or html, not %q", *format)
+	}
+	days, err := c.days(o, arguments)
+	if err != nil {
+		return err
+	}
+
+	w := c.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = file
+	}
+
+	exportOptions := psched.ExportOptions{TwelveHour: o.profile.TwelveHour}
+	switch *format {
+	case "ics":
+		return psched.WriteICS(w, days, psched.ICSOptions{Name: *title, AlarmMinutes: *alarm, Clock: c.clock})
+	case "csv":
+		return psched.WriteCSV(w, days, exportOptions)
+	case "json":
+		return psched.WriteJSON(w, days, exportOptions)
+	}
+	timetable := &psched.PrintableTimetable{
+		MosqueName: *title,
+		Days:       days,
+		Hijri:      true,
+		TwelveHour: o.profile.TwelveHour,
+		Clock:      c.clock,
+	}
+	return timetable.WriteHTML(w)
+}
+
+func (c *cli) qibla(args []string) error {
+	fs, o := c.flags("qibla", "[YYYY-MM-DD]")
+	magneticModel := fs.String("magnetic-model", "", "WMM coefficient file used instead of the embedded model, such as a newer WMM.COF from NOAA")
+	arguments, err := c.parse(fs, o, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) > 1 {
+		fs.Usage()
+		return errUsage
+	}
+	customer, err := c.customer(o)
+	if err != nil {
+		return err
+	}
+	location, err := o.profile.location()
+	if err != nil {
+		return err
+	}
+	day, err := c.day(arguments, location)
+	if err != nil {
+		return err
+	}
+	coordinates, err := coordinates(customer)
+	if err != nil {
+		return err
+	}
+
+	magnetic, err := magneticModelFile(*magneticModel)
+	if err != nil {
+		return err
+	}
+	qibla, err := coordinates.Qibla(day, magnetic)
+	if err != nil {
+		return err
+	}
+	if o.json {
+		return writeJSON(c.stdout, qibla)
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Bearing\t%.1f° from true north\n", qibla.Bearing)
+	if qibla.HasMagnetic {
+		fmt.Fprintf(w, "Magnetic bearing\t%.1f° from magnetic north\n", qibla.MagneticBearing)
+	} else {
+		fmt.Fprintf(w, "Magnetic bearing\tunknown, %s does not cover %s.  Pass a newer WMM.COF with -magnetic-model\n", magnetic.Name, day.Format("2006-01-02"))
+	}
+	fmt.Fprintf(w, "Distance\t%.0f km\n", qibla.DistanceKm)
+	fmt.Fprintf(w, "Sun towards the qibla\t%s\n", formatTimes(qibla.SunTowardsQibla, timeLayout(o)))
+	fmt.Fprintf(w, "Shadows towards the qibla\t%s\n", formatTimes(qibla.SunAwayFromQibla, timeLayout(o)))
+	fmt.Fprintf(w, "Sun over the Kaaba\t%s\n", formatTimes(qibla.SunOverKaaba[:], "2 January "+timeLayout(o)))
+	return w.Flush()
+}
+
+// magneticModelFile loads the WMM coefficient file at path, or the embedded model when path is empty
+func magneticModelFile(path string) (*psched.MagneticModel, error) {
+	if path == "" {
+		return psched.DefaultMagneticModel()
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return psched.LoadMagneticModel(file)
+}
+
+func (c *cli) hijri(args []string) error {
+	fs, o := c.flags("hijri", "[YYYY-MM-DD]")
+	arguments, err := c.parse(fs, o, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) > 1 {
+		fs.Usage()
+		return errUsage
+	}
+	calendar, adjustment, err := o.profile.hijriSettings()
+	if err != nil {
+		return err
+	}
+	location, err := o.profile.location()
+	if err != nil {
+		return err
+	}
+	date, err := c.day(arguments, location)
+	if err != nil {
+		return err
+	}
+	hijri, err := psched.ToHijri(date, calendar, adjustment)
+	if err != nil {
+		return err
+	}
+	if o.json {
+		return writeJSON(c.stdout, &hijriOutput{Gregorian: date.Format("2006-01-02"), Hijri: hijri, MonthName: hijri.MonthName(), Text: hijri.String()})
+	}
+	fmt.Fprintln(c.stdout, hijri)
+	return nil
+}
+
+func (c *cli) profile(args []string) error {
+	const profileUsage = "usage: prayer profile save [flags] [NAME] | list [-json] | delete NAME\n"
+	if len(args) == 0 {
+		fmt.Fprint(c.stderr, profileUsage)
+		return errUsage
+	}
+
+	switch args[0] {
+	case "save":
+		fs, o := c.flags("profile save", "[NAME]")
+		arguments, err := parseInterspersed(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(arguments) > 1 {
+			fs.Usage()
+			return errUsage
+		}
+		name := defaultProfile
+		if len(arguments) == 1 {
+			name = arguments[0]
+		}
+		// The flags change the saved profile of the name, or start from -profile
+		if o.profileName != "" {
+			err = c.applyProfile(fs, o, o.profileName, true)
+		} else {
+			err = c.applyProfile(fs, o, name, false)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := c.customer(o); err != nil {
+			return err
+		}
+
+		profiles, err := c.loadProfiles()
+		if err != nil {
+			return err
+		}
+		profiles[name] = o.profile
+		if err := c.saveProfiles(profiles); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "saved profile %s\n", name)
+		return nil
+
+	case "list":
+		fs := flag.NewFlagSet("profile list", flag.ContinueOnError)
+		fs.SetOutput(c.stderr)
+		asJSON := fs.Bool("json", false, "write JSON instead of a table")
+		arguments, err := parseInterspersed(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(arguments) != 0 {
+			fs.Usage()
+			return errUsage
+		}
+		profiles, err := c.loadProfiles()
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return writeJSON(c.stdout, profiles)
+		}
+
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Name\tMethod\tSchool\tHigh latitude\tTune\tLocation")
+		for _, name := range names {
+			p := profiles[name]
+			location := fmt.Sprintf("%g, %g", p.Latitude, p.Longitude)
+			if p.PostalCode != "" {
+				location = p.Country + " " + p.PostalCode
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n", name, p.Method, p.School, p.HighLatitude, p.Tune, location)
+		}
+		return w.Flush()
+
+	case "delete":
+		if len(args) != 2 {
+			fmt.Fprint(c.stderr, profileUsage)
+			return errUsage
+		}
+		profiles, err := c.loadProfiles()
+		if err != nil {
+			return err
+		}
+		if _, ok := profiles[args[1]]; !ok {
+			return fmt.Errorf("profile %q is not saved", args[1])
+		}
+		delete(profiles, args[1])
+		return c.saveProfiles(profiles)
+	}
+
+	fmt.Fprintf(c.stderr, "prayer: unknown profile command %q\n%s", args[0], profileUsage)
+	return errUsage
+}
+
+// days returns the timings of this month when there are no arguments, of a YYYY-MM month, or of a range of two
+// YYYY-MM-DD days
+func (c *cli) days(o *options, arguments []string) ([]psched.PCalDay, error) {
+	var start, end time.Time
+	switch len(arguments) {
+	case 0:
+		location, err := o.profile.location()
+		if err != nil {
+			return nil, err
+		}
+		now := c.clock.Now().In(location)
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, -1)
+	case 1:
+		month, err := time.Parse("2006-01", arguments[0])
+		if err != nil {
+			return nil, fmt.Errorf("month %q is not YYYY-MM", arguments[0])
+		}
+		start, end = month, month.AddDate(0, 1, -1)
+	default:
+		var err error
+		if start, err = parseDate(arguments[0]); err != nil {
+			return nil, err
+		}
+		if end, err = parseDate(arguments[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	customer, err := c.customer(o)
+	if err != nil {
+		return nil, err
+	}
+	days, err := customer.PrayerCalendarRange(start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+	return days.Days, nil
+}
+
+// day returns midnight in location of the YYYY-MM-DD day of arguments, or of today when there are none
+func (c *cli) day(arguments []string, location *time.Location) (time.Time, error) {
+	if len(arguments) == 0 {
+		now := c.clock.Now().In(location)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location), nil
+	}
+	date, err := parseDate(arguments[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, location), nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("date %q is not YYYY-MM-DD", value)
+	}
+	return date, nil
+}
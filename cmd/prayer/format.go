@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// timingNames are the timings of a day in order, as written by today
+var timingNames = []string{"Imsak", "Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// tablePrayers are the timings of the month and range tables
+var tablePrayers = []string{"Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// timingOf returns the timing called name
+func timingOf(timings *psched.FiveDailyPrayers, name string) string {
+	switch name {
+	case "Imsak":
+		return timings.Imsak
+	case "Fajr":
+		return timings.Fajr
+	case "Sunrise":
+		return timings.Sunrise
+	case "Dhuhr":
+		return timings.Dhuhr
+	case "Asr":
+		return timings.Asr
+	case "Maghrib":
+		return timings.Maghrib
+	case "Isha":
+		return timings.Isha
+	}
+	return ""
+}
+
+// timeLayout returns the layout of the times of tables
+func timeLayout(o *options) string {
+	if o.profile.TwelveHour {
+		return "3:04 PM"
+	}
+	return "15:04"
+}
+
+// countdown formats d as hours and minutes, or as minutes and seconds under an hour
+func countdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh %02dm", d/time.Hour, d%time.Hour/time.Minute)
+	}
+	return fmt.Sprintf("%dm %02ds", d/time.Minute, d%time.Minute/time.Second)
+}
+
+// formatTimes formats times with layout, separated by commas
+func formatTimes(times []time.Time, layout string) string {
+	if len(times) == 0 {
+		return "none"
+	}
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Format(layout)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// writeJSON writes value as indented JSON
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,104 @@
+/*
+Command prayer prints prayer timings, the current and next prayer, the qibla and Hijri dates, and exports timetables.
+The location and calculation settings are given as flags, or saved once as a profile:
+
+	prayer profile save -latitude 34.103 -longitude -118.4105 -method 2
+	prayer next
+	prayer month 2022-10 -json
+	prayer export -format ics -o prayers.ics 2022-10-01 2022-12-31
+
+Every command writes a table, or JSON with -json.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+const usage = `usage: prayer <command> [flags] [arguments]
+
+commands:
+  today [-date YYYY-MM-DD]              timings of today, or of -date
+  next                                  current and next prayer, with a countdown
+  month [YYYY-MM]                       timings of every day of a month
+  range START END                       timings of every day from START to END, as YYYY-MM-DD
+  export -format ics|csv|json|html [YYYY-MM | START END]
+                                        timetable of a month or range as a file
+  qibla [YYYY-MM-DD]                    qibla bearing and distance, and when the sun lines up with it
+  hijri [YYYY-MM-DD]                    Hijri date
+  profile save [NAME]                   saves the location and settings flags, as default without NAME
+  profile list                          lists the saved profiles
+  profile delete NAME                   deletes a saved profile
+
+Run prayer <command> -h for the flags of a command.
+`
+
+// errUsage is returned when the arguments are wrong and the usage has already been written
+var errUsage = errors.New("usage")
+
+// cli runs the commands of prayer
+type cli struct {
+	stdout       io.Writer
+	stderr       io.Writer
+	clock        psched.Clock
+	provider     psched.MonthProvider // Used instead of aladhan when set
+	geocoder     psched.Geocoder      // Used instead of HERE when set
+	profilesPath string               // JSON file the profiles are saved in
+	cacheDir     string               // Default of -cache-dir
+	hereAPIKey   string               // Default of -here-api-key
+}
+
+func main() {
+	c := &cli{stdout: os.Stdout, stderr: os.Stderr, clock: psched.SystemClock, hereAPIKey: os.Getenv("HERE_API_KEY")}
+	if dir, err := os.UserConfigDir(); err == nil {
+		c.profilesPath = filepath.Join(dir, "prayer-schedule", "profiles.json")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		c.cacheDir = filepath.Join(dir, "prayer-schedule")
+	}
+
+	err := c.run(os.Args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "prayer: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command of args
+func (c *cli) run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(c.stderr, usage)
+		return errUsage
+	}
+	commands := map[string]func(args []string) error{
+		"today":   c.today,
+		"next":    c.next,
+		"month":   c.month,
+		"range":   c.dateRange,
+		"export":  c.export,
+		"qibla":   c.qibla,
+		"hijri":   c.hijri,
+		"profile": c.profile,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+			fmt.Fprint(c.stdout, usage)
+			return nil
+		}
+		fmt.Fprintf(c.stderr, "prayer: unknown command %q\n\n%s", args[0], usage)
+		return errUsage
+	}
+	return command(args[1:])
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// testMonthProvider returns Fajr at 05:10, Dhuhr at 12:30 and Isha at 19:30 every day of the month in Los Angeles.
+// Fajr is a minute later for each calculation method number, so tests can tell which method was asked for
+type testMonthProvider struct {
+	location *time.Location
+	mu       sync.Mutex
+	inputs   []psched.PCalInput
+}
+
+func (p *testMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.mu.Lock()
+	p.inputs = append(p.inputs, *input)
+	p.mu.Unlock()
+
+	first := time.Date(input.CustTime.Year(), input.CustTime.Month(), 1, 0, 0, 0, 0, p.location)
+	output := &psched.PCalOutput{Code: 200, Status: "OK", Latitude: input.Latitude, Longitude: input.Longitude}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		at := func(hour int, minute int) string {
+			return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, p.location).Format("15:04 (MST)")
+		}
+		pcalDay := psched.PCalDay{
+			Timings: psched.FiveDailyPrayers{
+				Imsak:   at(5, 0),
+				Fajr:    at(5, 10+input.Institution-2),
+				Sunrise: at(6, 30),
+				Dhuhr:   at(12, 30),
+				Asr:     at(15, 45),
+				Maghrib: at(18, 15),
+				Isha:    at(19, 30),
+			},
+		}
+		pcalDay.Date.Gregorian.Date = day.Format("02-01-2006")
+		pcalDay.Meta.Timezone = p.location.String()
+		pcalDay.Meta.Latitude = float64(input.Latitude)
+		pcalDay.Meta.Longitude = float64(input.Longitude)
+		output.Data = append(output.Data, pcalDay)
+	}
+	return output, nil
+}
+
+// lastInput returns the input of the latest lookup
+func (p *testMonthProvider) lastInput() psched.PCalInput {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.inputs[len(p.inputs)-1]
+}
+
+// testGeocoder finds every postal code in Beverly Hills
+type testGeocoder struct{}
+
+func (testGeocoder) Geocode(ctx context.Context, countryCode string, postalCode string) (*psched.CustomerCoordinatesOutput, error) {
+	return &psched.CustomerCoordinatesOutput{Lat: 34.103, Lng: -118.4105}, nil
+}
+
+// testCLI returns a cli at 12:29:30 on 12 October 2022 in Los Angeles, with no saved profiles
+func testCLI(t *testing.T) (*cli, *testMonthProvider, *bytes.Buffer) {
+	t.Helper()
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := &bytes.Buffer{}
+	provider := &testMonthProvider{location: losAngeles}
+	return &cli{
+		stdout:       stdout,
+		stderr:       &bytes.Buffer{},
+		clock:        psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 29, 30, 0, losAngeles)),
+		provider:     provider,
+		geocoder:     testGeocoder{},
+		profilesPath: filepath.Join(t.TempDir(), "profiles.json"),
+	}, provider, stdout
+}
+
+// runCLI runs args and returns what was written to standard output
+func runCLI(t *testing.T, c *cli, stdout *bytes.Buffer, args ...string) string {
+	t.Helper()
+	stdout.Reset()
+	if err := c.run(args); err != nil {
+		t.Fatalf("prayer %s: %s", strings.Join(args, " "), err)
+	}
+	return stdout.String()
+}
+
+var testLocationFlags = []string{"-latitude", "34.103", "-longitude", "-118.4105"}
+
+func TestToday(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	output := runCLI(t, c, stdout, append([]string{"today"}, testLocationFlags...)...)
+	for _, want := range []string{
+		"Wednesday 12 October 2022, 16 Rabi al-Awwal 1444 AH (America/Los_Angeles)",
+		"Sunrise  06:30  now",
+		"Dhuhr    12:30  next",
+		"Isha     19:30\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+
+	var document psched.ExportDocument
+	output = runCLI(t, c, stdout, append([]string{"today", "-date", "2022-11-01", "--json"}, testLocationFlags...)...)
+	if err := json.Unmarshal([]byte(output), &document); err != nil {
+		t.Fatal(err)
+	}
+	if len(document.Days) != 1 || document.Days[0].Date != "2022-11-01" || document.Days[0].Timings.Fajr != "05:10" {
+		t.Errorf("document = %+v", document)
+	}
+}
+
+func TestNext(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	output := runCLI(t, c, stdout, append([]string{"next", "-twelve-hour"}, testLocationFlags...)...)
+	for _, want := range []string{"Current  Sunrise  6:30 AM", "Next     Dhuhr    12:30 PM  in 0m 30s"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+
+	var now prayerNow
+	output = runCLI(t, c, stdout, append([]string{"next", "-json"}, testLocationFlags...)...)
+	if err := json.Unmarshal([]byte(output), &now); err != nil {
+		t.Fatal(err)
+	}
+	if now.CurrentPrayer != "Sunrise" || now.NextPrayer != "Dhuhr" || now.SecondsUntilNext != 30 {
+		t.Errorf("next = %+v", now)
+	}
+}
+
+func TestMonthAndRange(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	output := runCLI(t, c, stdout, append([]string{"month", "2022-10"}, testLocationFlags...)...)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 32 {
+		t.Fatalf("month has %d lines, want a header and 31 days:\n%s", len(lines), output)
+	}
+	if !strings.HasPrefix(lines[0], "Date") || !strings.HasPrefix(lines[12], "Wed 2022-10-12  16 Rabi al-Awwal 1444 AH  05:10") {
+		t.Errorf("month table:\n%s", output)
+	}
+
+	// Flags may come after the arguments
+	var document psched.ExportDocument
+	output = runCLI(t, c, stdout, append([]string{"range", "2022-10-30", "2022-11-02", "-json"}, testLocationFlags...)...)
+	if err := json.Unmarshal([]byte(output), &document); err != nil {
+		t.Fatal(err)
+	}
+	if len(document.Days) != 4 || document.Days[0].Date != "2022-10-30" || document.Days[3].Date != "2022-11-02" {
+		t.Errorf("range = %+v", document.Days)
+	}
+
+	if err := c.run(append([]string{"range", "2022-10-30"}, testLocationFlags...)); !errors.Is(err, errUsage) {
+		t.Errorf("range with one day returned %v, want usage", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	formats := map[string]string{
+		"ics":  "BEGIN:VCALENDAR",
+		"csv":  "Date,Hijri,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n2022-10-01,",
+		"json": `"schema_version": 1`,
+		"html": "<table",
+	}
+	for format, want := range formats {
+		output := runCLI(t, c, stdout, append([]string{"export", "-format", format}, testLocationFlags...)...)
+		if !strings.Contains(output, want) {
+			t.Errorf("%s export does not contain %q:\n%s", format, want, output)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "prayers.ics")
+	runCLI(t, c, stdout, append([]string{"export", "-o", path, "-title", "Home", "2022-12-30", "2023-01-02"}, testLocationFlags...)...)
+	ics, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(ics), "X-WR-CALNAME:Home") || strings.Count(string(ics), "BEGIN:VEVENT") != 20 {
+		t.Errorf("ics export of 4 days:\n%s", ics)
+	}
+
+	if err := c.run(append([]string{"export", "-format", "pdf"}, testLocationFlags...)); err == nil {
+		t.Error("pdf export succeeded")
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	c, provider, stdout := testCLI(t)
+	if err := c.run([]string{"next"}); err == nil || !strings.Contains(err.Error(), "location is required") {
+		t.Errorf("next without a location returned %v", err)
+	}
+
+	runCLI(t, c, stdout, append([]string{"profile", "save", "-method", "3", "-school", "1", "-tune", "0,2,0,0,0,3,0"}, testLocationFlags...)...)
+	runCLI(t, c, stdout, "profile", "save", "-country", "US", "-postal-code", "90210", "-method", "4", "work")
+
+	// The default profile is used without flags
+	runCLI(t, c, stdout, "next")
+	input := provider.lastInput()
+	if input.Institution != 3 || input.School != 1 || input.Offsets.Fajr != 2 || input.Offsets.Maghrib != 3 || input.Latitude != 34.103 {
+		t.Errorf("default profile input = %+v", input)
+	}
+
+	// Flags override the profile
+	runCLI(t, c, stdout, "next", "-profile", "work", "-school", "1")
+	input = provider.lastInput()
+	if input.Institution != 4 || input.School != 1 || input.Offsets.Fajr != 0 {
+		t.Errorf("work profile input = %+v", input)
+	}
+
+	output := runCLI(t, c, stdout, "profile", "list")
+	if !strings.Contains(output, "default  3       1       0              0,2,0,0,0,3,0  34.103, -118.4105\n") ||
+		!strings.Contains(output, "work     4       0       0                             US 90210\n") {
+		t.Errorf("profiles:\n%s", output)
+	}
+
+	runCLI(t, c, stdout, "profile", "delete", "work")
+	if err := c.run([]string{"next", "-profile", "work"}); err == nil || !strings.Contains(err.Error(), "not saved") {
+		t.Errorf("next with a deleted profile returned %v", err)
+	}
+	if err := c.run(append([]string{"profile", "save", "-method", "6", "broken"}, testLocationFlags...)); err == nil || !strings.Contains(err.Error(), "method 6") {
+		t.Errorf("saving an unknown method returned %v", err)
+	}
+}
+
+func TestQibla(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	var qibla psched.QiblaOutput
+	output := runCLI(t, c, stdout, append([]string{"qibla", "-json", "2022-10-12"}, testLocationFlags...)...)
+	if err := json.Unmarshal([]byte(output), &qibla); err != nil {
+		t.Fatal(err)
+	}
+	if qibla.Bearing < 23 || qibla.Bearing > 25 || qibla.DistanceKm < 13000 || qibla.DistanceKm > 13500 || !qibla.HasMagnetic {
+		t.Errorf("qibla = %+v", qibla)
+	}
+
+	output = runCLI(t, c, stdout, "qibla", "-country", "US", "-postal-code", "90210", "2022-10-12")
+	if !strings.Contains(output, "Bearing") || !strings.Contains(output, "° from magnetic north") {
+		t.Errorf("qibla:\n%s", output)
+	}
+}
+
+func TestQiblaMagneticModel(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	output := runCLI(t, c, stdout, append([]string{"qibla", "2026-10-19"}, testLocationFlags...)...)
+	if !strings.Contains(output, "unknown, WMM-2020 does not cover 2026-10-19") {
+		t.Errorf("qibla past the embedded model:\n%s", output)
+	}
+
+	// A model file with a later epoch, standing in for a newer model from NOAA
+	coefficients, err := os.ReadFile(filepath.Join("..", "..", "WMM.COF"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelPath := filepath.Join(t.TempDir(), "WMM.COF")
+	newer := strings.Replace(string(coefficients), "2020.0            WMM-2020", "2025.0            WMM-TEST", 1)
+	if err := os.WriteFile(modelPath, []byte(newer), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	output = runCLI(t, c, stdout, append([]string{"qibla", "-magnetic-model", modelPath, "2026-10-19"}, testLocationFlags...)...)
+	if !strings.Contains(output, "° from magnetic north") {
+		t.Errorf("qibla with -magnetic-model:\n%s", output)
+	}
+}
+
+func TestHijri(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	if output := runCLI(t, c, stdout, "hijri", "2022-10-12"); output != "16 Rabi al-Awwal 1444 AH\n" {
+		t.Errorf("hijri = %q", output)
+	}
+
+	var hijri hijriOutput
+	output := runCLI(t, c, stdout, "hijri", "-json", "-hijri-adjustment", "1", "2022-10-12")
+	if err := json.Unmarshal([]byte(output), &hijri); err != nil {
+		t.Fatal(err)
+	}
+	if hijri.Gregorian != "2022-10-12" || hijri.Hijri.Day != 17 || hijri.Hijri.Month != 3 || hijri.Hijri.Year != 1444 {
+		t.Errorf("hijri = %+v", hijri)
+	}
+}
+
+func TestTodayInProfileTimezone(t *testing.T) {
+	c, _, stdout := testCLI(t)
+	// 20:00 on the 31st of October in Los Angeles is already the 1st of November in Tokyo
+	c.clock = psched.NewFakeClock(time.Date(2022, time.November, 1, 3, 0, 0, 0, time.UTC))
+
+	for timezone, want := range map[string]string{"America/Los_Angeles": "2022-10-31", "Asia/Tokyo": "2022-11-01"} {
+		var hijri hijriOutput
+		output := runCLI(t, c, stdout, "hijri", "-json", "-timezone", timezone)
+		if err := json.Unmarshal([]byte(output), &hijri); err != nil {
+			t.Fatal(err)
+		}
+		if hijri.Gregorian != want {
+			t.Errorf("hijri in %s is of %s, want %s", timezone, hijri.Gregorian, want)
+		}
+
+		output = runCLI(t, c, stdout, append([]string{"month", "-timezone", timezone}, testLocationFlags...)...)
+		if lines := strings.Split(output, "\n"); len(lines) < 2 || !strings.Contains(lines[1], want[:8]+"01") {
+			t.Errorf("month in %s:\n%s", timezone, output)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	c, _, _ := testCLI(t)
+	for _, args := range [][]string{{}, {"unknown"}, {"today", "-unknown"}, {"profile"}} {
+		if err := c.run(args); !errors.Is(err, errUsage) {
+			t.Errorf("prayer %s returned %v, want usage", strings.Join(args, " "), err)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+const (
+	// defaultProfile is the profile used when -profile is not given
+	defaultProfile = "default"
+	// defaultMethod is the calculation method used when neither a flag nor a profile sets one
+	defaultMethod = 2
+	// cacheMaxAge is how long months and postal code lookups are kept in -cache-dir
+	cacheMaxAge = 30 * 24 * time.Hour
+)
+
+// profile is the location and calculation settings of a command, saved by name in the profiles file
+type profile struct {
+	Latitude        float64 `json:"latitude,omitempty"`
+	Longitude       float64 `json:"longitude,omitempty"`
+	Country         string  `json:"country,omitempty"`
+	PostalCode      string  `json:"postal_code,omitempty"`
+	Timezone        string  `json:"timezone,omitempty"` // Timezone of offline timings and qibla times.  Defaults to the local timezone
+	Method          int     `json:"method"`
+	School          int     `json:"school"`
+	HighLatitude    int     `json:"high_latitude"`
+	Tune            string  `json:"tune,omitempty"`
+	HijriCalendar   string  `json:"hijri_calendar,omitempty"`
+	HijriAdjustment int     `json:"hijri_adjustment"`
+	TwelveHour      bool    `json:"twelve_hour,omitempty"`
+}
+
+// options are the flags every command takes
+type options struct {
+	profile     profile
+	profileName string
+	hereAPIKey  string
+	cacheDir    string
+	offline     bool
+	json        bool
+}
+
+// flags returns the flag set of command, with the flags every command takes
+func (c *cli) flags(command string, arguments string) (*flag.FlagSet, *options) {
+	o := &options{}
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: prayer %s [flags] %s\n\nflags:\n", command, arguments)
+		fs.PrintDefaults()
+	}
+
+	fs.Float64Var(&o.profile.Latitude, "latitude", 0, "latitude of the location")
+	fs.Float64Var(&o.profile.Longitude, "longitude", 0, "longitude of the location")
+	fs.StringVar(&o.profile.Country, "country", "", "ISO 3166 country code of the location, instead of -latitude and -longitude")
+	fs.StringVar(&o.profile.PostalCode, "postal-code", "", "postal code of the location, looked up with HERE")
+	fs.StringVar(&o.profile.Timezone, "timezone", "", "IANA timezone of -offline timings and qibla times.  Defaults to the local timezone")
+	fs.IntVar(&o.profile.Method, "method", defaultMethod, "aladhan calculation method.  2 is the Islamic Society of North America")
+	fs.IntVar(&o.profile.School, "school", 0, "Asr juristic school.  0 for Shafi and 1 for Hanafi")
+	fs.IntVar(&o.profile.HighLatitude, "high-latitude", 0, "high latitude rule.  1 middle of the night, 2 one seventh, 3 angle based")
+	fs.StringVar(&o.profile.Tune, "tune", "", "comma separated minutes added to Imsak, Fajr, Sunrise, Dhuhr, Asr, Maghrib and Isha")
+	fs.StringVar(&o.profile.HijriCalendar, "hijri-calendar", "ummalqura", "ummalqura or tabular")
+	fs.IntVar(&o.profile.HijriAdjustment, "hijri-adjustment", 0, "days added to Hijri dates for local moon sighting, from -2 to 2")
+	fs.BoolVar(&o.profile.TwelveHour, "twelve-hour", false, "print times such as 5:38 PM instead of 17:38")
+	fs.StringVar(&o.profileName, "profile", "", "saved profile of the location and settings, which flags override.  Defaults to the profile named default")
+	fs.StringVar(&o.hereAPIKey, "here-api-key", c.hereAPIKey, "HERE API key enabling -country and -postal-code.  Defaults to $HERE_API_KEY")
+	fs.StringVar(&o.cacheDir, "cache-dir", c.cacheDir, "directory months and postal code lookups are kept in.  Empty to not keep them")
+	fs.BoolVar(&o.offline, "offline", false, "calculate the timings without network requests, in -timezone")
+	fs.BoolVar(&o.json, "json", false, "write JSON instead of a table")
+	return fs, o
+}
+
+/*
+parse parses the flags of args, which may come before, after or between the arguments, and returns the arguments.
+Settings which are not given as flags are read from the profile of -profile, or from the default profile when it is
+saved.
+*/
+func (c *cli) parse(fs *flag.FlagSet, o *options, args []string) ([]string, error) {
+	arguments, err := parseInterspersed(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	name, required := o.profileName, true
+	if name == "" {
+		name, required = defaultProfile, false
+	}
+	if err := c.applyProfile(fs, o, name, required); err != nil {
+		return nil, err
+	}
+	return arguments, nil
+}
+
+// parseInterspersed parses the flags of args and returns the arguments between them
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var arguments []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return arguments, nil
+		}
+		arguments = append(arguments, args[0])
+		args = args[1:]
+	}
+}
+
+// applyProfile replaces the settings of o which were not given as flags with those of the saved profile name
+func (c *cli) applyProfile(fs *flag.FlagSet, o *options, name string, required bool) error {
+	profiles, err := c.loadProfiles()
+	if err != nil {
+		return err
+	}
+	saved, ok := profiles[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("profile %q is not saved", name)
+		}
+		return nil
+	}
+
+	given := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = f.Value.String() })
+	// A location given as flags replaces the location of the profile, whichever way either is given
+	if _, ok := given["latitude"]; ok {
+		saved.Country, saved.PostalCode = "", ""
+	} else if _, ok := given["longitude"]; ok {
+		saved.Country, saved.PostalCode = "", ""
+	}
+	if _, ok := given["country"]; ok {
+		saved.Latitude, saved.Longitude = 0, 0
+	} else if _, ok := given["postal-code"]; ok {
+		saved.Latitude, saved.Longitude = 0, 0
+	}
+
+	o.profile = saved
+	for flagName, value := range given {
+		if err := fs.Set(flagName, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadProfiles reads the saved profiles.  There are none when the profiles file does not exist
+func (c *cli) loadProfiles() (map[string]profile, error) {
+	profiles := make(map[string]profile)
+	if c.profilesPath == "" {
+		return profiles, nil
+	}
+	data, err := os.ReadFile(c.profilesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read profiles: %s", err)
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("unable to read profiles from %s: %s", c.profilesPath, err)
+	}
+	return profiles, nil
+}
+
+// saveProfiles replaces the saved profiles with profiles
+func (c *cli) saveProfiles(profiles map[string]profile) error {
+	if c.profilesPath == "" {
+		return fmt.Errorf("there is no configuration directory to save profiles in")
+	}
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.profilesPath), 0o755); err != nil {
+		return fmt.Errorf("unable to save profiles: %s", err)
+	}
+	if err := os.WriteFile(c.profilesPath, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to save profiles: %s", err)
+	}
+	return nil
+}
+
+// customer returns the location and calculation settings of o
+func (c *cli) customer(o *options) (*psched.CustomerLocationInput, error) {
+	p := o.profile
+	customer := &psched.CustomerLocationInput{
+		Institution:              p.Method,
+		School:                   p.School,
+		LatitudeAdjustmentMethod: p.HighLatitude,
+		Clock:                    c.clock,
+	}
+
+	var cache *psched.DiskCache
+	if o.cacheDir != "" && !o.offline {
+		var err error
+		if cache, err = psched.NewDiskCache(o.cacheDir, cacheMaxAge); err != nil {
+			return nil, err
+		}
+	}
+
+	hasCoordinates := p.Latitude != 0 || p.Longitude != 0
+	hasPostalCode := p.Country != "" || p.PostalCode != ""
+	switch {
+	case hasCoordinates && hasPostalCode:
+		return nil, fmt.Errorf("give either -latitude and -longitude or -country and -postal-code, not both")
+	case hasCoordinates:
+		if p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+			return nil, fmt.Errorf("-latitude and -longitude must be within -90 to 90 and -180 to 180")
+		}
+		if p.Latitude == 0 || p.Longitude == 0 {
+			return nil, fmt.Errorf("-latitude and -longitude on the equator or the prime meridian are not supported")
+		}
+		customer.Coordinates = psched.PrayerCalendarInputCoordinates{Latitude: float32(p.Latitude), Longitude: float32(p.Longitude)}
+	case hasPostalCode:
+		if p.Country == "" || p.PostalCode == "" {
+			return nil, fmt.Errorf("-country and -postal-code are both required")
+		}
+		customer.CountryCode = p.Country
+		customer.PostalCode = p.PostalCode
+		customer.Geocoder = c.geocoder
+		if customer.Geocoder == nil {
+			if o.hereAPIKey == "" {
+				return nil, fmt.Errorf("postal code locations require -here-api-key or $HERE_API_KEY")
+			}
+			customer.Geocoder = &psched.HEREGeocoder{APIKey: o.hereAPIKey}
+			if cache != nil {
+				customer.Geocoder = cache.Geocoder(customer.Geocoder)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("a location is required: -latitude and -longitude, -country and -postal-code, or a saved profile")
+	}
+
+	if _, ok := psched.CalculationMethods[customer.Institution]; !ok {
+		return nil, fmt.Errorf("method %d is unknown", customer.Institution)
+	}
+	if customer.School != 0 && customer.School != 1 {
+		return nil, fmt.Errorf("-school must be 0 or 1")
+	}
+	if customer.LatitudeAdjustmentMethod < 0 || customer.LatitudeAdjustmentMethod > psched.LatitudeAdjustmentAngleBased {
+		return nil, fmt.Errorf("-high-latitude must be from 0 to 3")
+	}
+
+	if p.Tune != "" {
+		minutes := strings.Split(p.Tune, ",")
+		offsets := []*int{
+			&customer.Offsets.Imsak, &customer.Offsets.Fajr, &customer.Offsets.Sunrise, &customer.Offsets.Dhuhr,
+			&customer.Offsets.Asr, &customer.Offsets.Maghrib, &customer.Offsets.Isha,
+		}
+		if len(minutes) != len(offsets) {
+			return nil, fmt.Errorf("-tune must have %d comma separated minutes", len(offsets))
+		}
+		for i, value := range minutes {
+			parsed, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("-tune %q is not minutes", value)
+			}
+			*offsets[i] = parsed
+		}
+	}
+
+	var err error
+	if customer.HijriCalendar, customer.HijriAdjustment, err = p.hijriSettings(); err != nil {
+		return nil, err
+	}
+
+	// Months are kept in memory, as a command may look up the same month more than once
+	var provider psched.MonthProvider
+	switch {
+	case c.provider != nil:
+		provider = c.provider
+	case o.offline:
+		location, err := p.location()
+		if err != nil {
+			return nil, err
+		}
+		provider = &psched.LocalProvider{Location: location}
+	case cache != nil:
+		provider = cache.Provider(&psched.AladhanProvider{})
+	default:
+		provider = &psched.AladhanProvider{}
+	}
+	cachedProvider, err := psched.NewCachedProvider(provider, 16, 0)
+	if err != nil {
+		return nil, err
+	}
+	cachedProvider.Clock = c.clock
+	customer.Provider = cachedProvider
+	return customer, nil
+}
+
+// coordinates returns the coordinates of the customer, looking up a postal code location
+func coordinates(customer *psched.CustomerLocationInput) (psched.PrayerCalendarInputCoordinates, error) {
+	if customer.Geocoder == nil {
+		return customer.Coordinates, nil
+	}
+	found, err := customer.Geocoder.Geocode(context.Background(), customer.CountryCode, customer.PostalCode)
+	if err != nil {
+		return psched.PrayerCalendarInputCoordinates{}, err
+	}
+	return psched.PrayerCalendarInputCoordinates{Latitude: found.Lat, Longitude: found.Lng}, nil
+}
+
+// hijriSettings returns the Hijri calendar and adjustment of the profile
+func (p profile) hijriSettings() (psched.HijriCalendar, int, error) {
+	calendar := psched.UmmAlQura
+	switch strings.ToLower(p.HijriCalendar) {
+	case "", "ummalqura":
+	case "tabular":
+		calendar = psched.TabularHijri
+	default:
+		return 0, 0, fmt.Errorf("-hijri-calendar must be ummalqura or tabular")
+	}
+	if p.HijriAdjustment < -2 || p.HijriAdjustment > 2 {
+		return 0, 0, fmt.Errorf("-hijri-adjustment must be from -2 to 2")
+	}
+	return calendar, p.HijriAdjustment, nil
+}
+
+// location returns the timezone of the profile
+func (p profile) location() (*time.Location, error) {
+	if p.Timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("-timezone %q is unknown", p.Timezone)
+	}
+	return location, nil
+}
@@ -0,0 +1,198 @@
+package schedule
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ProviderComparison contains the differences between the timings of a candidate and a baseline provider over a
+// date range.  Differences are the candidate time minus the baseline time, so positive minutes are later.
+type ProviderComparison struct {
+	Latitude         float32           `json:"latitude"`
+	Longitude        float32           `json:"longitude"`
+	Start            string            `json:"start"` // YYYY-MM-DD
+	End              string            `json:"end"`   // YYYY-MM-DD
+	ToleranceMinutes float64           `json:"tolerance_minutes"`
+	Prayers          []PrayerDeviation `json:"prayers"`
+	Days             []DayDifference   `json:"days"`
+	ExceedingDays    []string          `json:"exceeding_days"`         // Days any prayer differs by more than the tolerance
+	MissingDays      []string          `json:"missing_days,omitempty"` // Days only one of the providers has timings for
+}
+
+// PrayerDeviation summarises the differences of one prayer over the compared days
+type PrayerDeviation struct {
+	Prayer      string  `json:"prayer"`
+	Days        int     `json:"days"`
+	MaxMinutes  float64 `json:"max_minutes"`  // Largest absolute difference
+	MeanMinutes float64 `json:"mean_minutes"` // Mean absolute difference
+	BiasMinutes float64 `json:"bias_minutes"` // Mean difference, positive when the candidate is later on average
+}
+
+// DayDifference contains the minute difference of every prayer on a day
+type DayDifference struct {
+	Date             string         `json:"date"` // YYYY-MM-DD
+	Minutes          map[string]int `json:"minutes"`
+	ExceedsTolerance bool           `json:"exceeds_tolerance"`
+}
+
+/*
+CompareProviders looks up the customer location from start to end inclusive with both baseline and candidate, such
+as aladhan and an imported mosque timetable, and returns how far the candidate timings are from the baseline.
+A day exceeds tolerance when any prayer differs by more than it.  Imsak is only compared when both providers have it.
+*/
+func (c *CustomerLocationInput) CompareProviders(baseline MonthProvider, candidate MonthProvider, start time.Time, end time.Time, tolerance time.Duration) (*ProviderComparison, error) {
+	lookup := func(provider MonthProvider) (*PCalRangeOutput, error) {
+		providerInput := *c
+		providerInput.Provider = provider
+		return providerInput.PrayerCalendarRange(start, end, 0)
+	}
+	baselineRange, err := lookup(baseline)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up baseline timings: %s", err)
+	}
+	candidateRange, err := lookup(candidate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up candidate timings: %s", err)
+	}
+
+	comparison := &ProviderComparison{
+		Latitude:         baselineRange.Latitude,
+		Longitude:        baselineRange.Longitude,
+		Start:            start.Format("2006-01-02"),
+		End:              end.Format("2006-01-02"),
+		ToleranceMinutes: tolerance.Minutes(),
+		ExceedingDays:    []string{},
+	}
+
+	candidateDays := make(map[string]*PCalDay, len(candidateRange.Days))
+	for i := range candidateRange.Days {
+		candidateDays[candidateRange.Days[i].Date.Gregorian.Date] = &candidateRange.Days[i]
+	}
+
+	deviations := make(map[string]*PrayerDeviation)
+	for i := range baselineRange.Days {
+		baselineDay := &baselineRange.Days[i]
+		date, err := baselineDay.Day()
+		if err != nil {
+			return nil, err
+		}
+		candidateDay, ok := candidateDays[baselineDay.Date.Gregorian.Date]
+		if !ok {
+			comparison.MissingDays = append(comparison.MissingDays, date.Format("2006-01-02"))
+			continue
+		}
+		delete(candidateDays, baselineDay.Date.Gregorian.Date)
+
+		difference := DayDifference{Date: date.Format("2006-01-02"), Minutes: make(map[string]int)}
+		for _, prayer := range prayerTimingNames {
+			baselineTiming, candidateTiming := baselineDay.Timings.timing(prayer), candidateDay.Timings.timing(prayer)
+			if prayer == "Imsak" && (baselineTiming == "" || candidateTiming == "") {
+				continue
+			}
+			minutes, err := timingDifferenceMinutes(date, baselineTiming, candidateTiming)
+			if err != nil {
+				return nil, fmt.Errorf("unable to compare %s on %s: %s", prayer, difference.Date, err)
+			}
+			difference.Minutes[prayer] = minutes
+			if math.Abs(float64(minutes)) > tolerance.Minutes() {
+				difference.ExceedsTolerance = true
+			}
+
+			deviation, ok := deviations[prayer]
+			if !ok {
+				deviation = &PrayerDeviation{Prayer: prayer}
+				deviations[prayer] = deviation
+			}
+			deviation.Days++
+			deviation.MaxMinutes = math.Max(deviation.MaxMinutes, math.Abs(float64(minutes)))
+			deviation.MeanMinutes += math.Abs(float64(minutes))
+			deviation.BiasMinutes += float64(minutes)
+		}
+
+		comparison.Days = append(comparison.Days, difference)
+		if difference.ExceedsTolerance {
+			comparison.ExceedingDays = append(comparison.ExceedingDays, difference.Date)
+		}
+	}
+
+	for i := range candidateRange.Days {
+		if _, ok := candidateDays[candidateRange.Days[i].Date.Gregorian.Date]; ok {
+			date, err := candidateRange.Days[i].Day()
+			if err != nil {
+				return nil, err
+			}
+			comparison.MissingDays = append(comparison.MissingDays, date.Format("2006-01-02"))
+		}
+	}
+
+	for _, prayer := range prayerTimingNames {
+		if deviation, ok := deviations[prayer]; ok {
+			deviation.MeanMinutes /= float64(deviation.Days)
+			deviation.BiasMinutes /= float64(deviation.Days)
+			comparison.Prayers = append(comparison.Prayers, *deviation)
+		}
+	}
+	return comparison, nil
+}
+
+// WriteJSON writes the comparison as indented JSON
+func (p *ProviderComparison) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// WriteCSV writes a row of minute differences for every compared day, with a column for each compared prayer
+func (p *ProviderComparison) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"date"}
+	for _, deviation := range p.Prayers {
+		header = append(header, deviation.Prayer)
+	}
+	header = append(header, "exceeds_tolerance")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, day := range p.Days {
+		row := []string{day.Date}
+		for _, deviation := range p.Prayers {
+			if minutes, ok := day.Minutes[deviation.Prayer]; ok {
+				row = append(row, strconv.Itoa(minutes))
+			} else {
+				row = append(row, "")
+			}
+		}
+		row = append(row, strconv.FormatBool(day.ExceedsTolerance))
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// timingDifferenceMinutes returns the minutes from the baseline timing to the candidate timing on day.  Timings on
+// either side of midnight are compared the short way round.
+func timingDifferenceMinutes(day time.Time, baseline string, candidate string) (int, error) {
+	baselineTime, err := PrayerTimeOnDay(day, baseline)
+	if err != nil {
+		return 0, err
+	}
+	candidateTime, err := PrayerTimeOnDay(day, candidate)
+	if err != nil {
+		return 0, err
+	}
+	minutes := int(math.Round(candidateTime.Sub(baselineTime).Minutes()))
+	if minutes > 12*60 {
+		minutes -= 24 * 60
+	} else if minutes < -12*60 {
+		minutes += 24 * 60
+	}
+	return minutes, nil
+}
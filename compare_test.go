@@ -0,0 +1,83 @@
+package schedule_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// driftingMonthProvider moves Isha of the test month later by the day of the month modulo 4 and drops the 10th
+type driftingMonthProvider struct {
+	testMonthProvider
+}
+
+func (p *driftingMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	output, err := p.testMonthProvider.MonthlyPrayers(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	var days []psched.PCalDay
+	for i, day := range output.Data {
+		if i == 9 {
+			continue
+		}
+		day.Timings.Isha = fmt.Sprintf("19:%02d (UTC)", 30+(i+1)%4)
+		days = append(days, day)
+	}
+	output.Data = days
+	return output, nil
+}
+
+func TestCompareProviders(t *testing.T) {
+	customer := &psched.CustomerLocationInput{Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105}}
+	start := time.Date(2022, time.October, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, time.October, 12, 0, 0, 0, 0, time.UTC)
+	comparison, err := customer.CompareProviders(&testMonthProvider{}, &driftingMonthProvider{}, start, end, 2*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(comparison.Days) != 11 || len(comparison.MissingDays) != 1 || comparison.MissingDays[0] != "2022-10-10" {
+		t.Fatalf("expected 11 compared days and 2022-10-10 missing, got %d and %v", len(comparison.Days), comparison.MissingDays)
+	}
+	if strings.Join(comparison.ExceedingDays, ",") != "2022-10-03,2022-10-07,2022-10-11" {
+		t.Errorf("unexpected days over tolerance: %v", comparison.ExceedingDays)
+	}
+
+	deviations := make(map[string]psched.PrayerDeviation)
+	for _, deviation := range comparison.Prayers {
+		deviations[deviation.Prayer] = deviation
+	}
+	if len(deviations) != 7 || deviations["Fajr"].MaxMinutes != 0 {
+		t.Errorf("unexpected deviations: %+v", comparison.Prayers)
+	}
+	// Days 1 to 12 without the 10th drift 1,2,3,0,1,2,3,0,1,3,0 minutes
+	isha := deviations["Isha"]
+	if isha.Days != 11 || isha.MaxMinutes != 3 || isha.MeanMinutes != 16.0/11 || isha.BiasMinutes != 16.0/11 {
+		t.Errorf("unexpected isha deviation: %+v", isha)
+	}
+
+	var jsonOutput bytes.Buffer
+	if err := comparison.WriteJSON(&jsonOutput); err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(psched.ProviderComparison)
+	if err := json.Unmarshal(jsonOutput.Bytes(), decoded); err != nil || len(decoded.Days) != 11 || decoded.Days[2].Minutes["Isha"] != 3 {
+		t.Errorf("json output did not round trip: %v %s", err, jsonOutput.String())
+	}
+
+	var csvOutput bytes.Buffer
+	if err := comparison.WriteCSV(&csvOutput); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOutput.String()), "\n")
+	if len(lines) != 12 || lines[0] != "date,Imsak,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha,exceeds_tolerance" || lines[3] != "2022-10-03,0,0,0,0,0,0,3,true" {
+		t.Errorf("unexpected csv output:\n%s", csvOutput.String())
+	}
+}
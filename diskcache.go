@@ -0,0 +1,200 @@
+package schedule
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// diskCacheVersion is the version of the disk cache file format.  Files of any other version are ignored
+const diskCacheVersion = 1
+
+const (
+	diskCacheCalendars = "calendars"
+	diskCacheGeocodes  = "geocodes"
+)
+
+// DiskCache stores monthly calendars and geocoding results as JSON files under Dir, surviving restarts
+type DiskCache struct {
+	Dir     string
+	MaxAge  time.Duration   // Entries older than MaxAge are looked up again.  0 keeps entries forever
+	Clock   Clock           // Time entries are stored and expire at.  Defaults to SystemClock
+	OnError func(err error) // Called when an entry cannot be written.  The looked up value is still returned
+}
+
+// diskCacheEntry is the file format of a disk cache entry
+type diskCacheEntry struct {
+	Version int             `json:"version"`
+	Key     string          `json:"key"`
+	Stored  time.Time       `json:"stored"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewDiskCache returns a DiskCache in dir, creating dir when it does not exist
+func NewDiskCache(dir string, maxAge time.Duration) (*DiskCache, error) {
+	for _, kind := range []string{diskCacheCalendars, diskCacheGeocodes} {
+		if err := os.MkdirAll(filepath.Join(dir, kind), 0o755); err != nil {
+			return nil, fmt.Errorf("unable to create disk cache: %s", err)
+		}
+	}
+	return &DiskCache{Dir: dir, MaxAge: maxAge}, nil
+}
+
+// Provider returns a MonthProvider which looks up months from provider only when they are not on disk
+func (d *DiskCache) Provider(provider MonthProvider) *DiskCachedProvider {
+	return &DiskCachedProvider{Provider: provider, Cache: d}
+}
+
+// Geocoder returns a Geocoder which looks up postal codes from geocoder only when they are not on disk
+func (d *DiskCache) Geocoder(geocoder Geocoder) *DiskCachedGeocoder {
+	return &DiskCachedGeocoder{Geocoder: geocoder, Cache: d}
+}
+
+// DiskCachedProvider is a MonthProvider backed by a DiskCache
+type DiskCachedProvider struct {
+	Provider MonthProvider
+	Cache    *DiskCache
+}
+
+// MonthlyPrayers returns the month of input from disk, looking it up from the provider when it is missing or expired.
+// Each set of calculation settings has its own entry, so customers with different settings do not replace each other's month
+func (p *DiskCachedProvider) MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error) {
+	key := fmt.Sprintf(
+		"%.*f,%.*f|%04d-%02d|%s|%s",
+		defaultCoordinatePrecision, input.Latitude,
+		defaultCoordinatePrecision, input.Longitude,
+		input.CustTime.Year(), input.CustTime.Month(),
+		input.CustTime.Location(),
+		calculationSettingsHash(input),
+	)
+
+	output := new(PCalOutput)
+	if p.Cache.read(diskCacheCalendars, key, output) {
+		return output, nil
+	}
+
+	output, err := p.Provider.MonthlyPrayers(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	p.Cache.store(diskCacheCalendars, key, output)
+	return output, nil
+}
+
+// DiskCachedGeocoder is a Geocoder backed by a DiskCache
+type DiskCachedGeocoder struct {
+	Geocoder Geocoder
+	Cache    *DiskCache
+}
+
+// Geocode returns the coordinates of postalCode from disk, looking them up from the geocoder when they are missing or expired
+func (g *DiskCachedGeocoder) Geocode(ctx context.Context, countryCode string, postalCode string) (*CustomerCoordinatesOutput, error) {
+	key := fmt.Sprintf("%s|%s", countryCode, postalCode)
+
+	coordinates := new(CustomerCoordinatesOutput)
+	if g.Cache.read(diskCacheGeocodes, key, coordinates) {
+		return coordinates, nil
+	}
+
+	coordinates, err := g.Geocoder.Geocode(ctx, countryCode, postalCode)
+	if err != nil {
+		return nil, err
+	}
+	g.Cache.store(diskCacheGeocodes, key, coordinates)
+	return coordinates, nil
+}
+
+// read decodes the entry of key into out, returning false when the entry is missing, unreadable, of another version
+// or expired
+func (d *DiskCache) read(kind string, key string, out interface{}) bool {
+	file, err := os.ReadFile(d.path(kind, key))
+	if err != nil {
+		return false
+	}
+
+	entry := new(diskCacheEntry)
+	if err := json.Unmarshal(file, entry); err != nil {
+		return false
+	}
+	if entry.Version != diskCacheVersion || entry.Key != key {
+		return false
+	}
+	if d.MaxAge > 0 && clockOrSystem(d.Clock).Now().Sub(entry.Stored) >= d.MaxAge {
+		return false
+	}
+	return json.Unmarshal(entry.Data, out) == nil
+}
+
+// store writes value as the entry of key, reporting to OnError when it cannot be written, such as when the disk is full
+func (d *DiskCache) store(kind string, key string, value interface{}) {
+	if err := d.write(kind, key, value); err != nil && d.OnError != nil {
+		d.OnError(err)
+	}
+}
+
+// write stores value as the entry of key.  The entry is written to a temporary file which is renamed over the entry,
+// so readers never see a partially written entry
+func (d *DiskCache) write(kind string, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("unable to encode disk cache entry: %s", err)
+	}
+	file, err := json.Marshal(&diskCacheEntry{
+		Version: diskCacheVersion,
+		Key:     key,
+		Stored:  clockOrSystem(d.Clock).Now().UTC(),
+		Data:    data,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode disk cache entry: %s", err)
+	}
+
+	path := d.path(kind, key)
+	temp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to write disk cache entry: %s", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(file); err != nil {
+		temp.Close()
+		return fmt.Errorf("unable to write disk cache entry: %s", err)
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("unable to write disk cache entry: %s", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("unable to write disk cache entry: %s", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("unable to write disk cache entry: %s", err)
+	}
+	return nil
+}
+
+// path returns the file of the entry of key
+func (d *DiskCache) path(kind string, key string) string {
+	return filepath.Join(d.Dir, kind, hashString(key)+".json")
+}
+
+// calculationSettingsHash returns a hash of the settings which change the prayer timings of a location
+func calculationSettingsHash(input *PCalInput) string {
+	return hashString(fmt.Sprintf(
+		"method=%d|school=%d|latitudeAdjustmentMethod=%d|offsets=%+v",
+		input.Institution,
+		input.School,
+		input.LatitudeAdjustmentMethod,
+		input.Offsets,
+	))
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,221 @@
+package schedule_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// testGeocoder returns fixed coordinates for every postal code, counting lookups
+type testGeocoder struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (g *testGeocoder) Geocode(ctx context.Context, countryCode string, postalCode string) (*psched.CustomerCoordinatesOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls++
+	return &psched.CustomerCoordinatesOutput{Lat: 34.103, Lng: -118.4105}, nil
+}
+
+func TestDiskCachedProvider(t *testing.T) {
+	dir := t.TempDir()
+	provider := &testMonthProvider{}
+	cache, err := psched.NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := cache.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new cache in the same directory, as after a restart, reads the stored month
+	restarted, err := psched.NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := restarted.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Calls() != 1 {
+		t.Errorf("expected 1 provider call, got %d", provider.Calls())
+	}
+	if len(second.Data) != len(first.Data) || second.Data[3].Timings != first.Data[3].Timings {
+		t.Errorf("stored month differs: %+v %+v", first.Data[3], second.Data[3])
+	}
+
+	// Changing the calculation settings invalidates the stored month
+	hanafi := cacheTestInput(time.October)
+	hanafi.School = 1
+	if _, err := restarted.Provider(provider).MonthlyPrayers(context.Background(), hanafi); err != nil {
+		t.Fatal(err)
+	}
+	if provider.Calls() != 2 {
+		t.Errorf("expected changed settings to look up the month again, got %d calls", provider.Calls())
+	}
+	if _, err := restarted.Provider(provider).MonthlyPrayers(context.Background(), hanafi); err != nil {
+		t.Fatal(err)
+	}
+	if provider.Calls() != 2 {
+		t.Errorf("expected the month of the new settings to be stored, got %d calls", provider.Calls())
+	}
+	// The month of the earlier settings is kept next to it rather than replaced
+	if _, err := restarted.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October)); err != nil {
+		t.Fatal(err)
+	}
+	if provider.Calls() != 2 {
+		t.Errorf("expected the months of both settings to be stored, got %d calls", provider.Calls())
+	}
+
+	// No temporary files are left behind
+	files, _ := filepath.Glob(filepath.Join(dir, "*", ".tmp-*"))
+	if len(files) != 0 {
+		t.Errorf("temporary files were left behind: %v", files)
+	}
+}
+
+func TestDiskCacheIgnoresInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	provider := &testMonthProvider{}
+	cache, err := psched.NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October)); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "calendars", "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 stored month, got %v", files)
+	}
+	contents, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, invalid := range []string{
+		strings.Replace(string(contents), `"version":1`, `"version":99`, 1),
+		string(contents[:len(contents)/2]),
+	} {
+		if err := os.WriteFile(files[0], []byte(invalid), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		calls := provider.Calls()
+		if _, err := cache.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October)); err != nil {
+			t.Fatal(err)
+		}
+		if provider.Calls() != calls+1 {
+			t.Errorf("invalid file was not ignored: %.40q", invalid)
+		}
+	}
+}
+
+func TestDiskCacheMaxAge(t *testing.T) {
+	provider := &testMonthProvider{}
+	cache, err := psched.NewDiskCache(t.TempDir(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 0, 0, 0, time.UTC))
+	cache.Clock = clock
+
+	cache.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	clock.Advance(23 * time.Hour)
+	cache.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if provider.Calls() != 1 {
+		t.Fatalf("expected a fresh month to be read from disk, got %d calls", provider.Calls())
+	}
+	clock.Advance(time.Hour)
+	cache.Provider(provider).MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if provider.Calls() != 2 {
+		t.Errorf("expected an expired month to be looked up again, got %d calls", provider.Calls())
+	}
+}
+
+func TestDiskCachedGeocoder(t *testing.T) {
+	dir := t.TempDir()
+	geocoder := &testGeocoder{}
+	cache, err := psched.NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customer := &psched.CustomerLocationInput{
+		CountryCode: "US",
+		CustTime:    time.Date(2022, time.October, 22, 10, 10, 0, 0, time.UTC),
+		PostalCode:  "90210",
+		Institution: 2,
+		Provider:    &testMonthProvider{},
+		Geocoder:    cache.Geocoder(geocoder),
+	}
+	for i := 0; i < 2; i++ {
+		output, err := customer.PrayerCalendar()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.Latitude != 34.103 || output.Longitude != -118.4105 {
+			t.Errorf("unexpected coordinates: %v %v", output.Latitude, output.Longitude)
+		}
+	}
+	if geocoder.calls != 1 {
+		t.Errorf("expected 1 geocoder call, got %d", geocoder.calls)
+	}
+
+	restarted, _ := psched.NewDiskCache(dir, 0)
+	coordinates, err := restarted.Geocoder(geocoder).Geocode(context.Background(), "US", "90210")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if geocoder.calls != 1 || coordinates.Lat != 34.103 {
+		t.Errorf("stored coordinates were not used: %+v after %d calls", coordinates, geocoder.calls)
+	}
+}
+
+func TestDiskCacheWriteFailure(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := psched.NewDiskCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs []error
+	cache.OnError = func(err error) { errs = append(errs, err) }
+
+	// Entries cannot be written once the entry directories are replaced by files
+	for _, kind := range []string{"calendars", "geocodes"} {
+		if err := os.RemoveAll(filepath.Join(dir, kind)); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, kind), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output, err := cache.Provider(&testMonthProvider{}).MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if err != nil {
+		t.Fatalf("month lookup failed when the entry could not be written: %s", err)
+	}
+	if len(output.Data) == 0 {
+		t.Error("looked up month has no days")
+	}
+	coordinates, err := cache.Geocoder(&testGeocoder{}).Geocode(context.Background(), "US", "90210")
+	if err != nil {
+		t.Fatalf("geocode failed when the entry could not be written: %s", err)
+	}
+	if coordinates.Lat != 34.103 {
+		t.Errorf("unexpected coordinates: %+v", coordinates)
+	}
+	if len(errs) != 2 {
+		t.Errorf("expected 2 write errors to be reported, got %v", errs)
+	}
+}
@@ -0,0 +1,143 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Names of the Islamic events returned by IslamicEventsForHijriYear
+const (
+	EventIslamicNewYear = "Islamic New Year"
+	EventAshura         = "Ashura"
+	EventMawlid         = "Mawlid"
+	EventRamadanStart   = "Ramadan Start"
+	EventLaylatAlQadr   = "Laylat al-Qadr"
+	EventEidAlFitr      = "Eid al-Fitr"
+	EventDayOfArafah    = "Day of Arafah"
+	EventEidAlAdha      = "Eid al-Adha"
+	EventWhiteDay       = "White Day" // 13th to 15th of each Hijri month
+)
+
+// IslamicEvent is an Islamic observance and the Gregorian day it falls on
+type IslamicEvent struct {
+	Name  string    `json:"name"`
+	Hijri HijriDate `json:"hijri"`
+	// Date is the Gregorian day of the observance.  For a Night event it is the day on whose evening the night begins
+	Date  time.Time `json:"date"`
+	Night bool      `json:"night"`
+}
+
+// islamicEventDays are the fixed Hijri days of the yearly observances
+var islamicEventDays = []struct {
+	name  string
+	month int
+	day   int
+	night bool
+}{
+	{EventIslamicNewYear, 1, 1, false},
+	{EventAshura, 1, 10, false},
+	{EventMawlid, 3, 12, false},
+	{EventRamadanStart, 9, 1, false},
+	{EventLaylatAlQadr, 9, 21, true},
+	{EventLaylatAlQadr, 9, 23, true},
+	{EventLaylatAlQadr, 9, 25, true},
+	{EventLaylatAlQadr, 9, 27, true},
+	{EventLaylatAlQadr, 9, 29, true},
+	{EventEidAlFitr, 10, 1, false},
+	{EventDayOfArafah, 12, 9, false},
+	{EventEidAlAdha, 12, 10, false},
+}
+
+/*
+IslamicEventsForHijriYear returns the Islamic events of a Hijri year in date order.  The white days are the 13th to
+15th of each Hijri month, including 13 Dhu al-Hijjah, which is also one of the days of Tashreeq.
+*/
+func IslamicEventsForHijriYear(year int, calendar HijriCalendar, adjustment int) ([]IslamicEvent, error) {
+	var events []IslamicEvent
+	add := func(name string, hijri HijriDate, night bool) error {
+		date, err := hijri.ToGregorian(calendar, adjustment, time.UTC)
+		if err != nil {
+			return fmt.Errorf("unable to convert %s %s to gregorian: %s", name, hijri, err)
+		}
+		if night {
+			// The Islamic day, and so the night, begins at Maghrib of the previous Gregorian day
+			date = date.AddDate(0, 0, -1)
+		}
+		events = append(events, IslamicEvent{Name: name, Hijri: hijri, Date: date, Night: night})
+		return nil
+	}
+
+	for _, event := range islamicEventDays {
+		if err := add(event.name, HijriDate{Year: year, Month: event.month, Day: event.day}, event.night); err != nil {
+			return nil, err
+		}
+	}
+
+	for month := 1; month <= 12; month++ {
+		for day := 13; day <= 15; day++ {
+			if err := add(EventWhiteDay, HijriDate{Year: year, Month: month, Day: day}, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+	return events, nil
+}
+
+// IslamicEventsForGregorianYear returns the Islamic events falling within a Gregorian year in date order
+func IslamicEventsForGregorianYear(year int, calendar HijriCalendar, adjustment int) ([]IslamicEvent, error) {
+	firstDay, err := ToHijri(time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), calendar, adjustment)
+	if err != nil {
+		return nil, err
+	}
+	lastDay, err := ToHijri(time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC), calendar, adjustment)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []IslamicEvent
+	for hijriYear := firstDay.Year; hijriYear <= lastDay.Year; hijriYear++ {
+		yearEvents, err := IslamicEventsForHijriYear(hijriYear, calendar, adjustment)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range yearEvents {
+			if event.Date.Year() == year {
+				events = append(events, event)
+			}
+		}
+	}
+	return events, nil
+}
+
+// SetIslamicEvents attaches the Islamic events of each day to the days of the month
+func (p *PCalOutput) SetIslamicEvents(calendar HijriCalendar, adjustment int) error {
+	eventsByYear := make(map[int][]IslamicEvent)
+	for i := range p.Data {
+		day, err := p.Data[i].Day()
+		if err != nil {
+			return err
+		}
+
+		events, ok := eventsByYear[day.Year()]
+		if !ok {
+			events, err = IslamicEventsForGregorianYear(day.Year(), calendar, adjustment)
+			if err != nil {
+				return err
+			}
+			eventsByYear[day.Year()] = events
+		}
+
+		p.Data[i].Events = nil
+		for _, event := range events {
+			if event.Date.Year() == day.Year() && event.Date.YearDay() == day.YearDay() {
+				p.Data[i].Events = append(p.Data[i].Events, event)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func TestIslamicEventsForHijriYear(t *testing.T) {
+	events, err := psched.IslamicEventsForHijriYear(1444, psched.UmmAlQura, 0)
+	if err != nil {
+		t.Fatalf("unable to list islamic events: %s", err)
+	}
+
+	want := map[string]time.Time{
+		psched.EventRamadanStart: time.Date(2023, time.March, 23, 0, 0, 0, 0, time.UTC),
+		psched.EventEidAlFitr:    time.Date(2023, time.April, 21, 0, 0, 0, 0, time.UTC),
+		psched.EventDayOfArafah:  time.Date(2023, time.June, 27, 0, 0, 0, 0, time.UTC),
+		psched.EventEidAlAdha:    time.Date(2023, time.June, 28, 0, 0, 0, 0, time.UTC),
+	}
+
+	var qadrNights, whiteDays int
+	for i, event := range events {
+		if i > 0 && event.Date.Before(events[i-1].Date) {
+			t.Errorf("%s on %s is not in date order", event.Name, event.Date.Format("2006-01-02"))
+		}
+		switch event.Name {
+		case psched.EventLaylatAlQadr:
+			qadrNights++
+			if !event.Night {
+				t.Errorf("laylat al-qadr of %s is not marked as a night", event.Hijri)
+			}
+			// The night of the 27th begins on the evening of the 26th
+			if event.Hijri.Day == 27 && !event.Date.Equal(time.Date(2023, time.April, 17, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("27th night of Ramadan begins on %s, want 2023-04-17", event.Date.Format("2006-01-02"))
+			}
+		case psched.EventWhiteDay:
+			whiteDays++
+		}
+		if date, ok := want[event.Name]; ok && !event.Date.Equal(date) {
+			t.Errorf("%s is on %s, want %s", event.Name, event.Date.Format("2006-01-02"), date.Format("2006-01-02"))
+		}
+	}
+
+	if qadrNights != 5 {
+		t.Errorf("found %d laylat al-qadr nights, want 5", qadrNights)
+	}
+	// Three white days in each month
+	if whiteDays != 36 {
+		t.Errorf("found %d white days, want 36", whiteDays)
+	}
+}
+
+func TestIslamicEventsForGregorianYear(t *testing.T) {
+	events, err := psched.IslamicEventsForGregorianYear(2023, psched.UmmAlQura, 1)
+	if err != nil {
+		t.Fatalf("unable to list islamic events: %s", err)
+	}
+
+	var ashura []psched.IslamicEvent
+	for _, event := range events {
+		if event.Date.Year() != 2023 {
+			t.Errorf("%s on %s is outside of 2023", event.Name, event.Date.Format("2006-01-02"))
+		}
+		if event.Name == psched.EventAshura {
+			ashura = append(ashura, event)
+		}
+	}
+
+	// An adjustment of 1 moves each event one day earlier.  Ashura 1445 is on the 28th of July 2023 without it
+	if len(ashura) != 1 || !ashura[0].Date.Equal(time.Date(2023, time.July, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("adjusted ashura events in 2023 are %v, want only 2023-07-27", ashura)
+	}
+}
+
+func TestSetIslamicEvents(t *testing.T) {
+	month := iqamahTestMonth()
+	if err := month.SetIslamicEvents(psched.UmmAlQura, 0); err != nil {
+		t.Fatalf("unable to set islamic events: %s", err)
+	}
+
+	// The 1st to 7th of October 2022 are 5 to 11 Rabi al-Awwal 1444, which have no events
+	for i := range month.Data {
+		if len(month.Data[i].Events) != 0 {
+			t.Errorf("day %d has unexpected events %v", i+1, month.Data[i].Events)
+		}
+	}
+
+	// 8th of October 2022 is 12 Rabi al-Awwal 1444
+	month.Data[6].Date.Gregorian.Date = "08-10-2022"
+	if err := month.SetIslamicEvents(psched.UmmAlQura, 0); err != nil {
+		t.Fatalf("unable to set islamic events: %s", err)
+	}
+	if len(month.Data[6].Events) != 1 || month.Data[6].Events[0].Name != psched.EventMawlid {
+		t.Errorf("8th of October 2022 events are %v, want %s", month.Data[6].Events, psched.EventMawlid)
+	}
+}
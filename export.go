@@ -0,0 +1,245 @@
+package schedule
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ExportSchemaVersion is the version of the JSON export schema.  It changes whenever a field changes meaning or is removed
+const ExportSchemaVersion = 1
+
+const (
+	exportIqamahSuffix      = " Iqamah"
+	defaultExportDateLayout = "2006-01-02"
+)
+
+// defaultExportColumns are the CSV columns written when ExportOptions.Columns is empty
+var defaultExportColumns = []string{"Date", "Hijri", "Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// ExportOptions configures the CSV and JSON exports of prayer timings
+type ExportOptions struct {
+	Columns        []string     // CSV columns: "Date", "Hijri", a timing such as "Fajr", or an iqamah such as "Fajr Iqamah".  Defaults to the date, Hijri date and every prayer
+	DateLayout     string       // Go time layout of dates.  Defaults to 2006-01-02
+	TwelveHour     bool         // Write times such as 5:38 PM instead of 17:38
+	TimezoneSuffix bool         // Write the timezone after each time, such as 17:38 (PDT)
+	Iqamah         *IqamahRules // Rules of the iqamah columns and JSON iqamah times
+	IqamahAdjacent []PCalDay    // Days around the exported days, such as the rest of the first and last week, only used to keep weekly iqamah times across the whole week
+}
+
+// ExportDocument is the versioned JSON export of prayer timings
+type ExportDocument struct {
+	SchemaVersion int         `json:"schema_version"`
+	Timezone      string      `json:"timezone"`
+	Latitude      float64     `json:"latitude"`
+	Longitude     float64     `json:"longitude"`
+	Days          []ExportDay `json:"days"`
+}
+
+// ExportDay is a day of an ExportDocument
+type ExportDay struct {
+	Date      string         `json:"date"`
+	Hijri     string         `json:"hijri"`
+	HijriDate HijriDate      `json:"hijri_date"`
+	Timings   ExportTimings  `json:"timings"`
+	Iqamah    *ExportTimings `json:"iqamah,omitempty"`
+	Events    []string       `json:"events,omitempty"`
+}
+
+// ExportTimings are the formatted times of a day.  Timings a day does not have are left out
+type ExportTimings struct {
+	Imsak   string `json:"imsak,omitempty"`
+	Fajr    string `json:"fajr,omitempty"`
+	Sunrise string `json:"sunrise,omitempty"`
+	Dhuhr   string `json:"dhuhr,omitempty"`
+	Asr     string `json:"asr,omitempty"`
+	Maghrib string `json:"maghrib,omitempty"`
+	Isha    string `json:"isha,omitempty"`
+}
+
+// WriteCSV writes the month as CSV
+func (p *PCalOutput) WriteCSV(w io.Writer, options ExportOptions) error {
+	return WriteCSV(w, p.Data, options)
+}
+
+// WriteJSON writes the month as a versioned JSON document
+func (p *PCalOutput) WriteJSON(w io.Writer, options ExportOptions) error {
+	return WriteJSON(w, p.Data, options)
+}
+
+// WriteCSV writes the range as CSV
+func (r *PCalRangeOutput) WriteCSV(w io.Writer, options ExportOptions) error {
+	return WriteCSV(w, r.Days, options)
+}
+
+// WriteJSON writes the range as a versioned JSON document
+func (r *PCalRangeOutput) WriteJSON(w io.Writer, options ExportOptions) error {
+	return WriteJSON(w, r.Days, options)
+}
+
+/*
+WriteCSV writes a header row and a row for every day with the columns of options.  Written with the default date
+layout and the header names of the timings, the file is read back by ImportCSVTimetable unchanged.
+*/
+func WriteCSV(w io.Writer, days []PCalDay, options ExportOptions) error {
+	columns := options.Columns
+	if len(columns) == 0 {
+		columns = defaultExportColumns
+	}
+	for _, column := range columns {
+		prayer := strings.TrimSuffix(column, exportIqamahSuffix)
+		if column != "Date" && column != "Hijri" && !(&FiveDailyPrayers{}).setTiming(prayer, "") {
+			return fmt.Errorf("%s is not an export column", column)
+		}
+		if strings.HasSuffix(column, exportIqamahSuffix) && options.Iqamah == nil {
+			return fmt.Errorf("%s column requires iqamah rules", column)
+		}
+	}
+
+	exported, err := exportDays(days, options)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, day := range exported {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			switch {
+			case column == "Date":
+				row[i] = day.Date
+			case column == "Hijri":
+				row[i] = day.Hijri
+			case strings.HasSuffix(column, exportIqamahSuffix):
+				row[i] = day.Iqamah.timing(strings.TrimSuffix(column, exportIqamahSuffix))
+			default:
+				row[i] = day.Timings.timing(column)
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes days as an indented ExportDocument
+func WriteJSON(w io.Writer, days []PCalDay, options ExportOptions) error {
+	exported, err := exportDays(days, options)
+	if err != nil {
+		return err
+	}
+	document := ExportDocument{SchemaVersion: ExportSchemaVersion, Days: exported}
+	if len(days) > 0 {
+		document.Timezone = days[0].Meta.Timezone
+		document.Latitude = days[0].Meta.Latitude
+		document.Longitude = days[0].Meta.Longitude
+	}
+	if document.Days == nil {
+		document.Days = []ExportDay{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// exportDays formats days for export
+func exportDays(days []PCalDay, options ExportOptions) ([]ExportDay, error) {
+	dateLayout := options.DateLayout
+	if dateLayout == "" {
+		dateLayout = defaultExportDateLayout
+	}
+	timeLayout := "15:04"
+	if options.TwelveHour {
+		timeLayout = "3:04 PM"
+	}
+	if options.TimezoneSuffix {
+		timeLayout += " (MST)"
+	}
+
+	var iqamah *IqamahTimetable
+	if options.Iqamah != nil {
+		var err error
+		if iqamah, err = options.Iqamah.TimetableWithAdjacent(&PCalOutput{Data: days}, &PCalOutput{Data: options.IqamahAdjacent}); err != nil {
+			return nil, err
+		}
+	}
+
+	exported := make([]ExportDay, len(days))
+	for i := range days {
+		date, err := days[i].Day()
+		if err != nil {
+			return nil, err
+		}
+		hijri, err := days[i].hijriDate(date)
+		if err != nil {
+			return nil, err
+		}
+		exported[i] = ExportDay{Date: date.Format(dateLayout), Hijri: hijri.String(), HijriDate: hijri}
+		for _, event := range days[i].Events {
+			exported[i].Events = append(exported[i].Events, event.Name)
+		}
+
+		if exported[i].Timings, err = exportTimings(date, &days[i].Timings, timeLayout); err != nil {
+			return nil, err
+		}
+		if iqamah != nil {
+			iqamahTimings, err := exportTimings(date, &iqamah.Days[i].Iqamah, timeLayout)
+			if err != nil {
+				return nil, err
+			}
+			exported[i].Iqamah = &iqamahTimings
+		}
+	}
+	return exported, nil
+}
+
+// exportTimings formats every timing of timings on date with layout
+func exportTimings(date time.Time, timings *FiveDailyPrayers, layout string) (ExportTimings, error) {
+	var formatted FiveDailyPrayers
+	for _, prayer := range prayerTimingNames {
+		timing := timings.timing(prayer)
+		if timing == "" {
+			continue
+		}
+		prayerTime, err := PrayerTimeOnDay(date, timing)
+		if err != nil {
+			return ExportTimings{}, fmt.Errorf("unable to read %s on %s: %s", prayer, date.Format("2006-01-02"), err)
+		}
+		formatted.setTiming(prayer, prayerTime.Format(layout))
+	}
+	return ExportTimings{
+		Imsak:   formatted.Imsak,
+		Fajr:    formatted.Fajr,
+		Sunrise: formatted.Sunrise,
+		Dhuhr:   formatted.Dhuhr,
+		Asr:     formatted.Asr,
+		Maghrib: formatted.Maghrib,
+		Isha:    formatted.Isha,
+	}, nil
+}
+
+// timing returns the formatted timing called name, or an empty string when there is no such timing
+func (e *ExportTimings) timing(name string) string {
+	if e == nil {
+		return ""
+	}
+	timings := FiveDailyPrayers{
+		Imsak:   e.Imsak,
+		Fajr:    e.Fajr,
+		Sunrise: e.Sunrise,
+		Dhuhr:   e.Dhuhr,
+		Asr:     e.Asr,
+		Maghrib: e.Maghrib,
+		Isha:    e.Isha,
+	}
+	return timings.timing(name)
+}
@@ -0,0 +1,160 @@
+package schedule_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func exportTestMonth(t *testing.T) (*psched.PCalOutput, *time.Location) {
+	t.Helper()
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	month, err := (&testMonthProvider{location: location}).MonthlyPrayers(context.Background(), &psched.PCalInput{
+		CustTime:  time.Date(2022, time.October, 1, 0, 0, 0, 0, location),
+		Latitude:  34.103,
+		Longitude: -118.4105,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return month, location
+}
+
+func TestWriteCSV(t *testing.T) {
+	month, _ := exportTestMonth(t)
+	var output bytes.Buffer
+	err := month.WriteCSV(&output, psched.ExportOptions{
+		Columns:    []string{"Date", "Hijri", "Fajr", "Maghrib", "Maghrib Iqamah"},
+		DateLayout: "02/01/2006",
+		TwelveHour: true,
+		Iqamah:     &psched.IqamahRules{Maghrib: &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 5}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 32 || lines[0] != "Date,Hijri,Fajr,Maghrib,Maghrib Iqamah" {
+		t.Fatalf("unexpected csv header or length:\n%s", output.String())
+	}
+	if lines[1] != "01/10/2022,5 Rabi al-Awwal 1444 AH,5:10 AM,6:01 PM,6:06 PM" {
+		t.Errorf("unexpected first row: %s", lines[1])
+	}
+
+	for _, options := range []psched.ExportOptions{
+		{Columns: []string{"Date", "Tahajjud"}},
+		{Columns: []string{"Date", "Fajr Iqamah"}},
+	} {
+		if err := month.WriteCSV(&output, options); err == nil {
+			t.Errorf("columns %v did not return an error", options.Columns)
+		}
+	}
+}
+
+func TestCSVExportImportRoundTrip(t *testing.T) {
+	month, location := exportTestMonth(t)
+	columns := []string{"Date", "Imsak", "Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+	for _, options := range []psched.ExportOptions{
+		{Columns: columns},
+		{Columns: columns, TwelveHour: true},
+		{Columns: columns, TimezoneSuffix: true},
+		{Columns: columns, TwelveHour: true, TimezoneSuffix: true},
+	} {
+		var exported bytes.Buffer
+		if err := month.WriteCSV(&exported, options); err != nil {
+			t.Fatal(err)
+		}
+		timetable, err := psched.ImportCSVTimetable(bytes.NewReader(exported.Bytes()), psched.CSVTimetableFormat{}, location)
+		if err != nil {
+			t.Fatalf("unable to import export with %+v: %s", options, err)
+		}
+		if len(timetable.Days) != len(month.Data) {
+			t.Fatalf("expected %d imported days, got %d", len(month.Data), len(timetable.Days))
+		}
+		for i := range month.Data {
+			if timetable.Days[i].Timings != month.Data[i].Timings || timetable.Days[i].Date.Gregorian.Date != month.Data[i].Date.Gregorian.Date {
+				t.Fatalf("day %d changed with %+v: %+v became %+v", i+1, options, month.Data[i].Timings, timetable.Days[i].Timings)
+			}
+		}
+
+		var reexported bytes.Buffer
+		if err := psched.WriteCSV(&reexported, timetable.Days, options); err != nil {
+			t.Fatal(err)
+		}
+		if reexported.String() != exported.String() {
+			t.Errorf("export of the import differs with %+v", options)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	month, _ := exportTestMonth(t)
+	var output bytes.Buffer
+	err := month.WriteJSON(&output, psched.ExportOptions{
+		TimezoneSuffix: true,
+		Iqamah:         &psched.IqamahRules{Isha: &psched.IqamahRule{Type: psched.IqamahFixed, Time: "20:00"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := new(psched.ExportDocument)
+	if err := json.Unmarshal(output.Bytes(), document); err != nil {
+		t.Fatal(err)
+	}
+	if document.SchemaVersion != psched.ExportSchemaVersion || document.Timezone != "America/Los_Angeles" || len(document.Days) != 31 {
+		t.Fatalf("unexpected document: version %d timezone %s with %d days", document.SchemaVersion, document.Timezone, len(document.Days))
+	}
+	first := document.Days[0]
+	if first.Date != "2022-10-01" || first.HijriDate != (psched.HijriDate{Year: 1444, Month: 3, Day: 5}) {
+		t.Errorf("unexpected date: %s %+v", first.Date, first.HijriDate)
+	}
+	if first.Timings.Fajr != "05:10 (PDT)" || first.Timings.Imsak != "05:00 (PDT)" {
+		t.Errorf("unexpected timings: %+v", first.Timings)
+	}
+	if first.Iqamah == nil || first.Iqamah.Isha != "20:00 (PDT)" || first.Iqamah.Fajr != "" {
+		t.Errorf("unexpected iqamah: %+v", first.Iqamah)
+	}
+	if !strings.Contains(output.String(), `"schema_version": 1`) {
+		t.Errorf("schema version is not written:\n%.200s", output.String())
+	}
+}
+
+func TestWriteJSONWeeklyIqamahAdjacent(t *testing.T) {
+	// With a Friday week start, Friday the 30th of September 2022 begins the week of the 1st to the 6th of October
+	september := psched.PCalDay{Timings: psched.FiveDailyPrayers{Fajr: "05:50 (PDT)"}}
+	september.Date.Gregorian.Date = "30-09-2022"
+	september.Meta.Timezone = "America/Los_Angeles"
+
+	var output bytes.Buffer
+	err := psched.WriteJSON(&output, iqamahTestMonth().Data, psched.ExportOptions{
+		Iqamah: &psched.IqamahRules{
+			Fajr:      &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 10, Boundary: psched.IqamahWeekly},
+			WeekStart: time.Friday,
+		},
+		IqamahAdjacent: []psched.PCalDay{september},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	document := new(psched.ExportDocument)
+	if err := json.Unmarshal(output.Bytes(), document); err != nil {
+		t.Fatal(err)
+	}
+	if len(document.Days) != 7 {
+		t.Fatalf("document has %d days, want the 7 exported days", len(document.Days))
+	}
+	if iqamah := document.Days[0].Iqamah; iqamah == nil || iqamah.Fajr != "06:00" {
+		t.Errorf("weekly iqamah on the 1st of October is %+v, want 06:00 as on the 30th of September", iqamah)
+	}
+}
@@ -0,0 +1,57 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FallbackStep is a provider tried by a FallbackProvider
+type FallbackStep struct {
+	Provider MonthProvider
+	Timeout  time.Duration // Longest the provider is given before the next step is tried.  0 for no limit
+}
+
+/*
+FallbackProvider is a MonthProvider which tries each step in order until one returns the month, such as aladhan
+and then a LocalProvider.  When every step fails the expired month held by StaleCache is returned with Stale set.
+The Source of the output records which provider produced it.
+*/
+type FallbackProvider struct {
+	Steps      []FallbackStep
+	StaleCache *CachedProvider // Optional
+}
+
+// MonthlyPrayers returns the month of input from the first step which succeeds
+func (f *FallbackProvider) MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error) {
+	var failures []string
+	for i, step := range f.Steps {
+		output, err := f.try(ctx, step, input)
+		if err == nil {
+			return output, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		failures = append(failures, fmt.Sprintf("step %d: %s", i+1, err))
+	}
+
+	if f.StaleCache != nil {
+		if output, ok := f.StaleCache.Stale(input); ok {
+			output.Stale = true
+			return output, nil
+		}
+		failures = append(failures, "stale cache: month is not cached")
+	}
+	return nil, fmt.Errorf("every prayer calendar provider failed: %s", strings.Join(failures, "; "))
+}
+
+func (f *FallbackProvider) try(ctx context.Context, step FallbackStep, input *PCalInput) (*PCalOutput, error) {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+	return step.Provider.MonthlyPrayers(ctx, input)
+}
@@ -0,0 +1,78 @@
+package schedule_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// failingMonthProvider fails every lookup, after waiting for delay or the context
+type failingMonthProvider struct {
+	delay time.Duration
+}
+
+func (p *failingMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(p.delay):
+		return nil, errors.New("provider is down")
+	}
+}
+
+func TestFallbackProvider(t *testing.T) {
+	healthy := &testMonthProvider{}
+	fallback := &psched.FallbackProvider{Steps: []psched.FallbackStep{
+		{Provider: &failingMonthProvider{delay: time.Hour}, Timeout: 20 * time.Millisecond},
+		{Provider: &failingMonthProvider{}},
+		{Provider: &psched.LocalProvider{}},
+		{Provider: healthy},
+	}}
+
+	start := time.Now()
+	output, err := fallback.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("the first step was not timed out, took %s", time.Since(start))
+	}
+	if output.Source != psched.SourceLocal || output.Stale {
+		t.Errorf("expected a fresh month from the local provider, got source %q stale %v", output.Source, output.Stale)
+	}
+	if healthy.Calls() != 0 {
+		t.Error("steps after the first success were tried")
+	}
+}
+
+func TestFallbackProviderStale(t *testing.T) {
+	upstream := &testMonthProvider{}
+	cache, err := psched.NewCachedProvider(upstream, 10, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.MonthlyPrayers(context.Background(), cacheTestInput(time.October)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	cache.Provider = &failingMonthProvider{}
+	fallback := &psched.FallbackProvider{
+		Steps:      []psched.FallbackStep{{Provider: cache}, {Provider: &failingMonthProvider{}}},
+		StaleCache: cache,
+	}
+	output, err := fallback.MonthlyPrayers(context.Background(), cacheTestInput(time.October))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !output.Stale || len(output.Data) != 31 {
+		t.Errorf("expected the expired cached month, got stale %v with %d days", output.Stale, len(output.Data))
+	}
+
+	if _, err := fallback.MonthlyPrayers(context.Background(), cacheTestInput(time.November)); err == nil {
+		t.Error("a month missing from every step and the cache did not return an error")
+	}
+}
@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Geocoder returns the coordinates of a postal code within a country
+type Geocoder interface {
+	Geocode(ctx context.Context, countryCode string, postalCode string) (*CustomerCoordinatesOutput, error)
+}
+
+// HEREGeocoder is a Geocoder which looks up postal codes with the HERE geocoding API
+type HEREGeocoder struct {
+	APIKey string
+}
+
+// Geocode returns the coordinates of the HERE address matching postalCode
+func (h *HEREGeocoder) Geocode(ctx context.Context, countryCode string, postalCode string) (*CustomerCoordinatesOutput, error) {
+	hereLookup := &CustomerLocationInputWithHEREAPIKey{
+		HEREAPIKey:  h.APIKey,
+		CountryCode: countryCode,
+		PostalCode:  postalCode,
+	}
+	// Errors leave out hereLookup and the HERE response, as they carry the API key and are passed on to callers
+	hereResp, hereCity, err := HERECustomerLocationContext(ctx, hereLookup)
+	if err != nil {
+		return nil, fmt.Errorf("unable to lookup postal code %s in %s with HERE: %w", postalCode, countryCode, err)
+	}
+
+	if hereCity.Coordiantes.Lat == 0 && hereCity.Coordiantes.Lng == 0 {
+		for i := 0; i < len(hereResp.Items); i++ {
+			if hereResp.Items[i].Address.PostalCode == postalCode {
+				return &hereResp.Items[i].Position, nil
+			}
+		}
+		return nil, fmt.Errorf("unable to pinpoint customer location based on postal code %s in %s", postalCode, countryCode)
+	}
+	return &hereCity.Coordiantes, nil
+}
+
+// geocoder returns the customer Geocoder, falling back to HERE with the customer API key
+func (c *CustomerLocationInput) geocoder() Geocoder {
+	if c.Geocoder != nil {
+		return c.Geocoder
+	}
+	return &HEREGeocoder{APIKey: c.HEREAPIKey}
+}
+
+// CachedGeocoder is a Geocoder which keeps the most recently looked up postal codes of another geocoder in memory.
+// The zero value with a Geocoder holds 1024 postal codes which never expire
+type CachedGeocoder struct {
+	Geocoder Geocoder
+	Clock    Clock         // Time postal codes are stored and expire at.  Defaults to SystemClock
+	Timeout  time.Duration // Longest a lookup from the geocoder is given.  Defaults to DefaultCachedLookupTimeout
+
+	cache lruCache
+}
+
+// NewCachedGeocoder returns a CachedGeocoder holding at most size postal codes of geocoder for ttl.  A ttl of 0 never expires postal codes
+func NewCachedGeocoder(geocoder Geocoder, size int, ttl time.Duration) (*CachedGeocoder, error) {
+	if geocoder == nil {
+		return nil, fmt.Errorf("cached geocoder requires a geocoder")
+	}
+	if err := validateCacheSettings(size, ttl); err != nil {
+		return nil, err
+	}
+	return &CachedGeocoder{Geocoder: geocoder, cache: lruCache{size: size, ttl: ttl}}, nil
+}
+
+// Geocode returns the cached coordinates of postalCode, looking them up from the geocoder when they are missing or expired
+func (g *CachedGeocoder) Geocode(ctx context.Context, countryCode string, postalCode string) (*CustomerCoordinatesOutput, error) {
+	key := fmt.Sprintf("%s|%s", countryCode, postalCode)
+	coordinates, err := g.cache.lookup(ctx, key, g.Clock, g.Timeout, func(lookupCtx context.Context) (interface{}, error) {
+		coordinates, err := g.Geocoder.Geocode(lookupCtx, countryCode, postalCode)
+		if err != nil {
+			return nil, err
+		}
+		return *coordinates, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := coordinates.(CustomerCoordinatesOutput)
+	return &output, nil
+}
@@ -2,4 +2,19 @@ module github.com/moali87/prayer-schedule
 
 go 1.19
 
-require github.com/mitchellh/mapstructure v1.5.0
+require (
+	github.com/eclipse/paho.mqtt.golang v1.4.3
+	github.com/mitchellh/mapstructure v1.5.0
+	golang.org/x/sync v0.11.0
+	google.golang.org/grpc v1.59.0
+	google.golang.org/protobuf v1.31.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/gorilla/websocket v1.5.0 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230822172742-b8732ec3820d // indirect
+)
@@ -2,6 +2,7 @@
 package schedule
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -46,6 +47,11 @@ type HERECustomerLocationOutput struct {
 
 // HERECustomerLocation Returns customer location data to the nearest city
 func HERECustomerLocation(hereRequestParamaters *CustomerLocationInputWithHEREAPIKey) (*HERECustomerLocationOutput, *HERECustomerCityAddressOutput, error) {
+	return HERECustomerLocationContext(context.Background(), hereRequestParamaters)
+}
+
+// HERECustomerLocationContext is HERECustomerLocation with a context cancelling the HERE request
+func HERECustomerLocationContext(ctx context.Context, hereRequestParamaters *CustomerLocationInputWithHEREAPIKey) (*HERECustomerLocationOutput, *HERECustomerCityAddressOutput, error) {
 	resp := new(HERECustomerLocationOutput)
     var countryCode string
     countryCode = hereRequestParamaters.CountryCode
@@ -59,8 +65,15 @@ func HERECustomerLocation(hereRequestParamaters *CustomerLocationInputWithHEREAP
 		hereRequestParamaters.HEREAPIKey,
 	)
 
-	req, err := http.Get(reqURL)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
+		return resp, nil, fmt.Errorf("Unable to create customer location request")
+	}
+	req, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return resp, nil, ctx.Err()
+		}
 		errMsg := fmt.Errorf("Unable to retrieve customer location")
 		return resp, nil, errMsg
 	}
@@ -1,7 +1,10 @@
 package schedule_test
 
 import (
+	"context"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	psched "github.com/moali87/prayer-schedule"
@@ -27,3 +30,17 @@ func TestHEREAPI(t *testing.T) {
 		t.Errorf("Customer lookup returned with no locations %v", custLocRet.Items)
 	}
 }
+
+func TestGeocodeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	geocoder := &psched.HEREGeocoder{APIKey: "SUPERSECRETKEY"}
+	_, err := geocoder.Geocode(ctx, "US", "10001")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled geocode returned %v, want %v", err, context.Canceled)
+	}
+	if err != nil && strings.Contains(err.Error(), "SUPERSECRETKEY") {
+		t.Errorf("geocode error contains the API key: %s", err)
+	}
+}
@@ -0,0 +1,256 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// HijriCalendar is the method used to convert between Gregorian and Hijri dates
+type HijriCalendar int
+
+const (
+	// UmmAlQura is the Saudi Umm al-Qura calendar.  Only 1300 to 1600 AH is covered by its table
+	UmmAlQura HijriCalendar = iota
+	// TabularHijri is the arithmetical Islamic calendar using the civil epoch and a 30 year leap cycle
+	TabularHijri
+)
+
+const (
+	ummAlQuraFirstYear = 1300
+	ummAlQuraLastYear  = ummAlQuraFirstYear + len(ummAlQuraMonthLengths) - 1
+	// maxHijriAdjustment is the largest local moon sighting adjustment accepted, in days
+	maxHijriAdjustment = 2
+	// tabularHijriEpoch is 1 Muharram 1 AH as days since the unix epoch.  16th of July 622 in the Julian calendar
+	tabularHijriEpoch = -492148
+)
+
+// ummAlQuraEpoch is 1 Muharram 1300 AH, the first day of the Umm al-Qura table
+var ummAlQuraEpoch = time.Date(1882, time.November, 12, 0, 0, 0, 0, time.UTC)
+
+// ummAlQuraYearStarts is the first day of each Umm al-Qura year as days since the unix epoch.
+// The final entry is the day after the table ends.
+var ummAlQuraYearStarts = func() []int {
+	starts := make([]int, len(ummAlQuraMonthLengths)+1)
+	starts[0] = epochDay(ummAlQuraEpoch)
+	for i, lengths := range ummAlQuraMonthLengths {
+		starts[i+1] = starts[i] + 12*29 + bitCount(lengths)
+	}
+	return starts
+}()
+
+// HijriMonthNames are the transliterated names of the Hijri months, Muharram first
+var HijriMonthNames = [12]string{
+	"Muharram",
+	"Safar",
+	"Rabi al-Awwal",
+	"Rabi al-Thani",
+	"Jumada al-Ula",
+	"Jumada al-Akhirah",
+	"Rajab",
+	"Shaban",
+	"Ramadan",
+	"Shawwal",
+	"Dhu al-Qadah",
+	"Dhu al-Hijjah",
+}
+
+// HijriDate is a date in the Islamic calendar
+type HijriDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"` // 1 for Muharram through 12 for Dhu al-Hijjah
+	Day   int `json:"day"`
+}
+
+// MonthName returns the transliterated name of the Hijri month
+func (h HijriDate) MonthName() string {
+	if h.Month < 1 || h.Month > 12 {
+		return ""
+	}
+	return HijriMonthNames[h.Month-1]
+}
+
+// String returns the Hijri date as "DD MonthName YYYY AH"
+func (h HijriDate) String() string {
+	if h.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d %s %d AH", h.Day, h.MonthName(), h.Year)
+}
+
+// IsZero reports whether the Hijri date is unset
+func (h HijriDate) IsZero() bool {
+	return h.Year == 0 && h.Month == 0 && h.Day == 0
+}
+
+/*
+ToHijri returns the Hijri date of the calendar day of date.
+adjustment moves the Hijri date by up to 2 days to match local moon sighting.  An adjustment of 1 means the
+Hijri month starts one day earlier than the calendar states.
+*/
+func ToHijri(date time.Time, calendar HijriCalendar, adjustment int) (HijriDate, error) {
+	if err := checkHijriAdjustment(adjustment); err != nil {
+		return HijriDate{}, err
+	}
+
+	day := epochDay(date) + adjustment
+	switch calendar {
+	case UmmAlQura:
+		return ummAlQuraFromEpochDay(day)
+	case TabularHijri:
+		return tabularHijriFromEpochDay(day), nil
+	}
+	return HijriDate{}, fmt.Errorf("unknown hijri calendar: %d", calendar)
+}
+
+// ToGregorian returns midnight of the Gregorian day of the Hijri date in location
+func (h HijriDate) ToGregorian(calendar HijriCalendar, adjustment int, location *time.Location) (time.Time, error) {
+	if err := checkHijriAdjustment(adjustment); err != nil {
+		return time.Time{}, err
+	}
+
+	monthLength, err := HijriMonthLength(h.Year, h.Month, calendar)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if h.Day < 1 || h.Day > monthLength {
+		return time.Time{}, fmt.Errorf("day %d is outside of %s %d which has %d days", h.Day, h.MonthName(), h.Year, monthLength)
+	}
+
+	var day int
+	switch calendar {
+	case UmmAlQura:
+		day = ummAlQuraMonthStart(h.Year, h.Month) + h.Day - 1
+	case TabularHijri:
+		day = tabularHijriMonthStart(h.Year, h.Month) + h.Day - 1
+	}
+	day -= adjustment
+
+	if location == nil {
+		location = time.UTC
+	}
+	gregorian := time.Unix(int64(day)*86400, 0).UTC()
+	return time.Date(gregorian.Year(), gregorian.Month(), gregorian.Day(), 0, 0, 0, 0, location), nil
+}
+
+// HijriMonthLength returns the number of days in a Hijri month
+func HijriMonthLength(year int, month int, calendar HijriCalendar) (int, error) {
+	if month < 1 || month > 12 {
+		return 0, fmt.Errorf("hijri month must be between 1 and 12: %d", month)
+	}
+
+	switch calendar {
+	case UmmAlQura:
+		if year < ummAlQuraFirstYear || year > ummAlQuraLastYear {
+			return 0, fmt.Errorf("hijri year %d is outside of the Umm al-Qura table %d-%d", year, ummAlQuraFirstYear, ummAlQuraLastYear)
+		}
+		if ummAlQuraMonthLengths[year-ummAlQuraFirstYear]&(1<<(12-month)) != 0 {
+			return 30, nil
+		}
+		return 29, nil
+	case TabularHijri:
+		if year < 1 {
+			return 0, fmt.Errorf("hijri year must be above 0: %d", year)
+		}
+		if month%2 == 1 || (month == 12 && tabularHijriLeapYear(year)) {
+			return 30, nil
+		}
+		return 29, nil
+	}
+	return 0, fmt.Errorf("unknown hijri calendar: %d", calendar)
+}
+
+// SetHijriDates sets the Hijri date of every day in the month
+func (p *PCalOutput) SetHijriDates(calendar HijriCalendar, adjustment int) error {
+	for i := range p.Data {
+		day, err := p.Data[i].Day()
+		if err != nil {
+			return err
+		}
+		hijri, err := ToHijri(day, calendar, adjustment)
+		if err != nil {
+			return err
+		}
+		p.Data[i].Hijri = hijri
+	}
+	return nil
+}
+
+// hijriDate returns the Hijri date of the day on date, calculated with UmmAlQura when the day has none
+func (d *PCalDay) hijriDate(date time.Time) (HijriDate, error) {
+	if !d.Hijri.IsZero() {
+		return d.Hijri, nil
+	}
+	return ToHijri(date, UmmAlQura, 0)
+}
+
+func checkHijriAdjustment(adjustment int) error {
+	if adjustment < -maxHijriAdjustment || adjustment > maxHijriAdjustment {
+		return fmt.Errorf("hijri adjustment must be between -%d and %d days: %d", maxHijriAdjustment, maxHijriAdjustment, adjustment)
+	}
+	return nil
+}
+
+func ummAlQuraFromEpochDay(day int) (HijriDate, error) {
+	if day < ummAlQuraYearStarts[0] || day >= ummAlQuraYearStarts[len(ummAlQuraYearStarts)-1] {
+		return HijriDate{}, fmt.Errorf("date is outside of the Umm al-Qura table %d-%d AH", ummAlQuraFirstYear, ummAlQuraLastYear)
+	}
+
+	yearIndex := 0
+	for ummAlQuraYearStarts[yearIndex+1] <= day {
+		yearIndex++
+	}
+
+	hijri := HijriDate{Year: ummAlQuraFirstYear + yearIndex, Month: 1}
+	dayOfYear := day - ummAlQuraYearStarts[yearIndex]
+	for {
+		monthLength, _ := HijriMonthLength(hijri.Year, hijri.Month, UmmAlQura)
+		if dayOfYear < monthLength {
+			break
+		}
+		dayOfYear -= monthLength
+		hijri.Month++
+	}
+	hijri.Day = dayOfYear + 1
+	return hijri, nil
+}
+
+func ummAlQuraMonthStart(year int, month int) int {
+	day := ummAlQuraYearStarts[year-ummAlQuraFirstYear]
+	for m := 1; m < month; m++ {
+		monthLength, _ := HijriMonthLength(year, m, UmmAlQura)
+		day += monthLength
+	}
+	return day
+}
+
+func tabularHijriFromEpochDay(day int) HijriDate {
+	year := (30*(day-tabularHijriEpoch) + 10646) / 10631
+	month := 1
+	for month < 12 && day >= tabularHijriMonthStart(year, month+1) {
+		month++
+	}
+	return HijriDate{Year: year, Month: month, Day: day - tabularHijriMonthStart(year, month) + 1}
+}
+
+func tabularHijriMonthStart(year int, month int) int {
+	return tabularHijriEpoch + (year-1)*354 + (3+11*year)/30 + (59*(month-1)+1)/2
+}
+
+// tabularHijriLeapYear reports whether year is one of the 11 leap years of the 30 year cycle
+func tabularHijriLeapYear(year int) bool {
+	return (14+11*year)%30 < 11
+}
+
+// epochDay returns the calendar day of t as days since the unix epoch
+func epochDay(t time.Time) int {
+	calendarDay := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(calendarDay.Unix() / 86400)
+}
+
+func bitCount(value uint16) int {
+	count := 0
+	for ; value != 0; value &= value - 1 {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,109 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func TestToHijriUmmAlQura(t *testing.T) {
+	tests := []struct {
+		gregorian time.Time
+		hijri     psched.HijriDate
+	}{
+		{time.Date(1882, time.November, 12, 0, 0, 0, 0, time.UTC), psched.HijriDate{Year: 1300, Month: 1, Day: 1}},
+		{time.Date(2023, time.March, 23, 0, 0, 0, 0, time.UTC), psched.HijriDate{Year: 1444, Month: 9, Day: 1}},
+		{time.Date(2023, time.April, 21, 0, 0, 0, 0, time.UTC), psched.HijriDate{Year: 1444, Month: 10, Day: 1}},
+		{time.Date(2023, time.July, 19, 0, 0, 0, 0, time.UTC), psched.HijriDate{Year: 1445, Month: 1, Day: 1}},
+		{time.Date(2024, time.June, 16, 0, 0, 0, 0, time.UTC), psched.HijriDate{Year: 1445, Month: 12, Day: 10}},
+	}
+
+	for _, test := range tests {
+		hijri, err := psched.ToHijri(test.gregorian, psched.UmmAlQura, 0)
+		if err != nil {
+			t.Fatalf("unable to convert %s to hijri: %s", test.gregorian.Format("2006-01-02"), err)
+		}
+		if hijri != test.hijri {
+			t.Errorf("%s converted to %s, want %s", test.gregorian.Format("2006-01-02"), hijri, test.hijri)
+		}
+
+		gregorian, err := test.hijri.ToGregorian(psched.UmmAlQura, 0, time.UTC)
+		if err != nil {
+			t.Fatalf("unable to convert %s to gregorian: %s", test.hijri, err)
+		}
+		if !gregorian.Equal(test.gregorian) {
+			t.Errorf("%s converted to %s, want %s", test.hijri, gregorian.Format("2006-01-02"), test.gregorian.Format("2006-01-02"))
+		}
+	}
+
+	if _, err := psched.ToHijri(time.Date(1850, time.January, 1, 0, 0, 0, 0, time.UTC), psched.UmmAlQura, 0); err == nil {
+		t.Error("date before the Umm al-Qura table did not return an error")
+	}
+}
+
+func TestToHijriTabular(t *testing.T) {
+	// 16th of July 622 in the Julian calendar is the 19th in the proleptic Gregorian calendar used by time
+	epoch, err := psched.ToHijri(time.Date(622, time.July, 19, 0, 0, 0, 0, time.UTC), psched.TabularHijri, 0)
+	if err != nil {
+		t.Fatalf("unable to convert the hijri epoch: %s", err)
+	}
+	if epoch != (psched.HijriDate{Year: 1, Month: 1, Day: 1}) {
+		t.Errorf("tabular hijri epoch converted to %s, want 1 Muharram 1 AH", epoch)
+	}
+
+	// Every day of a full 30 year cycle has to convert back to itself
+	day := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10631; i++ {
+		hijri, err := psched.ToHijri(day, psched.TabularHijri, 0)
+		if err != nil {
+			t.Fatalf("unable to convert %s to hijri: %s", day.Format("2006-01-02"), err)
+		}
+		gregorian, err := hijri.ToGregorian(psched.TabularHijri, 0, time.UTC)
+		if err != nil {
+			t.Fatalf("unable to convert %s to gregorian: %s", hijri, err)
+		}
+		if !gregorian.Equal(day) {
+			t.Fatalf("%s converted to %s and back to %s", day.Format("2006-01-02"), hijri, gregorian.Format("2006-01-02"))
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+func TestToHijriAdjustment(t *testing.T) {
+	day := time.Date(2023, time.March, 22, 0, 0, 0, 0, time.UTC)
+	hijri, err := psched.ToHijri(day, psched.UmmAlQura, 1)
+	if err != nil {
+		t.Fatalf("unable to convert with adjustment: %s", err)
+	}
+	if hijri != (psched.HijriDate{Year: 1444, Month: 9, Day: 1}) {
+		t.Errorf("adjusted hijri date is %s, want 1 Ramadan 1444 AH", hijri)
+	}
+
+	gregorian, err := hijri.ToGregorian(psched.UmmAlQura, 1, time.UTC)
+	if err != nil {
+		t.Fatalf("unable to convert with adjustment: %s", err)
+	}
+	if !gregorian.Equal(day) {
+		t.Errorf("adjusted gregorian date is %s, want %s", gregorian.Format("2006-01-02"), day.Format("2006-01-02"))
+	}
+
+	if _, err := psched.ToHijri(day, psched.UmmAlQura, 3); err == nil {
+		t.Error("adjustment of 3 days did not return an error")
+	}
+}
+
+func TestSetHijriDates(t *testing.T) {
+	month := iqamahTestMonth()
+	if err := month.SetHijriDates(psched.UmmAlQura, 0); err != nil {
+		t.Fatalf("unable to set hijri dates: %s", err)
+	}
+
+	// 1st of October 2022 is 5 Rabi al-Awwal 1444
+	for i := range month.Data {
+		want := psched.HijriDate{Year: 1444, Month: 3, Day: 5 + i}
+		if month.Data[i].Hijri != want {
+			t.Errorf("day %d has hijri date %s, want %s", i+1, month.Data[i].Hijri, want)
+		}
+	}
+}
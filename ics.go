@@ -0,0 +1,270 @@
+package schedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	icsProductID       = "-//moali87//prayer-schedule//EN"
+	icsDefaultDuration = 15 * time.Minute
+	icsDefaultUIDHost  = "prayer-schedule"
+	icsLineLength      = 75 // Longest content line in octets before folding, from RFC 5545 section 3.1
+)
+
+// defaultICSPrayers are the timings exported when ICSOptions.Prayers is empty
+var defaultICSPrayers = []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// ICSOptions configures the iCalendar export of prayer timings
+type ICSOptions struct {
+	Name         string                   // Calendar name shown by calendar apps
+	Prayers      []string                 // Timings to export, such as "Fajr" or "Sunrise".  Defaults to the five prayers
+	Duration     time.Duration            // Length of each event.  Defaults to 15 minutes
+	Durations    map[string]time.Duration // Length of the events of a prayer, overriding Duration
+	AlarmMinutes int                      // Minutes before each prayer to remind at.  0 for no reminder
+	UIDHost      string                   // Host part of event UIDs.  Defaults to prayer-schedule
+	Clock        Clock                    // Time of the DTSTAMP of each event.  Defaults to SystemClock
+}
+
+// WriteICS writes the month as an iCalendar file
+func (p *PCalOutput) WriteICS(w io.Writer, options ICSOptions) error {
+	return WriteICS(w, p.Data, options)
+}
+
+// WriteICS writes the range as an iCalendar file
+func (r *PCalRangeOutput) WriteICS(w io.Writer, options ICSOptions) error {
+	return WriteICS(w, r.Days, options)
+}
+
+/*
+WriteICS writes an RFC 5545 iCalendar file with an event for every selected prayer of days.  Event UIDs are made of
+the date, prayer and rounded coordinates, so importing the file again updates the events instead of duplicating them.
+Each timezone of the days is described by a VTIMEZONE built from its Go timezone transitions.  The description of
+each event has the Hijri date of the day, calculated with UmmAlQura when the day has none.
+*/
+func WriteICS(w io.Writer, days []PCalDay, options ICSOptions) error {
+	prayers := options.Prayers
+	if len(prayers) == 0 {
+		prayers = defaultICSPrayers
+	}
+	for _, prayer := range prayers {
+		if !(&FiveDailyPrayers{}).setTiming(prayer, "") {
+			return fmt.Errorf("%s is not a prayer timing", prayer)
+		}
+	}
+	uidHost := options.UIDHost
+	if uidHost == "" {
+		uidHost = icsDefaultUIDHost
+	}
+
+	dates := make([]time.Time, len(days))
+	locations := make(map[string]*time.Location)
+	for i := range days {
+		date, err := days[i].Day()
+		if err != nil {
+			return err
+		}
+		dates[i] = date
+		locations[date.Location().String()] = date.Location()
+	}
+
+	writer := &icsWriter{w: bufio.NewWriter(w)}
+	writer.line("BEGIN:VCALENDAR")
+	writer.line("VERSION:2.0")
+	writer.line("PRODID:" + icsProductID)
+	writer.line("CALSCALE:GREGORIAN")
+	writer.line("METHOD:PUBLISH")
+	if options.Name != "" {
+		writer.line("X-WR-CALNAME:" + icsEscape(options.Name))
+	}
+
+	names := make([]string, 0, len(locations))
+	for name := range locations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if locations[name] != time.UTC && len(dates) > 0 {
+			writer.timezone(locations[name], dates[0], dates[len(dates)-1])
+		}
+	}
+
+	stamp := clockOrSystem(options.Clock).Now().UTC().Format("20060102T150405Z")
+	for i := range days {
+		hijri, err := days[i].hijriDate(dates[i])
+		if err != nil {
+			return err
+		}
+
+		for _, prayer := range prayers {
+			timing := days[i].Timings.timing(prayer)
+			if timing == "" {
+				continue
+			}
+			start, err := PrayerTimeOnDay(dates[i], timing)
+			if err != nil {
+				return fmt.Errorf("unable to read %s on %s: %s", prayer, days[i].Date.Gregorian.Date, err)
+			}
+			duration := options.Duration
+			if prayerDuration, ok := options.Durations[prayer]; ok {
+				duration = prayerDuration
+			}
+			if duration <= 0 {
+				duration = icsDefaultDuration
+			}
+
+			description := fmt.Sprintf("%s at %s\nHijri date: %s", prayer, start.Format("15:04"), hijri)
+			for _, event := range days[i].Events {
+				description += "\n" + event.Name
+			}
+
+			writer.line("BEGIN:VEVENT")
+			writer.line(fmt.Sprintf("UID:%s-%s-%.3f_%.3f@%s",
+				dates[i].Format("20060102"), strings.ToLower(prayer), days[i].Meta.Latitude, days[i].Meta.Longitude, uidHost))
+			writer.line("DTSTAMP:" + stamp)
+			writer.line(icsDateTime("DTSTART", start))
+			writer.line(icsDateTime("DTEND", start.Add(duration)))
+			writer.line("SUMMARY:" + icsEscape(prayer))
+			writer.line("DESCRIPTION:" + icsEscape(description))
+			writer.line("TRANSP:TRANSPARENT")
+			if options.AlarmMinutes > 0 {
+				writer.line("BEGIN:VALARM")
+				writer.line("ACTION:DISPLAY")
+				writer.line(fmt.Sprintf("TRIGGER:-PT%dM", options.AlarmMinutes))
+				writer.line("DESCRIPTION:" + icsEscape(fmt.Sprintf("%s in %d minutes", prayer, options.AlarmMinutes)))
+				writer.line("END:VALARM")
+			}
+			writer.line("END:VEVENT")
+		}
+	}
+
+	writer.line("END:VCALENDAR")
+	if writer.err != nil {
+		return writer.err
+	}
+	return writer.w.Flush()
+}
+
+// icsWriter writes folded iCalendar content lines, keeping the first error
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line, folding it into lines of at most 75 octets without splitting a UTF-8 character
+func (i *icsWriter) line(content string) {
+	if i.err != nil {
+		return
+	}
+	limit := icsLineLength
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, i.err = i.w.WriteString(content[:cut] + "\r\n "); i.err != nil {
+			return
+		}
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = icsLineLength - 1
+	}
+	_, i.err = i.w.WriteString(content + "\r\n")
+}
+
+/*
+timezone writes a VTIMEZONE for location with every transition from a year before first until after last, so
+the offset in effect at first is included.  A location without transitions gets a single observance.
+*/
+func (i *icsWriter) timezone(location *time.Location, first time.Time, last time.Time) {
+	i.line("BEGIN:VTIMEZONE")
+	i.line("TZID:" + location.String())
+
+	transitions := zoneTransitions(location, first.AddDate(-1, 0, 0), last.AddDate(0, 0, 2))
+	// Only the last transition before first is needed for the offset in effect at first
+	for len(transitions) > 1 && !transitions[1].After(first) {
+		transitions = transitions[1:]
+	}
+	if len(transitions) == 0 || transitions[0].After(first) {
+		name, offset := first.In(location).Zone()
+		kind := "STANDARD"
+		if first.In(location).IsDST() {
+			kind = "DAYLIGHT"
+		}
+		i.observance(kind, time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC), name, offset, offset)
+	}
+	for _, transition := range transitions {
+		name, offset := transition.In(location).Zone()
+		_, previousOffset := transition.Add(-time.Second).In(location).Zone()
+		kind := "STANDARD"
+		if transition.In(location).IsDST() {
+			kind = "DAYLIGHT"
+		}
+		// The onset is given in the local time before the transition
+		onset := transition.In(time.FixedZone("", previousOffset))
+		i.observance(kind, onset, name, previousOffset, offset)
+	}
+	i.line("END:VTIMEZONE")
+}
+
+func (i *icsWriter) observance(kind string, onset time.Time, name string, offsetFrom int, offsetTo int) {
+	i.line("BEGIN:" + kind)
+	i.line("DTSTART:" + onset.Format("20060102T150405"))
+	i.line("TZOFFSETFROM:" + icsOffset(offsetFrom))
+	i.line("TZOFFSETTO:" + icsOffset(offsetTo))
+	i.line("TZNAME:" + icsEscape(name))
+	i.line("END:" + kind)
+}
+
+// zoneTransitions returns the instants between from and to when the UTC offset of location changes
+func zoneTransitions(location *time.Location, from time.Time, to time.Time) []time.Time {
+	var transitions []time.Time
+	_, previousOffset := from.In(location).Zone()
+	previous := from
+	for current := from.Add(24 * time.Hour); !previous.After(to); current = current.Add(24 * time.Hour) {
+		if _, offset := current.In(location).Zone(); offset != previousOffset {
+			low, high := previous, current
+			for high.Sub(low) > time.Second {
+				middle := low.Add(high.Sub(low) / 2)
+				if _, middleOffset := middle.In(location).Zone(); middleOffset == previousOffset {
+					low = middle
+				} else {
+					high = middle
+				}
+			}
+			transitions = append(transitions, high.Truncate(time.Second))
+			previousOffset = offset
+		}
+		previous = current
+	}
+	return transitions
+}
+
+// icsDateTime returns a date time property of t, in UTC for UTC times and with the timezone of t otherwise
+func icsDateTime(property string, t time.Time) string {
+	if t.Location() == time.UTC {
+		return property + ":" + t.Format("20060102T150405Z")
+	}
+	return fmt.Sprintf("%s;TZID=%s:%s", property, t.Location(), t.Format("20060102T150405"))
+}
+
+// icsOffset formats a UTC offset in seconds as +HHMM
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	if seconds%60 != 0 {
+		return fmt.Sprintf("%s%02d%02d%02d", sign, seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// icsEscape escapes text property values
+func icsEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,104 @@
+package schedule_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func icsTestRange(t *testing.T) *psched.PCalRangeOutput {
+	t.Helper()
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	customer := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    &testMonthProvider{location: location},
+	}
+	output, err := customer.PrayerCalendarRange(time.Date(2022, time.November, 5, 0, 0, 0, 0, location), time.Date(2022, time.November, 6, 0, 0, 0, 0, location), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+// unfoldICS returns the content lines of an iCalendar file
+func unfoldICS(t *testing.T, ics string) []string {
+	t.Helper()
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line is longer than 75 octets: %q", line)
+		}
+	}
+	return strings.Split(strings.ReplaceAll(strings.TrimSuffix(ics, "\r\n"), "\r\n ", ""), "\r\n")
+}
+
+func TestWriteICS(t *testing.T) {
+	days := icsTestRange(t)
+	var output bytes.Buffer
+	err := days.WriteICS(&output, psched.ICSOptions{
+		Name:         "Prayer times, Los Angeles",
+		Prayers:      []string{"Fajr", "Maghrib"},
+		Duration:     20 * time.Minute,
+		Durations:    map[string]time.Duration{"Maghrib": 10 * time.Minute},
+		AlarmMinutes: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := unfoldICS(t, output.String())
+	ics := strings.Join(lines, "\n")
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\nVERSION:2.0\nPRODID:",
+		"X-WR-CALNAME:Prayer times\\, Los Angeles",
+		// Daylight saving time ended at 02:00 PDT on 6 November 2022
+		"BEGIN:STANDARD\nDTSTART:20221106T020000\nTZOFFSETFROM:-0700\nTZOFFSETTO:-0800\nTZNAME:PST\nEND:STANDARD",
+		"BEGIN:DAYLIGHT\nDTSTART:20220313T020000\nTZOFFSETFROM:-0800\nTZOFFSETTO:-0700\nTZNAME:PDT\nEND:DAYLIGHT",
+		"UID:20221105-fajr-34.103_-118.410@prayer-schedule",
+		"DTSTART;TZID=America/Los_Angeles:20221105T051400\nDTEND;TZID=America/Los_Angeles:20221105T053400",
+		"DTSTART;TZID=America/Los_Angeles:20221106T180600\nDTEND;TZID=America/Los_Angeles:20221106T181600",
+		"BEGIN:VALARM\nACTION:DISPLAY\nTRIGGER:-PT10M",
+		"DESCRIPTION:Fajr at 05:14\\nHijri date: 11 Rabi al-Thani 1444 AH",
+		"END:VCALENDAR",
+	} {
+		if !strings.Contains(ics, expected) {
+			t.Errorf("ics does not contain %q:\n%s", expected, ics)
+		}
+	}
+	if count := strings.Count(ics, "BEGIN:VEVENT"); count != 4 {
+		t.Errorf("expected 4 events, got %d", count)
+	}
+	if strings.Count(ics, "BEGIN:DAYLIGHT") != 1 {
+		t.Errorf("expected only the daylight observance in effect at the first day")
+	}
+
+	// Exporting again gives the same UIDs so calendar apps update the events
+	var again bytes.Buffer
+	if err := days.WriteICS(&again, psched.ICSOptions{Prayers: []string{"Fajr", "Maghrib"}}); err != nil {
+		t.Fatal(err)
+	}
+	uids := func(ics string) []string {
+		var uids []string
+		for _, line := range strings.Split(ics, "\r\n") {
+			if strings.HasPrefix(line, "UID:") {
+				uids = append(uids, line)
+			}
+		}
+		return uids
+	}
+	if strings.Join(uids(output.String()), ",") != strings.Join(uids(again.String()), ",") {
+		t.Errorf("uids are not stable: %v %v", uids(output.String()), uids(again.String()))
+	}
+}
+
+func TestWriteICSInvalidPrayer(t *testing.T) {
+	var output bytes.Buffer
+	if err := psched.WriteICS(&output, nil, psched.ICSOptions{Prayers: []string{"Tahajjud"}}); err == nil {
+		t.Error("unknown prayer did not return an error")
+	}
+}
@@ -0,0 +1,322 @@
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Angle search range and precision of InferCalculationMethod
+const (
+	inferMinAngle     = 10.0
+	inferMaxAngle     = 21.0
+	inferCoarseStep   = 0.5
+	inferFineStep     = 0.1
+	inferMinIshaDelay = 45  // Shortest Isha minutes after Maghrib tried
+	inferMaxIshaDelay = 150 // Longest Isha minutes after Maghrib tried
+)
+
+// inferMaghribAngles are the Maghrib angles of the aladhan methods, 0 being sunset
+var inferMaghribAngles = []float64{0, 4, 4.5}
+
+// InferredMethod is the calculation method which best reproduces a published timetable
+type InferredMethod struct {
+	Institution              int // Aladhan method number with the same angles, or -1 when no aladhan method matches
+	Method                   CalculationMethod
+	School                   int
+	LatitudeAdjustmentMethod int
+	Offsets                  PrayerOffsets
+	Residuals                []PrayerDeviation // Differences between the timetable and the inferred method with offsets
+}
+
+/*
+InferCalculationMethod searches for the Fajr and Isha angles or Isha delay, Maghrib angle, Asr school, high latitude
+rule and per-prayer offsets with which the LocalProvider best reproduces timetable at the coordinates.  The shape of
+each prayer through the year picks the angles and school, and the median remaining difference becomes the offset, so
+timetables which add a few minutes of precaution are still recognised.  The longer the timetable, the better the
+angles are told apart, a year being best.
+*/
+func InferCalculationMethod(timetable *ImportedTimetable, latitude float32, longitude float32) (*InferredMethod, error) {
+	if len(timetable.Days) == 0 {
+		return nil, fmt.Errorf("timetable has no days")
+	}
+	inference := &methodInference{latitude: float64(latitude), longitude: float64(longitude)}
+	for i := range timetable.Days {
+		date, err := timetable.Days[i].Day()
+		if err != nil {
+			return nil, err
+		}
+		inference.dates = append(inference.dates, date)
+	}
+	inference.actual = make(map[string][]time.Time)
+	for _, prayer := range prayerTimingNames {
+		for i := range timetable.Days {
+			timing := timetable.Days[i].Timings.timing(prayer)
+			if timing == "" {
+				delete(inference.actual, prayer)
+				break
+			}
+			actual, err := PrayerTimeOnDay(inference.dates[i], timing)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s on %s: %s", prayer, inference.dates[i].Format("2006-01-02"), err)
+			}
+			inference.actual[prayer] = append(inference.actual[prayer], actual)
+		}
+	}
+
+	baseMethod := CalculationMethod{FajrAngle: 18, IshaAngle: 18}
+	base, err := inference.calculate(baseMethod, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := &InferredMethod{Institution: -1}
+	result.Offsets.Sunrise, _ = inference.fit("Sunrise", base, func(t *localTimes) time.Time { return t.sunrise })
+	result.Offsets.Dhuhr, _ = inference.fit("Dhuhr", base, func(t *localTimes) time.Time { return t.dhuhr })
+
+	// Asr school
+	bestAsr := math.Inf(1)
+	for _, school := range []int{0, 1} {
+		times, err := inference.calculate(baseMethod, school, 0)
+		if err != nil {
+			return nil, err
+		}
+		if offset, residual := inference.fit("Asr", times, func(t *localTimes) time.Time { return t.asr }); residual < bestAsr {
+			bestAsr, result.School, result.Offsets.Asr = residual, school, offset
+		}
+	}
+
+	// Maghrib angle
+	bestMaghrib := math.Inf(1)
+	var maghribTimes []*localTimes
+	for _, angle := range inferMaghribAngles {
+		method := baseMethod
+		method.MaghribAngle = angle
+		times, err := inference.calculate(method, 0, 0)
+		if err != nil {
+			continue
+		}
+		if offset, residual := inference.fit("Maghrib", times, func(t *localTimes) time.Time { return t.maghrib }); residual < bestMaghrib-0.01 {
+			bestMaghrib, result.Method.MaghribAngle, result.Offsets.Maghrib, maghribTimes = residual, angle, offset, times
+		}
+	}
+	if maghribTimes == nil {
+		return nil, fmt.Errorf("unable to calculate maghrib at the timetable coordinates")
+	}
+
+	// Fajr and Isha angles share the high latitude rule.  The default rule is kept unless another fits better
+	bestTotal := math.Inf(1)
+	for _, rule := range []int{0, LatitudeAdjustmentMiddleOfTheNight, LatitudeAdjustmentOneSeventh} {
+		fajrAngle, fajrOffset, fajrResidual := inference.searchAngle("Fajr", rule, result.Method.MaghribAngle)
+		ishaAngle, ishaOffset, ishaResidual := inference.searchAngle("Isha", rule, result.Method.MaghribAngle)
+		if total := fajrResidual + ishaResidual; total < bestTotal-0.01 {
+			bestTotal = total
+			result.LatitudeAdjustmentMethod = rule
+			result.Method.FajrAngle, result.Offsets.Fajr = fajrAngle, fajrOffset
+			result.Method.IshaAngle, result.Offsets.Isha = ishaAngle, ishaOffset
+		}
+	}
+	if math.IsInf(bestTotal, 1) {
+		return nil, fmt.Errorf("unable to calculate fajr and isha at the timetable coordinates")
+	}
+
+	// Isha a fixed delay after Maghrib, as with Umm al-Qura
+	if ishaTimes, ok := inference.actual["Isha"]; ok {
+		bestIsha := inference.ishaResidual(result.Method, result.LatitudeAdjustmentMethod, result.Offsets.Isha)
+		var delays []float64
+		for i := range ishaTimes {
+			delays = append(delays, ishaTimes[i].Sub(maghribTimes[i].maghrib).Minutes())
+		}
+		delay := math.Round(median(delays))
+		if residual := meanAbsoluteDeviation(delays, delay); residual < bestIsha-0.01 && delay >= inferMinIshaDelay && delay <= inferMaxIshaDelay {
+			result.Method.IshaAngle = 0
+			result.Method.IshaMinutes = int(delay)
+			result.Offsets.Isha = 0
+		}
+	}
+
+	if _, ok := inference.actual["Imsak"]; ok {
+		times, err := inference.calculate(result.Method, result.School, result.LatitudeAdjustmentMethod)
+		if err != nil {
+			return nil, err
+		}
+		result.Offsets.Imsak, _ = inference.fit("Imsak", times, func(t *localTimes) time.Time { return t.imsak })
+	}
+
+	result.Method.Name = "Inferred"
+	institutions := make([]int, 0, len(CalculationMethods))
+	for institution := range CalculationMethods {
+		institutions = append(institutions, institution)
+	}
+	sort.Ints(institutions)
+	for _, institution := range institutions {
+		method := CalculationMethods[institution]
+		if math.Abs(method.FajrAngle-result.Method.FajrAngle) < 0.05 && math.Abs(method.IshaAngle-result.Method.IshaAngle) < 0.05 &&
+			method.IshaMinutes == result.Method.IshaMinutes && method.MaghribAngle == result.Method.MaghribAngle {
+			result.Institution = institution
+			result.Method.Name = method.Name
+			result.Method.IshaRamadanMinutes = method.IshaRamadanMinutes
+			break
+		}
+	}
+
+	if err := inference.setResiduals(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Apply sets the customer to calculate timings locally with the inferred method, such as for the years after the timetable
+func (m *InferredMethod) Apply(c *CustomerLocationInput, location *time.Location) {
+	method := m.Method
+	c.Provider = &LocalProvider{Location: location, Method: &method}
+	if m.Institution >= 0 {
+		c.Institution = m.Institution
+	}
+	c.School = m.School
+	c.LatitudeAdjustmentMethod = m.LatitudeAdjustmentMethod
+	c.Offsets = m.Offsets
+}
+
+// methodInference holds the timetable times being reproduced by InferCalculationMethod
+type methodInference struct {
+	latitude, longitude float64
+	dates               []time.Time
+	actual              map[string][]time.Time // Timetable times of each prayer, when the timetable has the prayer
+}
+
+// calculate returns the local times of every timetable day
+func (m *methodInference) calculate(method CalculationMethod, school int, rule int) ([]*localTimes, error) {
+	input := &PCalInput{School: school, LatitudeAdjustmentMethod: rule}
+	times := make([]*localTimes, len(m.dates))
+	for i, date := range m.dates {
+		dayTimes, err := calculateLocalTimes(date, m.latitude, m.longitude, method, input)
+		if err != nil {
+			return nil, err
+		}
+		times[i] = dayTimes
+	}
+	return times, nil
+}
+
+// fit returns the whole minute offset of prayer which best matches the timetable to the calculated times, and the
+// mean remaining difference in minutes
+func (m *methodInference) fit(prayer string, times []*localTimes, calculated func(*localTimes) time.Time) (int, float64) {
+	actual, ok := m.actual[prayer]
+	if !ok {
+		return 0, 0
+	}
+	differences := make([]float64, len(actual))
+	for i := range actual {
+		differences[i] = actual[i].Sub(calculated(times[i])).Minutes()
+	}
+	offset := math.Round(median(differences))
+	return int(offset), meanAbsoluteDeviation(differences, offset)
+}
+
+// searchAngle returns the Fajr or Isha angle, offset and mean remaining difference which best match the timetable
+func (m *methodInference) searchAngle(prayer string, rule int, maghribAngle float64) (float64, int, float64) {
+	if _, ok := m.actual[prayer]; !ok {
+		return 18, 0, 0
+	}
+	bestAngle, bestOffset, bestResidual := 0.0, 0, math.Inf(1)
+	try := func(angle float64) {
+		method := CalculationMethod{FajrAngle: angle, IshaAngle: angle, MaghribAngle: maghribAngle}
+		times, err := m.calculate(method, 0, rule)
+		if err != nil {
+			return
+		}
+		var offset int
+		var residual float64
+		if prayer == "Fajr" {
+			offset, residual = m.fit(prayer, times, func(t *localTimes) time.Time { return t.fajr })
+		} else {
+			offset, residual = m.fit(prayer, times, func(t *localTimes) time.Time { return t.isha })
+		}
+		// Prefer the smaller offset between angles which fit as well
+		if residual < bestResidual-0.001 || (residual < bestResidual+0.001 && abs(offset) < abs(bestOffset)) {
+			bestAngle, bestOffset, bestResidual = angle, offset, residual
+		}
+	}
+
+	for angle := inferMinAngle; angle <= inferMaxAngle+inferFineStep/2; angle += inferCoarseStep {
+		try(angle)
+	}
+	coarse := bestAngle
+	for angle := coarse - inferCoarseStep + inferFineStep; angle < coarse+inferCoarseStep-inferFineStep/2; angle += inferFineStep {
+		try(math.Round(angle*10) / 10)
+	}
+	return bestAngle, bestOffset, bestResidual
+}
+
+// ishaResidual returns the mean difference of Isha between the timetable and method with offset
+func (m *methodInference) ishaResidual(method CalculationMethod, rule int, offset int) float64 {
+	times, err := m.calculate(method, 0, rule)
+	if err != nil {
+		return math.Inf(1)
+	}
+	var differences []float64
+	for i, actual := range m.actual["Isha"] {
+		differences = append(differences, actual.Sub(times[i].isha).Minutes())
+	}
+	return meanAbsoluteDeviation(differences, float64(offset))
+}
+
+// setResiduals compares the timetable with the inferred method and offsets
+func (m *methodInference) setResiduals(result *InferredMethod) error {
+	times, err := m.calculate(result.Method, result.School, result.LatitudeAdjustmentMethod)
+	if err != nil {
+		return err
+	}
+	offsets := map[string]int{
+		"Imsak": result.Offsets.Imsak, "Fajr": result.Offsets.Fajr, "Sunrise": result.Offsets.Sunrise, "Dhuhr": result.Offsets.Dhuhr,
+		"Asr": result.Offsets.Asr, "Maghrib": result.Offsets.Maghrib, "Isha": result.Offsets.Isha,
+	}
+	for _, prayer := range prayerTimingNames {
+		actual, ok := m.actual[prayer]
+		if !ok {
+			continue
+		}
+		deviation := PrayerDeviation{Prayer: prayer, Days: len(actual)}
+		for i := range actual {
+			calculated := map[string]time.Time{
+				"Imsak": times[i].imsak, "Fajr": times[i].fajr, "Sunrise": times[i].sunrise, "Dhuhr": times[i].dhuhr,
+				"Asr": times[i].asr, "Maghrib": times[i].maghrib, "Isha": times[i].isha,
+			}[prayer].Add(time.Duration(offsets[prayer]) * time.Minute)
+			difference := actual[i].Sub(calculated).Minutes()
+			deviation.MaxMinutes = math.Max(deviation.MaxMinutes, math.Abs(difference))
+			deviation.MeanMinutes += math.Abs(difference)
+			deviation.BiasMinutes += difference
+		}
+		deviation.MeanMinutes /= float64(deviation.Days)
+		deviation.BiasMinutes /= float64(deviation.Days)
+		result.Residuals = append(result.Residuals, deviation)
+	}
+	return nil
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// meanAbsoluteDeviation returns the mean absolute difference of values from center
+func meanAbsoluteDeviation(values []float64, center float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += math.Abs(value - center)
+	}
+	return total / float64(len(values))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
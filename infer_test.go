@@ -0,0 +1,89 @@
+package schedule_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// generatedTimetable returns a year of timetable days calculated by the local provider
+func generatedTimetable(t *testing.T, provider *psched.LocalProvider, input psched.PCalInput) *psched.ImportedTimetable {
+	t.Helper()
+	timetable := &psched.ImportedTimetable{Location: provider.Location}
+	for month := time.January; month <= time.December; month++ {
+		input.CustTime = time.Date(2022, month, 1, 0, 0, 0, 0, provider.Location)
+		output, err := provider.MonthlyPrayers(context.Background(), &input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		timetable.Days = append(timetable.Days, output.Data...)
+	}
+	return timetable
+}
+
+func TestInferCalculationMethod(t *testing.T) {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := psched.PrayerOffsets{Imsak: -5, Dhuhr: 5, Maghrib: 3, Isha: 2}
+	timetable := generatedTimetable(t, &psched.LocalProvider{Location: location}, psched.PCalInput{
+		Institution: 3,
+		Latitude:    34.103,
+		Longitude:   -118.4105,
+		School:      1,
+		Offsets:     offsets,
+	})
+
+	inferred, err := psched.InferCalculationMethod(timetable, 34.103, -118.4105)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inferred.Institution != 3 || inferred.Method.FajrAngle != 18 || inferred.Method.IshaAngle != 17 {
+		t.Errorf("expected muslim world league 18 and 17 degrees, got method %d with %v and %v", inferred.Institution, inferred.Method.FajrAngle, inferred.Method.IshaAngle)
+	}
+	if inferred.School != 1 {
+		t.Errorf("expected the hanafi school, got %d", inferred.School)
+	}
+	if inferred.Offsets != offsets {
+		t.Errorf("expected offsets %+v, got %+v", offsets, inferred.Offsets)
+	}
+	for _, residual := range inferred.Residuals {
+		if residual.MaxMinutes > 1 {
+			t.Errorf("%s residual is too large: %+v", residual.Prayer, residual)
+		}
+	}
+
+	// The inferred method generates the following year
+	customer := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		CustTime:    time.Date(2023, time.March, 1, 0, 0, 0, 0, location),
+	}
+	inferred.Apply(customer, location)
+	month, err := customer.PrayerCalendar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if month.Source != psched.SourceLocal || len(month.Data) != 31 {
+		t.Errorf("unexpected generated month: %q with %d days", month.Source, len(month.Data))
+	}
+}
+
+func TestInferCalculationMethodIshaDelay(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Qatar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	timetable := generatedTimetable(t, &psched.LocalProvider{Location: location}, psched.PCalInput{Institution: 10, Latitude: 25.2854, Longitude: 51.531})
+
+	inferred, err := psched.InferCalculationMethod(timetable, 25.2854, 51.531)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inferred.Method.IshaMinutes != 90 || math.Abs(inferred.Method.FajrAngle-18) > 0.05 || inferred.Institution != 10 {
+		t.Errorf("expected qatar with isha 90 minutes after maghrib, got %+v as method %d", inferred.Method, inferred.Institution)
+	}
+}
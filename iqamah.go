@@ -0,0 +1,233 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// iqamahPrayerNames are the prayers with an iqamah, in the order of the day
+var iqamahPrayerNames = []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// IqamahRuleType is the kind of rule used to derive a congregation time from the adhan time
+type IqamahRuleType int
+
+const (
+	// IqamahFixed is iqamah at the clock time Time every day
+	IqamahFixed IqamahRuleType = iota
+	// IqamahAfterAdhan is iqamah Minutes after the adhan
+	IqamahAfterAdhan
+	// IqamahRoundUp is iqamah Minutes after the adhan, rounded up to the next RoundTo minutes
+	IqamahRoundUp
+	// IqamahNotEarlierThan is iqamah Minutes after the adhan, but never earlier than the clock time Time
+	IqamahNotEarlierThan
+)
+
+// IqamahBoundary is how often an adhan based iqamah time is allowed to change
+type IqamahBoundary int
+
+const (
+	// IqamahDaily lets the iqamah time follow the adhan every day
+	IqamahDaily IqamahBoundary = iota
+	// IqamahWeekly only changes the iqamah time at the start of the week
+	IqamahWeekly
+	// IqamahMonthly only changes the iqamah time at the start of the month
+	IqamahMonthly
+)
+
+// IqamahRule describes how the iqamah of a single prayer is set
+type IqamahRule struct {
+	Type     IqamahRuleType
+	Time     string // HH:MM clock time used by IqamahFixed and IqamahNotEarlierThan
+	Minutes  int    // Minutes after adhan used by every rule type but IqamahFixed
+	RoundTo  int    // Minutes to round up to with IqamahRoundUp, such as 5 or 15
+	Boundary IqamahBoundary
+}
+
+// IqamahRules contains the iqamah rule of each prayer.  A nil rule leaves that prayer without an iqamah time
+type IqamahRules struct {
+	Fajr      *IqamahRule
+	Dhuhr     *IqamahRule
+	Asr       *IqamahRule
+	Maghrib   *IqamahRule
+	Isha      *IqamahRule
+	WeekStart time.Weekday // First day of the week used by IqamahWeekly
+}
+
+// IqamahDay contains the adhan and iqamah times of a single day.  Iqamah.Sunrise is always empty
+type IqamahDay struct {
+	Date   time.Time
+	Adhan  FiveDailyPrayers
+	Iqamah FiveDailyPrayers
+}
+
+// IqamahTimetable is the iqamah timetable parallel to a PCalOutput month
+type IqamahTimetable struct {
+	Days []IqamahDay
+}
+
+/*
+Timetable returns the iqamah times for every day of month.  Weekly periods are cut at the first and last day of
+month, so a weekly rule must use TimetableWithAdjacent, or the IqamahAdjacent days of an export or printable
+timetable, whenever a week may run across the start or end of the month.
+*/
+func (r *IqamahRules) Timetable(month *PCalOutput) (*IqamahTimetable, error) {
+	return r.TimetableWithAdjacent(month)
+}
+
+/*
+TimetableWithAdjacent returns the iqamah times for every day of month, also taking the adhan times of the days of
+adjacent into account, such as of the previous and next month.  A weekly iqamah time then only changes at the start
+of a week, even when the week runs across the start or end of the month.
+*/
+func (r *IqamahRules) TimetableWithAdjacent(month *PCalOutput, adjacent ...*PCalOutput) (*IqamahTimetable, error) {
+	if month == nil {
+		return nil, fmt.Errorf("no monthly prayer data was given")
+	}
+
+	// The days of month come first, followed by the adjacent days which only widen the periods
+	data := append([]PCalDay(nil), month.Data...)
+	for _, other := range adjacent {
+		if other != nil {
+			data = append(data, other.Data...)
+		}
+	}
+	days := make([]time.Time, len(data))
+	for i := range data {
+		day, err := data[i].Day()
+		if err != nil {
+			return nil, err
+		}
+		days[i] = day
+	}
+
+	output := &IqamahTimetable{Days: make([]IqamahDay, len(month.Data))}
+	for i := range month.Data {
+		output.Days[i].Date = days[i]
+		output.Days[i].Adhan = month.Data[i].Timings
+	}
+
+	for _, prayer := range iqamahPrayerNames {
+		rule := r.rule(prayer)
+		if rule == nil {
+			continue
+		}
+
+		adhanTimes := make([]time.Time, len(days))
+		for i := range days {
+			adhan, err := PrayerTimeOnDay(days[i], data[i].Timings.timing(prayer))
+			if err != nil {
+				return nil, err
+			}
+			adhanTimes[i] = adhan
+		}
+
+		for i := range month.Data {
+			base := latestAdhanInPeriod(days, adhanTimes, i, rule.Boundary, r.WeekStart)
+			iqamah, err := rule.iqamahTime(days[i], base)
+			if err != nil {
+				return nil, err
+			}
+			output.Days[i].Iqamah.setTiming(prayer, formatPrayerTimeLike(iqamah, month.Data[i].Timings.timing(prayer)))
+		}
+	}
+
+	return output, nil
+}
+
+// rule returns the iqamah rule of prayer, or nil when it has none
+func (r *IqamahRules) rule(prayer string) *IqamahRule {
+	switch prayer {
+	case "Fajr":
+		return r.Fajr
+	case "Dhuhr":
+		return r.Dhuhr
+	case "Asr":
+		return r.Asr
+	case "Maghrib":
+		return r.Maghrib
+	case "Isha":
+		return r.Isha
+	}
+	return nil
+}
+
+// weekly reports whether any prayer has a weekly rule
+func (r *IqamahRules) weekly() bool {
+	for _, prayer := range iqamahPrayerNames {
+		if rule := r.rule(prayer); rule != nil && rule.Boundary == IqamahWeekly {
+			return true
+		}
+	}
+	return false
+}
+
+// iqamahTime applies the rule to the adhan time of day
+func (rule *IqamahRule) iqamahTime(day time.Time, adhan time.Time) (time.Time, error) {
+	switch rule.Type {
+	case IqamahFixed:
+		return PrayerTimeOnDay(day, rule.Time)
+	case IqamahAfterAdhan:
+		return adhan.Add(time.Duration(rule.Minutes) * time.Minute), nil
+	case IqamahRoundUp:
+		if rule.RoundTo <= 0 {
+			return time.Time{}, fmt.Errorf("iqamah round up rule requires RoundTo to be above 0: %d", rule.RoundTo)
+		}
+		iqamah := adhan.Add(time.Duration(rule.Minutes) * time.Minute)
+		minuteOfDay := iqamah.Hour()*60 + iqamah.Minute()
+		if remainder := minuteOfDay % rule.RoundTo; remainder != 0 {
+			iqamah = iqamah.Add(time.Duration(rule.RoundTo-remainder) * time.Minute)
+		}
+		return iqamah, nil
+	case IqamahNotEarlierThan:
+		earliest, err := PrayerTimeOnDay(day, rule.Time)
+		if err != nil {
+			return time.Time{}, err
+		}
+		iqamah := adhan.Add(time.Duration(rule.Minutes) * time.Minute)
+		if iqamah.Before(earliest) {
+			return earliest, nil
+		}
+		return iqamah, nil
+	}
+	return time.Time{}, fmt.Errorf("unknown iqamah rule type: %d", rule.Type)
+}
+
+// latestAdhanInPeriod returns the latest adhan time of day within the boundary period of day index, moved onto that day.
+// Using the latest adhan keeps a fixed weekly or monthly iqamah from ever falling before the adhan.
+func latestAdhanInPeriod(days []time.Time, adhanTimes []time.Time, index int, boundary IqamahBoundary, weekStart time.Weekday) time.Time {
+	if boundary == IqamahDaily {
+		return adhanTimes[index]
+	}
+
+	periodStart := func(day time.Time) time.Time {
+		if boundary == IqamahMonthly {
+			return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		}
+		offset := (int(day.Weekday()) - int(weekStart) + 7) % 7
+		return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, day.Location())
+	}
+
+	period := periodStart(days[index])
+	latest := adhanTimes[index]
+	latestMinute := latest.Hour()*60 + latest.Minute()
+	for i := range days {
+		if !periodStart(days[i]).Equal(period) {
+			continue
+		}
+		if minute := adhanTimes[i].Hour()*60 + adhanTimes[i].Minute(); minute > latestMinute {
+			latestMinute = minute
+		}
+	}
+
+	day := days[index]
+	return time.Date(day.Year(), day.Month(), day.Day(), latestMinute/60, latestMinute%60, 0, 0, day.Location())
+}
+
+// formatPrayerTimeLike formats t as HH:MM, adding the timezone abbreviation when reference carries one
+func formatPrayerTimeLike(t time.Time, reference string) string {
+	if strings.Contains(reference, "(") {
+		return t.Format("15:04 (MST)")
+	}
+	return t.Format("15:04")
+}
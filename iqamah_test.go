@@ -0,0 +1,148 @@
+package schedule_test
+
+import (
+	"fmt"
+	"testing"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// iqamahTestMonth returns a week of October 2022 in Los Angeles with a Fajr adhan one minute later every day
+func iqamahTestMonth() *psched.PCalOutput {
+	month := &psched.PCalOutput{Code: 200, Status: "OK"}
+	for day := 1; day <= 7; day++ {
+		pcalDay := psched.PCalDay{
+			Timings: psched.FiveDailyPrayers{
+				Fajr:    fmt.Sprintf("05:%02d (PDT)", 38+day),
+				Sunrise: "06:58 (PDT)",
+				Dhuhr:   "12:43 (PDT)",
+				Asr:     "15:59 (PDT)",
+				Maghrib: "18:27 (PDT)",
+				Isha:    "19:43 (PDT)",
+			},
+		}
+		pcalDay.Date.Gregorian.Date = fmt.Sprintf("%02d-10-2022", day)
+		pcalDay.Meta.Timezone = "America/Los_Angeles"
+		month.Data = append(month.Data, pcalDay)
+	}
+	return month
+}
+
+func TestIqamahTimetableRules(t *testing.T) {
+	rules := &psched.IqamahRules{
+		Fajr:    &psched.IqamahRule{Type: psched.IqamahRoundUp, Minutes: 20, RoundTo: 15},
+		Dhuhr:   &psched.IqamahRule{Type: psched.IqamahFixed, Time: "13:30"},
+		Asr:     &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 10},
+		Maghrib: &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 5},
+		Isha:    &psched.IqamahRule{Type: psched.IqamahNotEarlierThan, Minutes: 10, Time: "20:00"},
+	}
+
+	timetable, err := rules.Timetable(iqamahTestMonth())
+	if err != nil {
+		t.Fatalf("unable to build iqamah timetable: %s", err)
+	}
+
+	if len(timetable.Days) != 7 {
+		t.Fatalf("iqamah timetable has %d days, want 7", len(timetable.Days))
+	}
+
+	// Fajr adhan 05:39 + 20 minutes is 05:59, rounded up to 06:00
+	day := timetable.Days[0].Iqamah
+	if day.Fajr != "06:00 (PDT)" {
+		t.Errorf("round up iqamah is %s, want 06:00 (PDT)", day.Fajr)
+	}
+	if day.Dhuhr != "13:30 (PDT)" {
+		t.Errorf("fixed iqamah is %s, want 13:30 (PDT)", day.Dhuhr)
+	}
+	if day.Asr != "16:09 (PDT)" {
+		t.Errorf("after adhan iqamah is %s, want 16:09 (PDT)", day.Asr)
+	}
+	if day.Maghrib != "18:32 (PDT)" {
+		t.Errorf("after adhan iqamah is %s, want 18:32 (PDT)", day.Maghrib)
+	}
+	if day.Isha != "20:00 (PDT)" {
+		t.Errorf("not earlier than iqamah is %s, want 20:00 (PDT)", day.Isha)
+	}
+	if day.Sunrise != "" {
+		t.Errorf("sunrise should not have an iqamah time: %s", day.Sunrise)
+	}
+
+	// Fajr adhan 05:45 + 20 minutes is 06:05, rounded up to 06:15
+	if timetable.Days[6].Iqamah.Fajr != "06:15 (PDT)" {
+		t.Errorf("round up iqamah on the 7th is %s, want 06:15 (PDT)", timetable.Days[6].Iqamah.Fajr)
+	}
+}
+
+func TestIqamahTimetableBoundaries(t *testing.T) {
+	// 1st of October 2022 is a Saturday.  A Friday week start splits the days into 1-6 and 7
+	rules := &psched.IqamahRules{
+		Fajr:      &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 10, Boundary: psched.IqamahWeekly},
+		Isha:      &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 10, Boundary: psched.IqamahMonthly},
+		WeekStart: 5,
+	}
+
+	timetable, err := rules.Timetable(iqamahTestMonth())
+	if err != nil {
+		t.Fatalf("unable to build iqamah timetable: %s", err)
+	}
+
+	// Latest Fajr adhan of the first week is 05:44 on the 6th
+	for i := 0; i < 6; i++ {
+		if timetable.Days[i].Iqamah.Fajr != "05:54 (PDT)" {
+			t.Errorf("weekly iqamah on day %d is %s, want 05:54 (PDT)", i+1, timetable.Days[i].Iqamah.Fajr)
+		}
+	}
+	if timetable.Days[6].Iqamah.Fajr != "05:55 (PDT)" {
+		t.Errorf("weekly iqamah on the new week is %s, want 05:55 (PDT)", timetable.Days[6].Iqamah.Fajr)
+	}
+
+	for i := range timetable.Days {
+		if timetable.Days[i].Iqamah.Isha != "19:53 (PDT)" {
+			t.Errorf("monthly iqamah on day %d is %s, want 19:53 (PDT)", i+1, timetable.Days[i].Iqamah.Isha)
+		}
+		if timetable.Days[i].Iqamah.Dhuhr != "" {
+			t.Errorf("prayer without a rule has an iqamah time: %s", timetable.Days[i].Iqamah.Dhuhr)
+		}
+	}
+}
+
+func TestIqamahTimetableWeekAcrossMonths(t *testing.T) {
+	// With a Friday week start, Friday the 30th of September 2022 begins the week of the 1st to the 6th of October
+	rules := &psched.IqamahRules{
+		Fajr:      &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 10, Boundary: psched.IqamahWeekly},
+		WeekStart: 5,
+	}
+	september := &psched.PCalOutput{Code: 200, Status: "OK"}
+	for day := 29; day <= 30; day++ {
+		pcalDay := psched.PCalDay{Timings: psched.FiveDailyPrayers{Fajr: "05:50 (PDT)"}}
+		pcalDay.Date.Gregorian.Date = fmt.Sprintf("%02d-09-2022", day)
+		pcalDay.Meta.Timezone = "America/Los_Angeles"
+		september.Data = append(september.Data, pcalDay)
+	}
+
+	// Without the previous month the week is cut at the 1st of October
+	cut, err := rules.Timetable(iqamahTestMonth())
+	if err != nil {
+		t.Fatalf("unable to build iqamah timetable: %s", err)
+	}
+	if cut.Days[0].Iqamah.Fajr != "05:54 (PDT)" {
+		t.Errorf("weekly iqamah cut at the month start is %s, want 05:54 (PDT)", cut.Days[0].Iqamah.Fajr)
+	}
+
+	timetable, err := rules.TimetableWithAdjacent(iqamahTestMonth(), september)
+	if err != nil {
+		t.Fatalf("unable to build iqamah timetable: %s", err)
+	}
+	if len(timetable.Days) != 7 {
+		t.Fatalf("timetable has %d days, want the 7 days of the month", len(timetable.Days))
+	}
+	// The 05:50 adhan of the 30th of September is the latest of its week
+	for i := 0; i < 6; i++ {
+		if timetable.Days[i].Iqamah.Fajr != "06:00 (PDT)" {
+			t.Errorf("weekly iqamah on day %d is %s, want 06:00 (PDT) as on the 30th of September", i+1, timetable.Days[i].Iqamah.Fajr)
+		}
+	}
+	if timetable.Days[6].Iqamah.Fajr != "05:55 (PDT)" {
+		t.Errorf("weekly iqamah on the new week is %s, want 05:55 (PDT)", timetable.Days[6].Iqamah.Fajr)
+	}
+}
@@ -0,0 +1,230 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// localImsakMinutes is the minutes Imsak is before Fajr, as used by aladhan
+const localImsakMinutes = 10
+
+// CalculationMethod contains the sun angles of a prayer time calculation method
+type CalculationMethod struct {
+	Name               string
+	FajrAngle          float64 // Degrees the sun is below the horizon at Fajr
+	IshaAngle          float64 // Degrees the sun is below the horizon at Isha.  Ignored when IshaMinutes is set
+	IshaMinutes        int     // Minutes Isha is after Maghrib
+	IshaRamadanMinutes int     // Minutes Isha is after Maghrib during Ramadan, when different from IshaMinutes
+	MaghribAngle       float64 // Degrees the sun is below the horizon at Maghrib.  0 for sunset
+}
+
+// CalculationMethods are the calculation methods by their aladhan method number
+var CalculationMethods = map[int]CalculationMethod{
+	0:  {Name: "Shia Ithna-Ashari, Leva Institute, Qum", FajrAngle: 16, IshaAngle: 14, MaghribAngle: 4},
+	1:  {Name: "University of Islamic Sciences, Karachi", FajrAngle: 18, IshaAngle: 18},
+	2:  {Name: "Islamic Society of North America", FajrAngle: 15, IshaAngle: 15},
+	3:  {Name: "Muslim World League", FajrAngle: 18, IshaAngle: 17},
+	4:  {Name: "Umm Al-Qura University, Makkah", FajrAngle: 18.5, IshaMinutes: 90, IshaRamadanMinutes: 120},
+	5:  {Name: "Egyptian General Authority of Survey", FajrAngle: 19.5, IshaAngle: 17.5},
+	7:  {Name: "Institute of Geophysics, University of Tehran", FajrAngle: 17.7, IshaAngle: 14, MaghribAngle: 4.5},
+	8:  {Name: "Gulf Region", FajrAngle: 19.5, IshaMinutes: 90},
+	9:  {Name: "Kuwait", FajrAngle: 18, IshaAngle: 17.5},
+	10: {Name: "Qatar", FajrAngle: 18, IshaMinutes: 90},
+	11: {Name: "Majlis Ugama Islam Singapura, Singapore", FajrAngle: 20, IshaAngle: 18},
+	12: {Name: "Union Organization islamic de France", FajrAngle: 12, IshaAngle: 12},
+	13: {Name: "Diyanet İşleri Başkanlığı, Turkey", FajrAngle: 18, IshaAngle: 17},
+	14: {Name: "Spiritual Administration of Muslims of Russia", FajrAngle: 16, IshaAngle: 15},
+	15: {Name: "Moonsighting Committee Worldwide", FajrAngle: 18, IshaAngle: 18}, // Without the seasonal adjustments
+	16: {Name: "Dubai", FajrAngle: 18.2, IshaAngle: 18.2},
+	17: {Name: "Jabatan Kemajuan Islam Malaysia", FajrAngle: 20, IshaAngle: 18},
+	18: {Name: "Tunisia", FajrAngle: 18, IshaAngle: 18},
+	19: {Name: "Algeria", FajrAngle: 18, IshaAngle: 17},
+	20: {Name: "Kementerian Agama Republik Indonesia", FajrAngle: 20, IshaAngle: 18},
+	21: {Name: "Morocco", FajrAngle: 19, IshaAngle: 17},
+	22: {Name: "Comunidade Islamica de Lisboa", FajrAngle: 18, IshaMinutes: 77},
+	23: {Name: "Ministry of Awqaf, Islamic Affairs and Holy Places, Jordan", FajrAngle: 18, IshaAngle: 18},
+}
+
+// High latitude rules of PCalInput.LatitudeAdjustmentMethod
+const (
+	LatitudeAdjustmentMiddleOfTheNight = 1
+	LatitudeAdjustmentOneSeventh       = 2
+	LatitudeAdjustmentAngleBased       = 3
+)
+
+/*
+LocalProvider is a MonthProvider which calculates the prayer timings itself, without any network requests.
+Timings are within about a minute of aladhan.  Timings are in Location, or in the location of input.CustTime when
+Location is nil, since the timezone of coordinates cannot be looked up offline.
+*/
+type LocalProvider struct {
+	Location *time.Location
+	Method   *CalculationMethod // Used instead of the method of input.Institution when set
+}
+
+// localTimes are the calculated times of a day
+type localTimes struct {
+	imsak, fajr, sunrise, dhuhr, asr, maghrib, isha time.Time
+}
+
+// MonthlyPrayers calculates the prayer timings of the month of input.CustTime
+func (l *LocalProvider) MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	method, err := l.method(input.Institution)
+	if err != nil {
+		return nil, err
+	}
+	if input.Latitude < -90 || input.Latitude > 90 || input.Longitude < -180 || input.Longitude > 180 {
+		return nil, fmt.Errorf("coordinates are out of range: %v, %v", input.Latitude, input.Longitude)
+	}
+	location := l.Location
+	if location == nil {
+		location = input.CustTime.Location()
+	}
+
+	output := &PCalOutput{Code: 200, Status: "OK", Latitude: input.Latitude, Longitude: input.Longitude, Source: SourceLocal}
+	first := time.Date(input.CustTime.Year(), input.CustTime.Month(), 1, 0, 0, 0, 0, location)
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		times, err := calculateLocalTimes(day, float64(input.Latitude), float64(input.Longitude), method, input)
+		if err != nil {
+			return nil, err
+		}
+
+		format := func(t time.Time, offset int) string {
+			return t.Add(time.Duration(offset) * time.Minute).In(location).Format("15:04 (MST)")
+		}
+		pcalDay := PCalDay{
+			Timings: FiveDailyPrayers{
+				Imsak:   format(times.imsak, input.Offsets.Imsak),
+				Fajr:    format(times.fajr, input.Offsets.Fajr),
+				Sunrise: format(times.sunrise, input.Offsets.Sunrise),
+				Dhuhr:   format(times.dhuhr, input.Offsets.Dhuhr),
+				Asr:     format(times.asr, input.Offsets.Asr),
+				Maghrib: format(times.maghrib, input.Offsets.Maghrib),
+				Isha:    format(times.isha, input.Offsets.Isha),
+			},
+			Meta: PCalMeta{Latitude: float64(input.Latitude), Longitude: float64(input.Longitude), Timezone: location.String()},
+		}
+		pcalDay.Date.Readable = day.Format("02 Jan 2006")
+		pcalDay.Date.Timestamp = fmt.Sprint(day.Unix())
+		pcalDay.Date.Gregorian.Date = day.Format("02-01-2006")
+		output.Data = append(output.Data, pcalDay)
+	}
+	return output, nil
+}
+
+func (l *LocalProvider) method(institution int) (CalculationMethod, error) {
+	if l.Method != nil {
+		return *l.Method, nil
+	}
+	method, ok := CalculationMethods[institution]
+	if !ok {
+		return CalculationMethod{}, fmt.Errorf("calculation method %d is not supported by the local provider", institution)
+	}
+	return method, nil
+}
+
+// calculateLocalTimes returns the prayer times of the calendar day of day, rounded to the minute
+func calculateLocalTimes(day time.Time, latitude float64, longitude float64, method CalculationMethod, input *PCalInput) (*localTimes, error) {
+	// Times are calculated as UTC hours from the UTC midnight of the same calendar date
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	at := func(hours float64) time.Time {
+		return midnight.Add(time.Duration(hours * float64(time.Hour)))
+	}
+	approximateNoon := 12 - longitude/15
+
+	// sunAt returns when the sun reaches altitude before (direction -1) or after (direction 1) noon, refining the
+	// sun position at the previous estimate
+	sunAt := func(altitude func(declination float64) float64, direction float64) (float64, bool) {
+		hours := approximateNoon + direction*6
+		for i := 0; i < 3; i++ {
+			declination, equationOfTime := sunPosition(julianDate(at(hours)))
+			hourAngle, ok := hourAngleHours(latitude, declination, altitude(declination))
+			if !ok {
+				return 0, false
+			}
+			hours = 12 - longitude/15 - equationOfTime + direction*hourAngle
+		}
+		return hours, true
+	}
+	below := func(degrees float64) func(float64) float64 {
+		return func(float64) float64 { return -degrees }
+	}
+
+	_, equationOfTime := sunPosition(julianDate(at(approximateNoon)))
+	dhuhr := 12 - longitude/15 - equationOfTime
+	sunrise, sunriseOK := sunAt(below(0.833), -1)
+	sunset, sunsetOK := sunAt(below(0.833), 1)
+	if !sunriseOK || !sunsetOK {
+		return nil, fmt.Errorf("the sun does not rise and set at latitude %v on %s", latitude, day.Format("2006-01-02"))
+	}
+
+	shadowFactor := 1.0
+	if input.School == 1 {
+		shadowFactor = 2
+	}
+	asr, asrOK := sunAt(func(declination float64) float64 { return asrAltitude(shadowFactor, latitude, declination) }, 1)
+	if !asrOK {
+		return nil, fmt.Errorf("asr does not occur at latitude %v on %s", latitude, day.Format("2006-01-02"))
+	}
+
+	maghrib := sunset
+	if method.MaghribAngle > 0 {
+		if angleMaghrib, ok := sunAt(below(method.MaghribAngle), 1); ok {
+			maghrib = angleMaghrib
+		}
+	}
+
+	fajr, fajrOK := sunAt(below(method.FajrAngle), -1)
+	var isha float64
+	ishaOK := true
+	if method.IshaMinutes > 0 {
+		minutes := method.IshaMinutes
+		if method.IshaRamadanMinutes > 0 {
+			if hijri, err := ToHijri(day, UmmAlQura, 0); err == nil && hijri.Month == 9 {
+				minutes = method.IshaRamadanMinutes
+			}
+		}
+		isha = maghrib + float64(minutes)/60
+	} else {
+		isha, ishaOK = sunAt(below(method.IshaAngle), 1)
+	}
+
+	// Limit Fajr and Isha to a portion of the night, which also covers nights when the sun is never far enough below
+	// the horizon
+	night := 24 - (sunset - sunrise)
+	portion := func(angle float64) float64 {
+		switch input.LatitudeAdjustmentMethod {
+		case LatitudeAdjustmentMiddleOfTheNight:
+			return night / 2
+		case LatitudeAdjustmentOneSeventh:
+			return night / 7
+		default:
+			return angle / 60 * night
+		}
+	}
+	if fajrPortion := portion(method.FajrAngle); !fajrOK || sunrise-fajr > fajrPortion {
+		fajr = sunrise - fajrPortion
+	}
+	if method.IshaMinutes == 0 {
+		if ishaPortion := portion(method.IshaAngle); !ishaOK || isha-sunset > ishaPortion {
+			isha = sunset + ishaPortion
+		}
+	}
+
+	round := func(hours float64) time.Time {
+		return at(hours).Round(time.Minute)
+	}
+	return &localTimes{
+		imsak:   round(fajr).Add(-localImsakMinutes * time.Minute),
+		fajr:    round(fajr),
+		sunrise: round(sunrise),
+		dhuhr:   round(dhuhr),
+		asr:     round(asr),
+		maghrib: round(maghrib),
+		isha:    round(isha),
+	}, nil
+}
@@ -0,0 +1,123 @@
+package schedule_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func localTestMonth(t *testing.T, location string, input *psched.PCalInput) *psched.PCalOutput {
+	t.Helper()
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.CustTime = time.Date(input.CustTime.Year(), input.CustTime.Month(), input.CustTime.Day(), 0, 0, 0, 0, loc)
+	output, err := (&psched.LocalProvider{}).MonthlyPrayers(context.Background(), input)
+	if err != nil {
+		t.Fatalf("local provider returned an error: %s", err)
+	}
+	return output
+}
+
+func TestLocalProvider(t *testing.T) {
+	output := localTestMonth(t, "America/Los_Angeles", &psched.PCalInput{
+		CustTime:    time.Date(2022, time.October, 1, 0, 0, 0, 0, time.UTC),
+		Institution: 2,
+		Latitude:    34.103,
+		Longitude:   -118.4105,
+	})
+	if output.Source != psched.SourceLocal || len(output.Data) != 31 {
+		t.Fatalf("unexpected month: source %q with %d days", output.Source, len(output.Data))
+	}
+
+	first := output.Data[0]
+	if first.Date.Gregorian.Date != "01-10-2022" || first.Meta.Timezone != "America/Los_Angeles" {
+		t.Errorf("unexpected day: %+v %+v", first.Date, first.Meta)
+	}
+	// Sunrise, noon and sunset in Los Angeles on 1 October 2022 were 06:47, 12:43 and 18:38
+	expected := map[string]string{"Sunrise": "06:47", "Dhuhr": "12:43", "Maghrib": "18:38"}
+	actual := map[string]string{"Sunrise": first.Timings.Sunrise, "Dhuhr": first.Timings.Dhuhr, "Maghrib": first.Timings.Maghrib}
+	for prayer, expectedTime := range expected {
+		expectedClock, _ := time.Parse("15:04", expectedTime)
+		actualClock, err := time.Parse("15:04 (MST)", actual[prayer])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if difference := actualClock.Sub(expectedClock); difference > time.Minute || difference < -time.Minute {
+			t.Errorf("%s is %s, expected %s", prayer, actual[prayer], expectedTime)
+		}
+	}
+
+	fajr, _ := time.Parse("15:04 (MST)", first.Timings.Fajr)
+	imsak, _ := time.Parse("15:04 (MST)", first.Timings.Imsak)
+	if fajr.Sub(imsak) != 10*time.Minute {
+		t.Errorf("imsak %s is not 10 minutes before fajr %s", first.Timings.Imsak, first.Timings.Fajr)
+	}
+}
+
+func TestLocalProviderSettings(t *testing.T) {
+	input := func() *psched.PCalInput {
+		return &psched.PCalInput{CustTime: time.Date(2022, time.October, 1, 0, 0, 0, 0, time.UTC), Institution: 3, Latitude: 34.103, Longitude: -118.4105}
+	}
+	shafi := localTestMonth(t, "America/Los_Angeles", input())
+	hanafiInput := input()
+	hanafiInput.School = 1
+	hanafi := localTestMonth(t, "America/Los_Angeles", hanafiInput)
+	if hanafi.Data[0].Timings.Asr <= shafi.Data[0].Timings.Asr {
+		t.Errorf("hanafi asr %s is not after shafi asr %s", hanafi.Data[0].Timings.Asr, shafi.Data[0].Timings.Asr)
+	}
+
+	offsetInput := input()
+	offsetInput.Offsets = psched.PrayerOffsets{Isha: 5}
+	offset := localTestMonth(t, "America/Los_Angeles", offsetInput)
+	isha, _ := time.Parse("15:04 (MST)", shafi.Data[0].Timings.Isha)
+	offsetIsha, _ := time.Parse("15:04 (MST)", offset.Data[0].Timings.Isha)
+	if offsetIsha.Sub(isha) != 5*time.Minute {
+		t.Errorf("isha offset was not applied: %s %s", shafi.Data[0].Timings.Isha, offset.Data[0].Timings.Isha)
+	}
+
+	// Umm al-Qura Isha is 120 minutes after Maghrib in Ramadan, which began on 23 March 2023
+	ummAlQura := localTestMonth(t, "Asia/Riyadh", &psched.PCalInput{CustTime: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), Institution: 4, Latitude: 21.4225, Longitude: 39.8262})
+	for _, day := range []int{21, 24} {
+		timings := ummAlQura.Data[day].Timings
+		maghrib, _ := time.Parse("15:04 (MST)", timings.Maghrib)
+		isha, _ := time.Parse("15:04 (MST)", timings.Isha)
+		expected := 90 * time.Minute
+		if day == 24 {
+			expected = 120 * time.Minute
+		}
+		if isha.Sub(maghrib) != expected {
+			t.Errorf("isha on %d March is %s after maghrib, expected %s", day+1, isha.Sub(maghrib), expected)
+		}
+	}
+}
+
+func TestLocalProviderHighLatitude(t *testing.T) {
+	// The sun does not go 18 degrees below the horizon in London in June, so Fajr and Isha are limited to a portion of the night
+	for _, rule := range []int{0, psched.LatitudeAdjustmentMiddleOfTheNight, psched.LatitudeAdjustmentOneSeventh, psched.LatitudeAdjustmentAngleBased} {
+		output := localTestMonth(t, "Europe/London", &psched.PCalInput{
+			CustTime:                 time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Institution:              3,
+			Latitude:                 51.5074,
+			Longitude:                -0.1278,
+			LatitudeAdjustmentMethod: rule,
+		})
+		timings := output.Data[20].Timings
+		// Middle of the night puts Isha after midnight, at the same time as Fajr
+		if timings.Fajr >= timings.Sunrise || (rule != psched.LatitudeAdjustmentMiddleOfTheNight && timings.Isha <= timings.Maghrib) {
+			t.Errorf("rule %d returned fajr %s, sunrise %s, maghrib %s, isha %s", rule, timings.Fajr, timings.Sunrise, timings.Maghrib, timings.Isha)
+		}
+	}
+
+	// The sun does not set in Tromsø in June
+	loc, _ := time.LoadLocation("Europe/Oslo")
+	_, err := (&psched.LocalProvider{Location: loc}).MonthlyPrayers(context.Background(), &psched.PCalInput{
+		CustTime: time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC), Institution: 3, Latitude: 69.6492, Longitude: 18.9553,
+	})
+	if err == nil {
+		t.Error("midnight sun did not return an error")
+	}
+}
@@ -0,0 +1,197 @@
+package schedule
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed WMM.COF
+var defaultMagneticModelFile []byte
+
+const (
+	magneticReferenceRadius = 6371.2   // Geomagnetic reference radius in km
+	wgs84SemiMajorAxis      = 6378.137 // WGS84 semi-major axis in km
+	wgs84Flattening         = 1 / 298.257223563
+	magneticModelLifespan   = 5.0 // Years a World Magnetic Model is valid for after its epoch
+)
+
+// MagneticModel is a World Magnetic Model loaded from a WMM.COF coefficient file
+type MagneticModel struct {
+	Name   string
+	Epoch  float64 // Decimal year the coefficients are given for
+	degree int
+	g, h   [][]float64
+	gDot   [][]float64
+	hDot   [][]float64
+}
+
+// DefaultMagneticModel returns the World Magnetic Model embedded in the library, WMM-2020, which is valid until 2025.0.
+// Load a newer model from NOAA with LoadMagneticModel for later days
+func DefaultMagneticModel() (*MagneticModel, error) {
+	return LoadMagneticModel(bytes.NewReader(defaultMagneticModelFile))
+}
+
+// LoadMagneticModel reads a World Magnetic Model from a WMM.COF coefficient file, such as a newer model from NOAA
+func LoadMagneticModel(r io.Reader) (*MagneticModel, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("magnetic model file is empty")
+	}
+
+	header := strings.Fields(scanner.Text())
+	if len(header) < 2 {
+		return nil, fmt.Errorf("magnetic model header is malformed: %q", scanner.Text())
+	}
+	epoch, err := strconv.ParseFloat(header[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("magnetic model epoch is malformed: %s", err)
+	}
+	model := &MagneticModel{Name: header[1], Epoch: epoch}
+
+	type coefficient struct {
+		n, m             int
+		g, h, gDot, hDot float64
+	}
+	var coefficients []coefficient
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "9999") {
+			break
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("magnetic model coefficient line is malformed: %q", line)
+		}
+		values := make([]float64, 6)
+		for i, field := range fields {
+			if values[i], err = strconv.ParseFloat(field, 64); err != nil {
+				return nil, fmt.Errorf("magnetic model coefficient is malformed: %q", line)
+			}
+		}
+		c := coefficient{n: int(values[0]), m: int(values[1]), g: values[2], h: values[3], gDot: values[4], hDot: values[5]}
+		if c.n < 1 || c.m < 0 || c.m > c.n {
+			return nil, fmt.Errorf("magnetic model coefficient degree and order are invalid: %q", line)
+		}
+		if c.n > model.degree {
+			model.degree = c.n
+		}
+		coefficients = append(coefficients, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read magnetic model: %s", err)
+	}
+	if model.degree == 0 {
+		return nil, fmt.Errorf("magnetic model has no coefficients")
+	}
+
+	newTable := func() [][]float64 {
+		table := make([][]float64, model.degree+1)
+		for n := range table {
+			table[n] = make([]float64, n+1)
+		}
+		return table
+	}
+	model.g, model.h, model.gDot, model.hDot = newTable(), newTable(), newTable(), newTable()
+	for _, c := range coefficients {
+		model.g[c.n][c.m] = c.g
+		model.h[c.n][c.m] = c.h
+		model.gDot[c.n][c.m] = c.gDot
+		model.hDot[c.n][c.m] = c.hDot
+	}
+	return model, nil
+}
+
+// Covers reports whether date is within the five years the model is valid for
+func (model *MagneticModel) Covers(date time.Time) bool {
+	year := decimalYear(date)
+	return year >= model.Epoch && year < model.Epoch+magneticModelLifespan
+}
+
+// Declination returns the magnetic declination in degrees at sea level, positive when magnetic north is east of true north
+func (model *MagneticModel) Declination(latitude float64, longitude float64, date time.Time) (float64, error) {
+	year := decimalYear(date)
+	if !model.Covers(date) {
+		return 0, fmt.Errorf("%s is only valid from %.1f to %.1f, not %.2f", model.Name, model.Epoch, model.Epoch+magneticModelLifespan, year)
+	}
+	north, east, _ := model.field(latitude, longitude, 0, year-model.Epoch)
+	return darctan2(east, north), nil
+}
+
+// field returns the north, east and down components of the magnetic field in nT at a geodetic position.
+// height is in km above the ellipsoid and years is the time since the model epoch.
+func (model *MagneticModel) field(latitude float64, longitude float64, height float64, years float64) (float64, float64, float64) {
+	// Convert the geodetic position to geocentric spherical coordinates
+	eccentricitySquared := wgs84Flattening * (2 - wgs84Flattening)
+	curvatureRadius := wgs84SemiMajorAxis / math.Sqrt(1-eccentricitySquared*dsin(latitude)*dsin(latitude))
+	p := (curvatureRadius + height) * dcos(latitude)
+	z := (curvatureRadius*(1-eccentricitySquared) + height) * dsin(latitude)
+	radius := math.Hypot(p, z)
+	geocentricLatitude := darcsin(z / radius)
+
+	// Schmidt semi-normalised associated Legendre functions of sin(geocentric latitude) and their latitude derivatives
+	x, cosLatitude := dsin(geocentricLatitude), dcos(geocentricLatitude)
+	legendre := make([][]float64, model.degree+1)
+	derivative := make([][]float64, model.degree+1)
+	for n := 0; n <= model.degree; n++ {
+		legendre[n] = make([]float64, n+1)
+		derivative[n] = make([]float64, n+1)
+	}
+	legendre[0][0] = 1
+	for n := 1; n <= model.degree; n++ {
+		if n == 1 {
+			legendre[1][1] = cosLatitude
+			derivative[1][1] = -x
+		} else {
+			factor := math.Sqrt(1 - 1/(2*float64(n)))
+			legendre[n][n] = factor * cosLatitude * legendre[n-1][n-1]
+			derivative[n][n] = factor * (cosLatitude*derivative[n-1][n-1] - x*legendre[n-1][n-1])
+		}
+		for m := 0; m < n; m++ {
+			var previous, previousDerivative float64
+			if n-2 >= m {
+				previous, previousDerivative = legendre[n-2][m], derivative[n-2][m]
+			}
+			k := math.Sqrt(float64((n-1)*(n-1) - m*m))
+			scale := math.Sqrt(float64(n*n - m*m))
+			legendre[n][m] = (float64(2*n-1)*x*legendre[n-1][m] - k*previous) / scale
+			derivative[n][m] = (float64(2*n-1)*(cosLatitude*legendre[n-1][m]+x*derivative[n-1][m]) - k*previousDerivative) / scale
+		}
+	}
+
+	var north, east, down float64
+	ratio := magneticReferenceRadius / radius
+	for n := 1; n <= model.degree; n++ {
+		radial := math.Pow(ratio, float64(n+2))
+		for m := 0; m <= n; m++ {
+			g := model.g[n][m] + years*model.gDot[n][m]
+			h := model.h[n][m] + years*model.hDot[n][m]
+			cosM, sinM := dcos(float64(m)*longitude), dsin(float64(m)*longitude)
+			north -= radial * (g*cosM + h*sinM) * derivative[n][m]
+			east += radial * float64(m) * (g*sinM - h*cosM) * legendre[n][m] / cosLatitude
+			down -= radial * float64(n+1) * (g*cosM + h*sinM) * legendre[n][m]
+		}
+	}
+
+	// Rotate the geocentric field back to the geodetic frame
+	rotation := geocentricLatitude - latitude
+	return north*dcos(rotation) - down*dsin(rotation), east, north*dsin(rotation) + down*dcos(rotation)
+}
+
+// decimalYear returns date as a fractional year
+func decimalYear(date time.Time) float64 {
+	date = date.UTC()
+	start := time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	return float64(date.Year()) + float64(date.Sub(start))/float64(end.Sub(start))
+}
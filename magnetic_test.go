@@ -0,0 +1,61 @@
+package schedule_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// Test values published with the WMM2020 report for sea level at 2020.0
+func TestMagneticDeclination(t *testing.T) {
+	model, err := psched.DefaultMagneticModel()
+	if err != nil {
+		t.Fatalf("unable to load the embedded magnetic model: %s", err)
+	}
+
+	epoch := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		latitude, longitude, declination float64
+	}{
+		{80, 0, -1.28},
+		{0, 120, 0.16},
+		{-80, 240, 69.36},
+	}
+	for _, test := range tests {
+		declination, err := model.Declination(test.latitude, test.longitude, epoch)
+		if err != nil {
+			t.Fatalf("unable to calculate declination: %s", err)
+		}
+		if math.Abs(declination-test.declination) > 0.01 {
+			t.Errorf("declination at %v, %v is %.2f, want %.2f", test.latitude, test.longitude, declination, test.declination)
+		}
+	}
+
+	if _, err := model.Declination(0, 0, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("declination outside of the model lifespan did not return an error")
+	}
+}
+
+func TestLoadMagneticModel(t *testing.T) {
+	// A dipole only model pointing magnetic north at true north has no declination anywhere
+	model, err := psched.LoadMagneticModel(strings.NewReader("    2025.0            TEST        01/01/2025\n" +
+		"  1  0  -30000.0       0.0        0.0        0.0\n" +
+		"999999999999999999999999999999999999999999999999\n"))
+	if err != nil {
+		t.Fatalf("unable to load magnetic model: %s", err)
+	}
+	declination, err := model.Declination(45, 90, time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unable to calculate declination: %s", err)
+	}
+	if math.Abs(declination) > 1e-9 {
+		t.Errorf("dipole model declination is %v, want 0", declination)
+	}
+
+	if _, err := psched.LoadMagneticModel(strings.NewReader("2025.0 TEST\n1 0 abc 0 0 0\n")); err == nil {
+		t.Error("malformed coefficient did not return an error")
+	}
+}
@@ -0,0 +1,189 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+/*
+PrayerNow returns the current and next prayer at the customer location at instant.
+The previous, current and next day timings are looked up by PrayerNow itself, including from the adjacent month
+on the first or last day of a month.  instant is moved into the timezone of the location before it is compared.
+*/
+func (c *CustomerLocationInput) PrayerNow(instant time.Time) (*DeterminedPrayerOutput, error) {
+	days, localNow, err := c.adjacentDays(context.Background(), instant)
+	if err != nil {
+		return nil, err
+	}
+	return days.prayerNow(localNow)
+}
+
+// CurrentPrayer returns the current and next prayer at the customer location at the time of Clock
+func (c *CustomerLocationInput) CurrentPrayer() (*DeterminedPrayerOutput, error) {
+	return c.PrayerNow(clockOrSystem(c.Clock).Now())
+}
+
+// PrayerDay returns the timings of the day instant falls on at the customer location, with its Hijri date set
+func (c *CustomerLocationInput) PrayerDay(instant time.Time) (*PCalDay, error) {
+	return c.PrayerDayContext(context.Background(), instant)
+}
+
+// PrayerDayContext is PrayerDay with the location and month lookups given up when ctx is done
+func (c *CustomerLocationInput) PrayerDayContext(ctx context.Context, instant time.Time) (*PCalDay, error) {
+	days, localNow, err := c.adjacentDays(ctx, instant)
+	if err != nil {
+		return nil, err
+	}
+	return c.hijriDay(days, localNow)
+}
+
+// PrayerMoment is the current and next prayer at an instant together with the timings of its day
+type PrayerMoment struct {
+	Instant time.Time               // The instant in the timezone of the location
+	Day     *PCalDay                // Timings of the day of Instant, with its Hijri date set
+	Prayer  *DeterminedPrayerOutput // Current and next prayer at Instant
+}
+
+/*
+PrayerAt returns the results of both PrayerDay and PrayerNow at instant, resolving the customer location and looking
+up each month once.  The lookups are given up when ctx is done.
+*/
+func (c *CustomerLocationInput) PrayerAt(ctx context.Context, instant time.Time) (*PrayerMoment, error) {
+	days, localNow, err := c.adjacentDays(ctx, instant)
+	if err != nil {
+		return nil, err
+	}
+	day, err := c.hijriDay(days, localNow)
+	if err != nil {
+		return nil, err
+	}
+	prayer, err := days.prayerNow(localNow)
+	if err != nil {
+		return nil, err
+	}
+	return &PrayerMoment{Instant: localNow, Day: day, Prayer: prayer}, nil
+}
+
+// hijriDay returns the timings of the day of localNow with its Hijri date set
+func (c *CustomerLocationInput) hijriDay(days *adjacentDays, localNow time.Time) (*PCalDay, error) {
+	day, err := days.day(localNow)
+	if err != nil {
+		return nil, err
+	}
+
+	output := *day
+	date, err := output.Day()
+	if err != nil {
+		return nil, err
+	}
+	if output.Hijri, err = ToHijri(date, c.HijriCalendar, c.HijriAdjustment); err != nil {
+		return nil, fmt.Errorf("unable to set hijri date: %s", err)
+	}
+	return &output, nil
+}
+
+// adjacentDays resolves the customer location and returns the day lookup of it with instant in its timezone
+func (c *CustomerLocationInput) adjacentDays(ctx context.Context, instant time.Time) (*adjacentDays, time.Time, error) {
+	nowInput := *c
+	nowInput.CustTime = instant
+	input, err := nowInput.resolvePCalInput(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	days := &adjacentDays{ctx: ctx, provider: c.provider(), input: input, months: make(map[string]*PCalOutput)}
+	localNow, err := days.local(instant)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return days, localNow, nil
+}
+
+// adjacentDays looks up single days, requesting each month from the provider at most once
+type adjacentDays struct {
+	ctx      context.Context // Context of the provider lookups.  Defaults to context.Background()
+	provider MonthProvider
+	input    *PCalInput
+	months   map[string]*PCalOutput
+	location *time.Location // Timezone of the timings, taken from the first month looked up
+}
+
+/*
+local returns instant in the timezone of the timings.  The timezone is only known once a month has been looked up,
+so the first month is picked with the mean solar time of the longitude, which is within a couple of hours of the
+local time almost everywhere.  This avoids looking up the month after the local one near the end of a month in
+timezones behind UTC, such as in the evening of the last day in UTC-7.
+*/
+func (a *adjacentDays) local(instant time.Time) (time.Time, error) {
+	if a.location == nil {
+		solarOffset := int(math.Round(float64(a.input.Longitude)/15)) * 60 * 60
+		if _, err := a.month(instant.In(time.FixedZone("", solarOffset))); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return instant.In(a.location), nil
+}
+
+func (a *adjacentDays) month(day time.Time) (*PCalOutput, error) {
+	monthKey := day.Format("01-2006")
+	if month, ok := a.months[monthKey]; ok {
+		return month, nil
+	}
+
+	monthInput := *a.input
+	monthInput.CustTime = day
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	month, err := a.provider.MonthlyPrayers(ctx, &monthInput)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up prayer calendar of %s: %s", monthKey, err)
+	}
+	a.months[monthKey] = month
+
+	if a.location == nil {
+		a.location = day.Location()
+		if len(month.Data) > 0 {
+			if first, err := month.Data[0].Day(); err == nil {
+				a.location = first.Location()
+			}
+		}
+	}
+	return month, nil
+}
+
+// prayerNow returns the current and next prayer at localNow from the timings of the previous, current and next day
+func (a *adjacentDays) prayerNow(localNow time.Time) (*DeterminedPrayerOutput, error) {
+	previousDay, err := a.day(localNow.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	currentDay, err := a.day(localNow)
+	if err != nil {
+		return nil, err
+	}
+	nextDay, err := a.day(localNow.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	return DetermineWhichPrayer(&previousDay.Timings, &currentDay.Timings, &nextDay.Timings, &localNow)
+}
+
+func (a *adjacentDays) day(day time.Time) (*PCalDay, error) {
+	month, err := a.month(day)
+	if err != nil {
+		return nil, err
+	}
+
+	dateKey := day.Format("02-01-2006")
+	for i := range month.Data {
+		if month.Data[i].Date.Gregorian.Date == dateKey {
+			return &month.Data[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no prayer timings were found for %s", dateKey)
+}
@@ -0,0 +1,106 @@
+package schedule_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func TestPrayerNowAcrossMonths(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for America/Los_Angeles: %s", err)
+	}
+
+	provider := &testMonthProvider{location: losAngeles}
+	customerInput := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    provider,
+	}
+
+	// 23:00 on the last day of March in Los Angeles, given in UTC
+	lastDayIsha, err := customerInput.PrayerNow(time.Date(2023, time.April, 1, 6, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unable to determine the prayer now: %s", err)
+	}
+	if lastDayIsha.CurrentPrayerName != "Isha" || lastDayIsha.PreviousDayIsha {
+		t.Errorf("current prayer is %s, previous day isha %v, want current day Isha", lastDayIsha.CurrentPrayerName, lastDayIsha.PreviousDayIsha)
+	}
+	// Fajr on the 1st of April is looked up from the next month
+	if lastDayIsha.NextPrayerName != "Fajr" || lastDayIsha.NextPrayerTime != "05:10 (PDT)" {
+		t.Errorf("next prayer is %s at %s, want Fajr at 05:10 (PDT)", lastDayIsha.NextPrayerName, lastDayIsha.NextPrayerTime)
+	}
+	if lastDayIsha.TimeDiff != 6*time.Hour+10*time.Minute {
+		t.Errorf("time until fajr is %s, want 6h10m", lastDayIsha.TimeDiff)
+	}
+	if provider.Calls() != 2 {
+		t.Errorf("made %d month lookups, want 2", provider.Calls())
+	}
+
+	// 04:00 on the 1st of April is still Isha of the 31st of March
+	firstDayIsha, err := customerInput.PrayerNow(time.Date(2023, time.April, 1, 4, 0, 0, 0, losAngeles))
+	if err != nil {
+		t.Fatalf("unable to determine the prayer now: %s", err)
+	}
+	if firstDayIsha.CurrentPrayerName != "Isha" || !firstDayIsha.PreviousDayIsha || firstDayIsha.CurrentPrayerTime != "19:30 (PDT)" {
+		t.Errorf("current prayer is %s at %s, previous day isha %v, want previous day Isha", firstDayIsha.CurrentPrayerName, firstDayIsha.CurrentPrayerTime, firstDayIsha.PreviousDayIsha)
+	}
+}
+
+func TestPrayerDayLooksUpLocalMonth(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for America/Los_Angeles: %s", err)
+	}
+
+	provider := &testMonthProvider{location: losAngeles}
+	customerInput := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    provider,
+	}
+
+	// 23:00 on the last day of March in Los Angeles is already April in UTC
+	day, err := customerInput.PrayerDay(time.Date(2023, time.April, 1, 6, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unable to look up the prayer day: %s", err)
+	}
+	if day.Date.Gregorian.Date != "31-03-2023" {
+		t.Errorf("prayer day is %s, want 31-03-2023", day.Date.Gregorian.Date)
+	}
+	if provider.Calls() != 1 {
+		t.Errorf("made %d month lookups, want 1", provider.Calls())
+	}
+}
+
+func TestPrayerAt(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for America/Los_Angeles: %s", err)
+	}
+
+	provider := &testMonthProvider{location: losAngeles}
+	customerInput := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    provider,
+	}
+
+	// 20:00 on the 12th of October in Los Angeles
+	moment, err := customerInput.PrayerAt(context.Background(), time.Date(2022, time.October, 13, 3, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unable to look up the prayer at the instant: %s", err)
+	}
+	if moment.Instant.Location().String() != "America/Los_Angeles" || moment.Instant.Hour() != 20 {
+		t.Errorf("instant is %s, want 20:00 in Los Angeles", moment.Instant)
+	}
+	if moment.Day.Date.Gregorian.Date != "12-10-2022" || moment.Day.Hijri.IsZero() {
+		t.Errorf("day is %s with hijri date %v, want 12-10-2022 with a hijri date", moment.Day.Date.Gregorian.Date, moment.Day.Hijri)
+	}
+	if moment.Prayer.CurrentPrayerName != "Isha" || moment.Prayer.NextPrayerName != "Fajr" {
+		t.Errorf("current prayer is %s and next is %s, want Isha and Fajr", moment.Prayer.CurrentPrayerName, moment.Prayer.NextPrayerName)
+	}
+	if provider.Calls() != 1 {
+		t.Errorf("made %d month lookups, want 1", provider.Calls())
+	}
+}
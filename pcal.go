@@ -3,18 +3,27 @@
 package schedule
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 )
 
 type CustomerLocationInput struct {
-	Coordinates PrayerCalendarInputCoordinates // Only required if HEREAPIKey is not filled
-	CountryCode string
-	CustTime    time.Time
-	HEREAPIKey  string // Only required if Coordinates is not filled
-	Institution int
-	PostalCode  string // Only required if Coordiantes is not filled
+	Coordinates              PrayerCalendarInputCoordinates // Only required if HEREAPIKey is not filled
+	CountryCode              string
+	CustTime                 time.Time
+	HEREAPIKey               string // Only required if Coordinates is not filled
+	Institution              int
+	PostalCode               string        // Only required if Coordiantes is not filled
+	School                   int           // Asr juristic school.  0 for Shafi and 1 for Hanafi
+	LatitudeAdjustmentMethod int           // High latitude rule.  0 for the provider default, 1 middle of the night, 2 one seventh, 3 angle based
+	Offsets                  PrayerOffsets // Minutes added to each calculated time
+	HijriCalendar            HijriCalendar // Calendar used for the Hijri date of each day.  Defaults to UmmAlQura
+	HijriAdjustment          int           // Local moon sighting adjustment of the Hijri date, between -2 and 2 days
+	Provider                 MonthProvider // Source of the monthly prayer timings.  Defaults to AladhanProvider
+	Geocoder                 Geocoder      // Looks up PostalCode when Coordinates is not filled.  Defaults to HERE with HEREAPIKey
+	Clock                    Clock         // Time of CurrentPrayer.  Defaults to SystemClock
 }
 
 type PrayerCalendarInputCoordinates struct {
@@ -57,18 +66,42 @@ PrayerCalendar returns customer monthly prayer data with or without customer pro
 if customer does not provide coordiantes, they must provide a HERE API Key
 */
 func (c *CustomerLocationInput) PrayerCalendar() (*PCalOutput, error) {
+	return c.PrayerCalendarContext(context.Background())
+}
+
+// PrayerCalendarContext is PrayerCalendar with the location and month lookups given up when ctx is done
+func (c *CustomerLocationInput) PrayerCalendarContext(ctx context.Context) (*PCalOutput, error) {
+	monthlyPrayerData, err := c.resolvePCalInput(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	monthlyPrayers, err := c.provider().MonthlyPrayers(ctx, monthlyPrayerData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := monthlyPrayers.SetHijriDates(c.HijriCalendar, c.HijriAdjustment); err != nil {
+		return nil, fmt.Errorf("unable to set hijri dates: %s", err)
+	}
+	return monthlyPrayers, nil
+}
+
+// resolvePCalInput resolves the customer location into the provider input of CustTime
+func (c *CustomerLocationInput) resolvePCalInput(ctx context.Context) (*PCalInput, error) {
 	lookupMethod, err := c.checkCustomerInput()
 	if err != nil {
        fmt.Println(err) 
        return nil, err
 	}
 
-	hereLookup := new(CustomerLocationInputWithHEREAPIKey)
 	monthlyPrayerData := new(PCalInput)
 
 	monthlyPrayerData.CustTime = c.CustTime
 	monthlyPrayerData.Institution = c.Institution
-	hereLookup.CountryCode = c.CountryCode
+	monthlyPrayerData.School = c.School
+	monthlyPrayerData.LatitudeAdjustmentMethod = c.LatitudeAdjustmentMethod
+	monthlyPrayerData.Offsets = c.Offsets
 
 	if lookupMethod != "Coordinates" && lookupMethod != "APIKey" {
 		return nil, fmt.Errorf("coordiantes or APIKey was not provided, cannot continue: %v %s", c, lookupMethod)
@@ -77,53 +110,43 @@ func (c *CustomerLocationInput) PrayerCalendar() (*PCalOutput, error) {
 	if lookupMethod == "Coordinates" {
 		monthlyPrayerData.Longitude = c.Coordinates.Longitude
 		monthlyPrayerData.Latitude = c.Coordinates.Latitude
-		return AladhanData(monthlyPrayerData)
+		return monthlyPrayerData, nil
 	}
 	// Build for condition without coordiantes.  To be used with HERE API
 	if lookupMethod == "APIKey" {
-		hereLookup.PostalCode = c.PostalCode
-		hereLookup.HEREAPIKey = c.HEREAPIKey
-		hereResp, hereCity, err := HERECustomerLocation(hereLookup)
+		coordinates, err := c.geocoder().Geocode(ctx, c.CountryCode, c.PostalCode)
 		if err != nil {
-			return nil, fmt.Errorf("unable to lookup customer location using API Key: %v", hereLookup)
+			return nil, err
 		}
-
-		if hereCity.Coordiantes.Lat == 0 && hereCity.Coordiantes.Lng == 0 {
-			for i := 0; i < len(hereResp.Items); i++ {
-				if hereResp.Items[i].Address.PostalCode == c.PostalCode {
-					monthlyPrayerData.Longitude = hereResp.Items[i].Position.Lng
-					monthlyPrayerData.Latitude = hereResp.Items[i].Position.Lat
-					return AladhanData(monthlyPrayerData)
-				}
-			}
-			return nil, fmt.Errorf("unable to pinpoint customer location based on zip code: %v:", hereResp)
-		}
-		monthlyPrayerData.Longitude = hereCity.Coordiantes.Lng
-		monthlyPrayerData.Latitude = hereCity.Coordiantes.Lat
-		return AladhanData(monthlyPrayerData)
+		monthlyPrayerData.Longitude = coordinates.Lng
+		monthlyPrayerData.Latitude = coordinates.Lat
+		return monthlyPrayerData, nil
 	}
 
 	return nil, fmt.Errorf("unable to locate customer input.  Perhaps not enough input data was given %v:", c)
 }
 
 func (c *CustomerLocationInput)checkCustomerInput() (string, error) {
+	// A custom Geocoder takes the place of the HERE API key
+	hasGeocoder := c.HEREAPIKey != "" || c.Geocoder != nil
+
 	// Check if API key and Coordinates are not filled
-	if c.HEREAPIKey == "" && (c.Coordinates.Longitude == 0 || c.Coordinates.Latitude == 0) {
+	if !hasGeocoder && (c.Coordinates.Longitude == 0 || c.Coordinates.Latitude == 0) {
 		_, err := fmt.Fprintf(os.Stderr, "error: HEREAPIKey and coordinates are not filled.  Must fill one or the other")
 		return "", err
 	}
 
 	// Check if API key and Coordinates are filled
-	if c.HEREAPIKey != "" && (c.Coordinates.Longitude != 0 || c.Coordinates.Latitude != 0) {
+	if hasGeocoder && (c.Coordinates.Longitude != 0 || c.Coordinates.Latitude != 0) {
 		_, err := fmt.Fprintf(os.Stderr, "error: HEREAPIKey and Coordinates are filled.  Cannot fill both fields")
 		return "", err
 	}
 
-	if c.HEREAPIKey != "" && (c.Coordinates.Longitude == 0 || c.Coordinates.Latitude == 0) {
+	if hasGeocoder && (c.Coordinates.Longitude == 0 || c.Coordinates.Latitude == 0) {
 		return "APIKey", nil
 	}
 
-	if c.HEREAPIKey == "" && (c.Coordinates.Longitude != 0 || c.Coordinates.Latitude != 0) {
+	if !hasGeocoder && (c.Coordinates.Longitude != 0 || c.Coordinates.Latitude != 0) {
 		return "Coordinates", nil
 	}
 	return "", fmt.Errorf("Could not determine which method to use between API key or Coordinates")
@@ -0,0 +1,429 @@
+package schedule
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Dimensions of the printed timetable in points
+const (
+	pdfPageWidth     = 595.28 // A4
+	pdfPageHeight    = 841.89
+	pdfMargin        = 36.0
+	pdfLogoHeight    = 48.0
+	pdfTitleHeight   = 60.0 // Title, subtitle and logo above the table
+	pdfFooterHeight  = 20.0
+	pdfRowHeight     = 14.0
+	pdfFontSize      = 8.0
+	pdfCellPadding   = 6.0
+	pdfTitleFontSize = 18.0
+)
+
+// pdfHeaderColor, pdfFridayColor and pdfTodayColor are the fill colors of the table header and highlighted rows
+var (
+	pdfHeaderColor = [3]float64{0.184, 0.365, 0.314}
+	pdfFridayColor = [3]float64{0.878, 0.949, 0.878}
+	pdfTodayColor  = [3]float64{1, 0.949, 0.702}
+	pdfBorderColor = [3]float64{0.533, 0.533, 0.533}
+)
+
+// Widths of the printable ASCII characters of the standard Helvetica fonts in thousandths of the font size
+var (
+	pdfHelveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	pdfHelveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// pdfWinAnsi are the WinAnsiEncoding codes of the characters outside Latin-1 which are likely in a mosque name
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+/*
+WritePDF writes the timetable as an A4 PDF document using the standard Helvetica fonts, so no fonts need to be
+embedded.  The table is split over as many pages as needed with the title and header row repeated on every page, a
+day is never split from its Jumu'ah row, and Fridays and today are highlighted.  Characters outside the Windows-1252
+character set, such as Arabic, are printed as question marks.
+*/
+func (p *PrintableTimetable) WritePDF(w io.Writer) error {
+	table, err := p.table()
+	if err != nil {
+		return err
+	}
+
+	document := &pdfDocument{}
+	catalog := document.reserve()
+	pages := document.reserve()
+	regular := document.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	bold := document.add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	resources := fmt.Sprintf("/Font << /F1 %d 0 R /F2 %d 0 R >>", regular, bold)
+
+	var logo *pdfImage
+	if len(p.Logo) > 0 {
+		if logo, err = newPDFImage(p.Logo); err != nil {
+			return err
+		}
+		resources += fmt.Sprintf(" /XObject << /Im1 %d 0 R >>", document.stream(logo.dictionary, logo.data, logo.encoded))
+	}
+
+	widths := pdfColumnWidths(table)
+	headerRows := 1
+	if table.Grouped {
+		headerRows = 2
+	}
+	tableTop := pdfMargin + pdfTitleHeight
+	bodyTop := tableTop + float64(headerRows)*pdfRowHeight
+	rowsPerPage := int((pdfPageHeight - pdfMargin - pdfFooterHeight - bodyTop) / pdfRowHeight)
+
+	// Split the rows into pages, keeping each Friday with its Jumu'ah row
+	var pageRows [][]printableRow
+	lines := rowsPerPage
+	for _, row := range table.Rows {
+		height := 1
+		if row.Jumuah != "" {
+			height = 2
+		}
+		if lines+height > rowsPerPage {
+			pageRows = append(pageRows, nil)
+			lines = 0
+		}
+		pageRows[len(pageRows)-1] = append(pageRows[len(pageRows)-1], row)
+		lines += height
+	}
+
+	var kids []string
+	for number, rows := range pageRows {
+		content := &pdfContent{}
+		if logo != nil {
+			width := logo.width * pdfLogoHeight / logo.height
+			content.printf("q %.2f 0 0 %.2f %.2f %.2f cm /Im1 Do Q\n", width, pdfLogoHeight, pdfMargin, pdfPageHeight-pdfMargin-pdfLogoHeight)
+		}
+		titleLeft := pdfMargin
+		if logo != nil {
+			titleLeft += logo.width*pdfLogoHeight/logo.height + 12
+		}
+		content.text(true, pdfTitleFontSize, titleLeft, pdfMargin+20, table.Title)
+		content.text(false, 12, titleLeft, pdfMargin+40, table.Subtitle)
+
+		// Header rows
+		content.fill(pdfHeaderColor, pdfMargin, tableTop, pdfPageWidth-2*pdfMargin, float64(headerRows)*pdfRowHeight)
+		content.printf("1 g\n")
+		if table.Grouped {
+			x, column := pdfMargin, 0
+			for _, group := range table.Groups() {
+				width := 0.0
+				for i := column; i < column+group.Span; i++ {
+					if group.Span > 1 {
+						content.centered(true, x+width, tableTop+pdfRowHeight, widths[i], table.Columns[i].Heading)
+					}
+					width += widths[i]
+				}
+				// Headings without iqamah columns are centered over both header rows
+				top := tableTop
+				if group.Span == 1 {
+					top += pdfRowHeight / 2
+				}
+				content.centered(true, x, top, width, group.Heading)
+				x += width
+				column += group.Span
+			}
+		} else {
+			x := pdfMargin
+			for i, column := range table.Columns {
+				content.centered(true, x, tableTop, widths[i], column.Heading)
+				x += widths[i]
+			}
+		}
+		content.printf("0 g\n")
+
+		// Body rows
+		y := bodyTop
+		for _, row := range rows {
+			switch {
+			case row.Today:
+				content.fill(pdfTodayColor, pdfMargin, y, pdfPageWidth-2*pdfMargin, pdfRowHeight)
+			case row.Friday:
+				content.fill(pdfFridayColor, pdfMargin, y, pdfPageWidth-2*pdfMargin, pdfRowHeight)
+			}
+			x := pdfMargin
+			for i, cell := range row.Cells {
+				content.centered(row.Today, x, y, widths[i], cell)
+				x += widths[i]
+			}
+			content.grid(widths, y, 1)
+			y += pdfRowHeight
+
+			if row.Jumuah != "" {
+				content.fill(pdfFridayColor, pdfMargin, y, pdfPageWidth-2*pdfMargin, pdfRowHeight)
+				content.text(false, pdfFontSize, pdfMargin+pdfCellPadding/2, y+pdfRowHeight-4, row.Jumuah)
+				content.grid(nil, y, 1)
+				y += pdfRowHeight
+			}
+		}
+		content.grid(nil, tableTop, float64(headerRows))
+
+		content.text(false, pdfFontSize, pdfMargin, pdfPageHeight-pdfMargin, fmt.Sprintf("Page %d of %d", number+1, len(pageRows)))
+
+		stream := document.stream("", content.Bytes(), false)
+		kids = append(kids, fmt.Sprintf("%d 0 R", document.add(fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>",
+			pages, pdfPageWidth, pdfPageHeight, resources, stream))))
+	}
+
+	document.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	document.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	info := document.add(fmt.Sprintf("<< /Title %s /Producer (prayer-schedule) >>", pdfString(table.Title+" - "+table.Subtitle)))
+	return document.write(w, catalog, info)
+}
+
+// pdfColumnWidths fits the widest text of each column to the page width
+func pdfColumnWidths(table *printableTable) []float64 {
+	widths := make([]float64, len(table.Columns))
+	for i, column := range table.Columns {
+		widths[i] = pdfTextWidth(true, pdfFontSize, column.Heading)
+		for _, row := range table.Rows {
+			if width := pdfTextWidth(true, pdfFontSize, row.Cells[i]); width > widths[i] {
+				widths[i] = width
+			}
+		}
+		widths[i] += pdfCellPadding
+	}
+	total := 0.0
+	for _, width := range widths {
+		total += width
+	}
+	for i := range widths {
+		widths[i] *= (pdfPageWidth - 2*pdfMargin) / total
+	}
+	return widths
+}
+
+// pdfTextWidth returns the width of text in points
+func pdfTextWidth(bold bool, size float64, text string) float64 {
+	metrics := &pdfHelveticaWidths
+	if bold {
+		metrics = &pdfHelveticaBoldWidths
+	}
+	width := 0
+	for _, c := range pdfEncode(text) {
+		if c >= 32 && c <= 126 {
+			width += metrics[c-32]
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// pdfEncode encodes text in WinAnsiEncoding, replacing characters it does not have with question marks
+func pdfEncode(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= 32 && r <= 126 || r >= 0xA0 && r <= 0xFF:
+			encoded = append(encoded, byte(r))
+		case pdfWinAnsi[r] != 0:
+			encoded = append(encoded, pdfWinAnsi[r])
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// pdfString returns text as a PDF literal string
+func pdfString(text string) string {
+	var escaped strings.Builder
+	escaped.WriteByte('(')
+	for _, c := range pdfEncode(text) {
+		if c == '(' || c == ')' || c == '\\' {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteByte(c)
+	}
+	escaped.WriteByte(')')
+	return escaped.String()
+}
+
+// pdfContent is a page content stream.  Its methods take y as the distance from the top of the page
+type pdfContent struct {
+	bytes.Buffer
+}
+
+func (c *pdfContent) printf(format string, args ...interface{}) {
+	fmt.Fprintf(c, format, args...)
+}
+
+// text writes text with its baseline at y
+func (c *pdfContent) text(bold bool, size float64, x float64, y float64, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	c.printf("BT /%s %g Tf %.2f %.2f Td %s Tj ET\n", font, size, x, pdfPageHeight-y, pdfString(text))
+}
+
+// centered writes text centered in the cell of a table row with its top at y
+func (c *pdfContent) centered(bold bool, x float64, y float64, width float64, text string) {
+	c.text(bold, pdfFontSize, x+(width-pdfTextWidth(bold, pdfFontSize, text))/2, y+pdfRowHeight-4, text)
+}
+
+// fill fills a rectangle with its top left corner at x, y
+func (c *pdfContent) fill(color [3]float64, x float64, y float64, width float64, height float64) {
+	c.printf("q %.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f Q\n", color[0], color[1], color[2], x, pdfPageHeight-y-height, width, height)
+}
+
+// grid strokes the border of rows table rows from y, with a line between the columns of widths
+func (c *pdfContent) grid(widths []float64, y float64, rows float64) {
+	c.printf("q %.3f %.3f %.3f RG 0.5 w\n", pdfBorderColor[0], pdfBorderColor[1], pdfBorderColor[2])
+	c.printf("%.2f %.2f %.2f %.2f re S\n", pdfMargin, pdfPageHeight-y-rows*pdfRowHeight, pdfPageWidth-2*pdfMargin, rows*pdfRowHeight)
+	x := pdfMargin
+	for i := 0; i < len(widths)-1; i++ {
+		x += widths[i]
+		c.printf("%.2f %.2f m %.2f %.2f l S\n", x, pdfPageHeight-y, x, pdfPageHeight-y-rows*pdfRowHeight)
+	}
+	c.printf("Q\n")
+}
+
+// pdfImage is an image XObject
+type pdfImage struct {
+	width      float64
+	height     float64
+	dictionary string
+	data       []byte
+	encoded    bool // Whether data is already compressed, as JPEG data is
+}
+
+// newPDFImage converts a PNG or JPEG image to an image XObject.  JPEG images are embedded as they are
+func newPDFImage(data []byte) (*pdfImage, error) {
+	var decoded image.Image
+	var err error
+	switch contentType := http.DetectContentType(data); contentType {
+	case "image/jpeg":
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read logo: %s", err)
+		}
+		colorSpace := ""
+		switch config.ColorModel {
+		case color.YCbCrModel, color.RGBAModel:
+			colorSpace = "/DeviceRGB"
+		case color.GrayModel:
+			colorSpace = "/DeviceGray"
+		}
+		if colorSpace != "" {
+			return &pdfImage{
+				width:  float64(config.Width),
+				height: float64(config.Height),
+				dictionary: fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode",
+					config.Width, config.Height, colorSpace),
+				data:    data,
+				encoded: true,
+			}, nil
+		}
+		// CMYK JPEG images are decoded, as their colors are stored inverted by some encoders
+		decoded, err = jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read logo: %s", err)
+		}
+	case "image/png":
+		if decoded, err = png.Decode(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("unable to read logo: %s", err)
+		}
+	default:
+		return nil, fmt.Errorf("logo is %s, not a PNG or JPEG image", contentType)
+	}
+
+	// Transparent pixels are blended with the white page
+	bounds := decoded.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := decoded.At(x, y).RGBA()
+			for _, channel := range []uint32{r, g, b} {
+				pixels = append(pixels, byte((channel+0xffff-a)>>8))
+			}
+		}
+	}
+	return &pdfImage{
+		width:      float64(bounds.Dx()),
+		height:     float64(bounds.Dy()),
+		dictionary: fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8", bounds.Dx(), bounds.Dy()),
+		data:       pixels,
+	}, nil
+}
+
+// pdfDocument collects the objects of a PDF document.  Object numbers start at 1
+type pdfDocument struct {
+	objects []string
+}
+
+// add adds an object and returns its number
+func (d *pdfDocument) add(object string) int {
+	d.objects = append(d.objects, object)
+	return len(d.objects)
+}
+
+// reserve returns the number of an object set later, for objects which refer to objects added after them
+func (d *pdfDocument) reserve() int {
+	return d.add("")
+}
+
+func (d *pdfDocument) set(number int, object string) {
+	d.objects[number-1] = object
+}
+
+// stream adds a stream object with the entries of dictionary, compressing data unless it is already encoded
+func (d *pdfDocument) stream(dictionary string, data []byte, encoded bool) int {
+	if !encoded {
+		var compressed bytes.Buffer
+		writer := zlib.NewWriter(&compressed)
+		writer.Write(data)
+		writer.Close()
+		data = compressed.Bytes()
+		dictionary = strings.TrimSpace(dictionary + " /Filter /FlateDecode")
+	}
+	return d.add(fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dictionary, len(data), data))
+}
+
+// write writes the document with its cross-reference table
+func (d *pdfDocument) write(w io.Writer, root int, info int) error {
+	var output bytes.Buffer
+	// The binary comment marks the file as binary for transfer programs
+	output.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(d.objects))
+	for i, object := range d.objects {
+		offsets[i] = output.Len()
+		fmt.Fprintf(&output, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := output.Len()
+	fmt.Fprintf(&output, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&output, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&output, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, root, info, xref)
+	_, err := output.WriteTo(w)
+	return err
+}
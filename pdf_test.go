@@ -0,0 +1,116 @@
+package schedule_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/jpeg"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// pdfContentStreams returns the inflated page content streams of a PDF document
+func pdfContentStreams(t *testing.T, document []byte) []string {
+	t.Helper()
+	var streams []string
+	for _, match := range regexp.MustCompile(`(?s)<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`).FindAllSubmatchIndex(document, -1) {
+		length, _ := strconv.Atoi(string(document[match[2]:match[3]]))
+		reader, err := zlib.NewReader(bytes.NewReader(document[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		streams = append(streams, string(content))
+	}
+	return streams
+}
+
+func TestPrintableTimetablePDF(t *testing.T) {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	customer := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    &testMonthProvider{location: location},
+	}
+	days, err := customer.PrayerCalendarRange(time.Date(2022, time.October, 1, 0, 0, 0, 0, location), time.Date(2022, time.December, 31, 0, 0, 0, 0, location), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logo bytes.Buffer
+	if err := jpeg.Encode(&logo, image.NewGray(image.Rect(0, 0, 8, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	err = (&psched.PrintableTimetable{
+		MosqueName: "Masjid (Al-Noor) – Los Angeles",
+		Logo:       logo.Bytes(),
+		Days:       days.Days,
+		Iqamah:     &psched.IqamahRules{Isha: &psched.IqamahRule{Type: psched.IqamahFixed, Time: "20:00"}},
+		Jumuah:     []string{"13:15"},
+		Today:      time.Date(2022, time.November, 15, 9, 0, 0, 0, location),
+	}).WritePDF(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := output.Bytes()
+
+	if !bytes.HasPrefix(document, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(document, []byte("%%EOF\n")) {
+		t.Fatal("document is not a PDF file")
+	}
+	if !bytes.Contains(document, []byte("/Filter /DCTDecode")) {
+		t.Error("JPEG logo is not embedded")
+	}
+
+	// Every cross-reference entry points at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(document)
+	if startxref == nil {
+		t.Fatal("document has no startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(document[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the cross-reference table", xref)
+	}
+	for number, entry := range regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(document[xref:], -1) {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if !bytes.HasPrefix(document[offset:], []byte(strconv.Itoa(number+1)+" 0 obj\n")) {
+			t.Errorf("object %d is not at offset %d", number+1, offset)
+		}
+	}
+
+	pages := pdfContentStreams(t, document)
+	if len(pages) < 2 || !bytes.Contains(document, []byte("/Count "+strconv.Itoa(len(pages))+" >>")) {
+		t.Fatalf("expected 92 days to be split over several pages, got %d", len(pages))
+	}
+	fridays, jumuah, today := 0, 0, 0
+	for i, page := range pages {
+		if !strings.Contains(page, "(Page "+strconv.Itoa(i+1)+" of "+strconv.Itoa(len(pages))+") Tj") {
+			t.Errorf("page %d has no page number", i+1)
+		}
+		if !strings.Contains(page, `(Masjid \(Al-Noor\) `+"\x96"+` Los Angeles) Tj`) || !strings.Contains(page, "(Iqamah) Tj") {
+			t.Errorf("page %d does not repeat the title and header", i+1)
+		}
+		fridays += strings.Count(page, "0.878 0.949 0.878 rg")
+		jumuah += strings.Count(page, "(Jumu'ah: 13:15) Tj")
+		today += strings.Count(page, "1.000 0.949 0.702 rg")
+		// A Friday is never the last row of a page without its Jumu'ah row
+		if strings.LastIndex(page, "(Fri) Tj") > strings.LastIndex(page, "(Jumu'ah: 13:15) Tj") {
+			t.Errorf("page %d splits a Friday from its jumu'ah row", i+1)
+		}
+	}
+	// October to December 2022 has 13 Fridays, each highlighted with its Jumu'ah row
+	if fridays != 26 || jumuah != 13 || today != 1 {
+		t.Errorf("expected 13 highlighted Fridays with jumu'ah rows and today, got %d fills, %d jumu'ah rows and %d today", fridays, jumuah, today)
+	}
+}
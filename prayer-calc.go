@@ -261,3 +261,23 @@ func FormatPrayerTime(prayerTime string) (string, string, error) {
 
     return prayerTimeHour, prayerTimeMinute, nil
 }
+
+// PrayerTimeOnDay returns prayerTime in the format of HH:MM (TIMEZONE) as a time on the date and location of day
+func PrayerTimeOnDay(day time.Time, prayerTime string) (time.Time, error) {
+	prayerHourStr, prayerMinuteStr, err := FormatPrayerTime(prayerTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	prayerHour, err := strconv.Atoi(strings.TrimSpace(prayerHourStr))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to convert hour from string to int: %s", err)
+	}
+
+	prayerMinute, err := strconv.Atoi(prayerMinuteStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to convert minute from string to int: %s", err)
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), prayerHour, prayerMinute, 0, 0, day.Location()), nil
+}
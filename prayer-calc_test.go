@@ -6,8 +6,11 @@ import (
     psched "github.com/moali87/prayer-schedule"
 )
 
+// prayerCalcTestDay fixes the day of the tests, so they do not depend on the date or timezone of the host
+var prayerCalcTestDay = time.Date(2022, time.October, 12, 0, 0, 0, 0, time.UTC)
+
 func TestDetermineSelectedPrayer(t *testing.T) {
-	timeLocation, err := time.LoadLocation("Local")
+	timeLocation, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		t.Errorf("unable to load time location: %s", err.Error())
 	}
@@ -16,7 +19,7 @@ func TestDetermineSelectedPrayer(t *testing.T) {
 	   Test with same hour but different minute
 	   current minute should be before prayer time minute, meaning the prayer has not started yet
 	*/
-	t1CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 13, 0, 0, 0, timeLocation)
+	t1CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 13, 0, 0, 0, timeLocation)
 	t1DeterminedTime, err := psched.DetermineSelectedPrayer(t1CurrentTime, "13:04 (EST)")
     if err != nil {
         t.Errorf("determined time with same hour but off before minute returned an incorrect result: %s", err)
@@ -29,7 +32,7 @@ func TestDetermineSelectedPrayer(t *testing.T) {
 	   Test with same hour but different minute
 	   current minute should be after prayer time minute, meaning the prayer has started
 	*/
-	t2CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 13, 5, 0, 0, timeLocation)
+	t2CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 13, 5, 0, 0, timeLocation)
 	t2DeterminedTime, err := psched.DetermineSelectedPrayer(t2CurrentTime, "13:04 (EST)")
     if err !=  nil {
         t.Errorf("determined time with same hour but off after minute returned an incorrect result: %s", err)
@@ -42,7 +45,7 @@ func TestDetermineSelectedPrayer(t *testing.T) {
 	   Test with same hour and minute
 	   prayer has started
 	*/
-	t3CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 13, 5, 0, 0, timeLocation)
+	t3CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 13, 5, 0, 0, timeLocation)
 	t3DeterminedTime, err := psched.DetermineSelectedPrayer(t3CurrentTime, "13:04 (EST)")
     if err != nil {
         t.Errorf("determined time with same hour and same minute returned an incorrect result")
@@ -55,7 +58,7 @@ func TestDetermineSelectedPrayer(t *testing.T) {
 	   Test with same different hour and same minute
 	   Hour should be before prayer time hour, meaning the prayer has not started yet
 	*/
-	t4CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 12, 5, 0, 0, timeLocation)
+	t4CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 12, 5, 0, 0, timeLocation)
 	t4DeterminedTime, err := psched.DetermineSelectedPrayer(t4CurrentTime, "13:04 (EST)")
     if err != nil {
         t.Errorf("determined time with different before hour and same minute returned an incorrect result")
@@ -68,7 +71,7 @@ func TestDetermineSelectedPrayer(t *testing.T) {
 	   Test with same different hour and same minute
 	   Hour should be after prayer time hour, meaning the prayer has started
 	*/
-	t5CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 14, 5, 0, 0, timeLocation)
+	t5CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 14, 5, 0, 0, timeLocation)
 	t5DeterminedTime, err := psched.DetermineSelectedPrayer(t5CurrentTime, "13:04 (EST)")
     if err != nil {
         t.Errorf("determined time with different after hour and same minute returned an incorrect result")
@@ -80,7 +83,7 @@ func TestDetermineSelectedPrayer(t *testing.T) {
 	/*
 	   Test both hour and minute is before prayer hour and minute
 	*/
-	t6CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 12, 0, 0, 0, timeLocation)
+	t6CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 12, 0, 0, 0, timeLocation)
 	t6DeterminedTime, err := psched.DetermineSelectedPrayer(t6CurrentTime, "13:04 (EST)")
     if err != nil {
         t.Errorf("determined time with different after hour and same minute returned an incorrect result")
@@ -92,7 +95,7 @@ func TestDetermineSelectedPrayer(t *testing.T) {
 	/*
 	   Test both hour and minute is after prayer hour and minute
 	*/
-	t7CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 17, 10, 0, 0, timeLocation)
+	t7CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 17, 10, 0, 0, timeLocation)
 	t7DeterminedTime, err := psched.DetermineSelectedPrayer(t7CurrentTime, "13:04 (EST)")
     if err != nil {
         t.Errorf("determined time with different after hour and same minute returned an incorrect result")
@@ -131,13 +134,13 @@ var nextDayPrayerStruct = &psched.FiveDailyPrayers{
 
 // TODO: Create test for determineWhichPrayer function
 func TestDetermineWhichPrayerIsha(t *testing.T) {
-	timeLocation, err := time.LoadLocation("Local")
+	timeLocation, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		t.Errorf("unable to load time location: %s", err.Error())
 	}
 
 	// Test if current prayer is previous day Isha
-	t1CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 4, 36, 0, 0, timeLocation)
+	t1CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 4, 36, 0, 0, timeLocation)
 	prevDayIshaStruct, err := psched.DetermineWhichPrayer(prevDayPrayerStruct, currDayPrayerStruct, nextDayPrayerStruct, &t1CurrentTime)
 	if err != nil {
 		t.Errorf("unable to determine current, previous, and next prayer time structure: %s", err.Error())
@@ -157,7 +160,7 @@ func TestDetermineWhichPrayerIsha(t *testing.T) {
 	}
 
 	// Test if current prayer is current day Isha
-	t2CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 21, 36, 0, 0, timeLocation)
+	t2CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 21, 36, 0, 0, timeLocation)
 	currDayIshaStruct, err := psched.DetermineWhichPrayer(prevDayPrayerStruct, currDayPrayerStruct, nextDayPrayerStruct, &t2CurrentTime)
 
 	//// Test if current prayer name is Isha
@@ -176,12 +179,12 @@ func TestDetermineWhichPrayerIsha(t *testing.T) {
 
 // Test if currnet prayer name is Fajr and next prayer name is Dhuhr and not sunrise
 func TestDetermineWhichPrayerFajr(t *testing.T) {
-	timeLocation, err := time.LoadLocation("Local")
+	timeLocation, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		t.Errorf("unable to load time location: %s", err.Error())
 	}
 
-	t1CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 4, 37, 0, 0, timeLocation)
+	t1CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 4, 37, 0, 0, timeLocation)
 	currDayFajrStruct, err := psched.DetermineWhichPrayer(prevDayPrayerStruct, currDayPrayerStruct, nextDayPrayerStruct, &t1CurrentTime)
 	if err != nil {
 		t.Errorf("unable to determine current, previous, and next prayer time structure: %s", err.Error())
@@ -199,12 +202,12 @@ func TestDetermineWhichPrayerFajr(t *testing.T) {
 
 // Test if current prayer name is Sunrise and next prayer name is Dhuhr
 func TestDetermineWhichPrayerSunrise(t *testing.T) {
-	timeLocation, err := time.LoadLocation("Local")
+	timeLocation, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		t.Errorf("unable to load time location: %s", err.Error())
 	}
 
-	t1CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 6, 37, 0, 0, timeLocation)
+	t1CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 6, 37, 0, 0, timeLocation)
 	currDayPrayerStruct, err := psched.DetermineWhichPrayer(prevDayPrayerStruct, currDayPrayerStruct, nextDayPrayerStruct, &t1CurrentTime)
 	if err != nil {
 		t.Errorf("unable to determine current, previous, and next prayer time structure: %s", err.Error())
@@ -222,12 +225,12 @@ func TestDetermineWhichPrayerSunrise(t *testing.T) {
 
 // Test if current prayer name is Dhuhr and next prayer name is Asr
 func TestDetermineWhichPrayerDhuhr(t *testing.T) {
-	timeLocation, err := time.LoadLocation("Local")
+	timeLocation, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		t.Errorf("unable to load time location: %s", err.Error())
 	}
 
-	t1CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 13, 37, 0, 0, timeLocation)
+	t1CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 13, 37, 0, 0, timeLocation)
 	currDayDhuhrStruct, err := psched.DetermineWhichPrayer(prevDayPrayerStruct, currDayPrayerStruct, nextDayPrayerStruct, &t1CurrentTime)
 	if err != nil {
 		t.Errorf("unable to determine current, previous, and next prayer time structure: %s", err.Error())
@@ -245,12 +248,12 @@ func TestDetermineWhichPrayerDhuhr(t *testing.T) {
 
 // Test if current prayer name is Asr and next prayer name is Maghrib
 func TestDetermineWhichPrayerAsr(t *testing.T) {
-	timeLocation, err := time.LoadLocation("Local")
+	timeLocation, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		t.Errorf("unable to load time location: %s", err.Error())
 	}
 
-	t1CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 16, 37, 0, 0, timeLocation)
+	t1CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 16, 37, 0, 0, timeLocation)
 	currDayPrayerStruct, err := psched.DetermineWhichPrayer(prevDayPrayerStruct, currDayPrayerStruct, nextDayPrayerStruct, &t1CurrentTime)
 	if err != nil {
 		t.Errorf("unable to determine current, previous, and next prayer time structure: %s", err.Error())
@@ -268,12 +271,12 @@ func TestDetermineWhichPrayerAsr(t *testing.T) {
 
 // Test if current prayer name is Asr and next prayer name is Maghrib
 func TestDetermineWhichPrayerMaghrib(t *testing.T) {
-	timeLocation, err := time.LoadLocation("Local")
+	timeLocation, err := time.LoadLocation("America/New_York")
 	if err != nil {
 		t.Errorf("unable to load time location: %s", err.Error())
 	}
 
-	t1CurrentTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 19, 37, 0, 0, timeLocation)
+	t1CurrentTime := time.Date(prayerCalcTestDay.Year(), prayerCalcTestDay.Month(), prayerCalcTestDay.Day(), 19, 37, 0, 0, timeLocation)
 	currDayPrayerStruct, err := psched.DetermineWhichPrayer(prevDayPrayerStruct, currDayPrayerStruct, nextDayPrayerStruct, &t1CurrentTime)
 	if err != nil {
 		t.Errorf("unable to determine current, previous, and next prayer time structure: %s", err.Error())
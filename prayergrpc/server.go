@@ -0,0 +1,341 @@
+// Package prayergrpc serves the PrayerSchedule gRPC service of the prayerpb package
+package prayergrpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+	"github.com/moali87/prayer-schedule/prayerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// defaultMethod is the calculation method used when a request has none
+	defaultMethod = 2
+	// maxCalendarDays is the longest date range served by GetCalendar
+	maxCalendarDays = 366
+)
+
+// watchedPrayers are the prayers WatchPrayers sends events for
+var watchedPrayers = []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+/*
+Server implements prayerpb.PrayerScheduleServer.  Requests are turned into a psched.CustomerLocationInput with the
+Provider and Geocoder of the server, so timings are looked up exactly as PrayerCalendar looks them up.
+*/
+type Server struct {
+	prayerpb.UnimplementedPrayerScheduleServer
+
+	Provider   psched.MonthProvider // Source of the monthly prayer timings.  Defaults to AladhanProvider
+	Geocoder   psched.Geocoder      // Looks up postal code locations.  Defaults to HERE with HEREAPIKey
+	HEREAPIKey string               // Enables postal code locations without a Geocoder
+	Clock      psched.Clock         // Defaults to psched.SystemClock
+}
+
+// GetCalendar returns the timings of every day from start to end inclusive
+func (s *Server) GetCalendar(ctx context.Context, request *prayerpb.GetCalendarRequest) (*prayerpb.GetCalendarResponse, error) {
+	customer, err := s.customer(request.GetLocation(), request.GetSettings())
+	if err != nil {
+		return nil, err
+	}
+	if request.GetStart() == nil || request.GetEnd() == nil {
+		return nil, status.Error(codes.InvalidArgument, "start and end are required")
+	}
+	start, err := date(request.GetStart())
+	if err != nil {
+		return nil, err
+	}
+	end, err := date(request.GetEnd())
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) || end.Sub(start) >= maxCalendarDays*24*time.Hour {
+		return nil, status.Errorf(codes.InvalidArgument, "range must be from 1 to %d days", maxCalendarDays)
+	}
+
+	calendar, err := customer.PrayerCalendarRangeContext(ctx, start, end, 0)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	response := &prayerpb.GetCalendarResponse{
+		Coordinates: &prayerpb.Coordinates{Latitude: calendar.Latitude, Longitude: calendar.Longitude},
+	}
+	for i := range calendar.Days {
+		day, err := dayMessage(&calendar.Days[i])
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.Days = append(response.Days, day)
+	}
+	return response, nil
+}
+
+// GetDay returns the timings of the requested day, or of today at the location
+func (s *Server) GetDay(ctx context.Context, request *prayerpb.GetDayRequest) (*prayerpb.Day, error) {
+	customer, err := s.customer(request.GetLocation(), request.GetSettings())
+	if err != nil {
+		return nil, err
+	}
+
+	var day *psched.PCalDay
+	if request.GetDate() == nil {
+		if day, err = customer.PrayerDayContext(ctx, s.clock().Now()); err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	} else {
+		requested, err := date(request.GetDate())
+		if err != nil {
+			return nil, err
+		}
+		calendar, err := customer.PrayerCalendarRangeContext(ctx, requested, requested, 1)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		if len(calendar.Days) != 1 {
+			return nil, status.Errorf(codes.NotFound, "no prayer timings were found for %s", requested.Format("2006-01-02"))
+		}
+		day = &calendar.Days[0]
+	}
+
+	message, err := dayMessage(day)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return message, nil
+}
+
+// GetPrayerNow returns the current and next prayer
+func (s *Server) GetPrayerNow(ctx context.Context, request *prayerpb.GetPrayerNowRequest) (*prayerpb.PrayerNow, error) {
+	customer, err := s.customer(request.GetLocation(), request.GetSettings())
+	if err != nil {
+		return nil, err
+	}
+	now, _, err := prayerNow(ctx, customer, s.clock().Now())
+	if err != nil {
+		return nil, err
+	}
+	return now, nil
+}
+
+/*
+WatchPrayers sends an event each time one of the five daily prayers begins at the location until the call is
+cancelled.  Sunrise is not a prayer, so no event is sent when it begins, nor as the current prayer between sunrise
+and Dhuhr.  After each prayer begins, the next prayer is determined again from the timings of that instant, so the
+watch carries on across days and months.
+*/
+func (s *Server) WatchPrayers(request *prayerpb.WatchPrayersRequest, stream prayerpb.PrayerSchedule_WatchPrayersServer) error {
+	customer, err := s.customer(request.GetLocation(), request.GetSettings())
+	if err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	now, instant, err := prayerNow(ctx, customer, s.clock().Now())
+	if err != nil {
+		return err
+	}
+	if request.GetSendCurrent() && isPrayer(now.CurrentPrayer) {
+		event := &prayerpb.PrayerEvent{Prayer: now.CurrentPrayer, Time: now.CurrentPrayerTime, Timezone: instant.Location().String(), Current: true}
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		begins := instant.Add(now.TimeUntilNext.AsDuration())
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.clock().After(begins.Sub(s.clock().Now())):
+		}
+
+		// The next prayer is determined at the instant it begins, so a slow timer cannot skip a prayer
+		if now, instant, err = prayerNow(ctx, customer, begins); err != nil {
+			return err
+		}
+		if isPrayer(now.CurrentPrayer) {
+			event := &prayerpb.PrayerEvent{Prayer: now.CurrentPrayer, Time: timestamppb.New(begins), Timezone: instant.Location().String()}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+		if now.TimeUntilNext.AsDuration() <= 0 {
+			return status.Errorf(codes.Internal, "next prayer after %s is not in the future", now.CurrentPrayer)
+		}
+	}
+}
+
+// isPrayer returns whether name is one of the five daily prayers rather than Sunrise
+func isPrayer(name string) bool {
+	for _, prayer := range watchedPrayers {
+		if name == prayer {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) clock() psched.Clock {
+	if s.Clock != nil {
+		return s.Clock
+	}
+	return psched.SystemClock
+}
+
+/*
+prayerNow returns the current and next prayer at instant, and instant in the timezone of the location.  The location
+and timings are looked up once, and given up when ctx is done.
+*/
+func prayerNow(ctx context.Context, customer *psched.CustomerLocationInput, instant time.Time) (*prayerpb.PrayerNow, time.Time, error) {
+	moment, err := customer.PrayerAt(ctx, instant)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, time.Time{}, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, time.Time{}, status.Error(codes.Unavailable, err.Error())
+	}
+	determined, localNow := moment.Prayer, moment.Instant
+
+	currentDay := localNow
+	if determined.PreviousDayIsha {
+		currentDay = currentDay.AddDate(0, 0, -1)
+	}
+	currentTime, err := psched.PrayerTimeOnDay(currentDay, determined.CurrentPrayerTime)
+	if err != nil {
+		return nil, time.Time{}, status.Error(codes.Internal, err.Error())
+	}
+
+	return &prayerpb.PrayerNow{
+		CurrentPrayer:     determined.CurrentPrayerName,
+		CurrentPrayerTime: timestamppb.New(currentTime),
+		PreviousDayIsha:   determined.PreviousDayIsha,
+		NextPrayer:        determined.NextPrayerName,
+		NextPrayerTime:    timestamppb.New(localNow.Add(determined.TimeDiff)),
+		TimeUntilNext:     durationpb.New(determined.TimeDiff),
+	}, localNow, nil
+}
+
+// customer returns the customer location of a request, with the provider and geocoder of the server
+func (s *Server) customer(location *prayerpb.Location, settings *prayerpb.CalculationSettings) (*psched.CustomerLocationInput, error) {
+	customer := &psched.CustomerLocationInput{Provider: s.Provider, Institution: defaultMethod}
+
+	switch {
+	case location.GetCoordinates() != nil:
+		latitude, longitude := location.GetCoordinates().GetLatitude(), location.GetCoordinates().GetLongitude()
+		if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			return nil, status.Error(codes.InvalidArgument, "latitude and longitude must be within -90 to 90 and -180 to 180")
+		}
+		if latitude == 0 || longitude == 0 {
+			return nil, status.Error(codes.InvalidArgument, "latitude and longitude on the equator or the prime meridian are not supported")
+		}
+		customer.Coordinates = psched.PrayerCalendarInputCoordinates{Latitude: latitude, Longitude: longitude}
+	case location.GetPostalCode() != nil:
+		if location.GetPostalCode().GetCountryCode() == "" || location.GetPostalCode().GetPostalCode() == "" {
+			return nil, status.Error(codes.InvalidArgument, "country_code and postal_code are both required")
+		}
+		if s.Geocoder == nil && s.HEREAPIKey == "" {
+			return nil, status.Error(codes.InvalidArgument, "postal code locations are not enabled on this server")
+		}
+		customer.CountryCode = location.GetPostalCode().GetCountryCode()
+		customer.PostalCode = location.GetPostalCode().GetPostalCode()
+		customer.Geocoder = s.Geocoder
+		customer.HEREAPIKey = s.HEREAPIKey
+	default:
+		return nil, status.Error(codes.InvalidArgument, "a location is required: coordinates or postal_code")
+	}
+
+	if settings != nil && settings.Method != nil {
+		customer.Institution = int(settings.GetMethod())
+	}
+	if _, ok := psched.CalculationMethods[customer.Institution]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "method %d is unknown", customer.Institution)
+	}
+	customer.School = int(settings.GetSchool())
+	if customer.School != 0 && customer.School != 1 {
+		return nil, status.Error(codes.InvalidArgument, "school must be 0 or 1")
+	}
+	customer.LatitudeAdjustmentMethod = int(settings.GetLatitudeAdjustmentMethod())
+	if customer.LatitudeAdjustmentMethod < 0 || customer.LatitudeAdjustmentMethod > psched.LatitudeAdjustmentAngleBased {
+		return nil, status.Error(codes.InvalidArgument, "latitude_adjustment_method must be from 0 to 3")
+	}
+	offsets := settings.GetOffsets()
+	customer.Offsets = psched.PrayerOffsets{
+		Imsak:   int(offsets.GetImsak()),
+		Fajr:    int(offsets.GetFajr()),
+		Sunrise: int(offsets.GetSunrise()),
+		Dhuhr:   int(offsets.GetDhuhr()),
+		Asr:     int(offsets.GetAsr()),
+		Maghrib: int(offsets.GetMaghrib()),
+		Isha:    int(offsets.GetIsha()),
+	}
+
+	switch settings.GetHijriCalendar() {
+	case prayerpb.HijriCalendar_HIJRI_CALENDAR_UMM_AL_QURA:
+		customer.HijriCalendar = psched.UmmAlQura
+	case prayerpb.HijriCalendar_HIJRI_CALENDAR_TABULAR:
+		customer.HijriCalendar = psched.TabularHijri
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "hijri_calendar %d is unknown", settings.GetHijriCalendar())
+	}
+	customer.HijriAdjustment = int(settings.GetHijriAdjustment())
+	if customer.HijriAdjustment < -2 || customer.HijriAdjustment > 2 {
+		return nil, status.Error(codes.InvalidArgument, "hijri_adjustment must be from -2 to 2")
+	}
+	return customer, nil
+}
+
+// date returns midnight UTC of a Date message
+func date(message *prayerpb.Date) (time.Time, error) {
+	day := time.Date(int(message.GetYear()), time.Month(message.GetMonth()), int(message.GetDay()), 0, 0, 0, 0, time.UTC)
+	if day.Year() != int(message.GetYear()) || int32(day.Month()) != message.GetMonth() || int32(day.Day()) != message.GetDay() {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "%04d-%02d-%02d is not a date", message.GetYear(), message.GetMonth(), message.GetDay())
+	}
+	return day, nil
+}
+
+// dayMessage converts a day of timings to a Day message
+func dayMessage(day *psched.PCalDay) (*prayerpb.Day, error) {
+	date, err := day.Day()
+	if err != nil {
+		return nil, err
+	}
+	message := &prayerpb.Day{
+		Date:     &prayerpb.Date{Year: int32(date.Year()), Month: int32(date.Month()), Day: int32(date.Day())},
+		Timezone: date.Location().String(),
+		Timings:  &prayerpb.Timings{},
+	}
+	if !day.Hijri.IsZero() {
+		message.Hijri = &prayerpb.HijriDate{Year: int32(day.Hijri.Year), Month: int32(day.Hijri.Month), Day: int32(day.Hijri.Day), MonthName: day.Hijri.MonthName()}
+	}
+	for _, event := range day.Events {
+		message.Events = append(message.Events, event.Name)
+	}
+
+	timings := []struct {
+		timing string
+		field  **timestamppb.Timestamp
+	}{
+		{day.Timings.Imsak, &message.Timings.Imsak},
+		{day.Timings.Fajr, &message.Timings.Fajr},
+		{day.Timings.Sunrise, &message.Timings.Sunrise},
+		{day.Timings.Dhuhr, &message.Timings.Dhuhr},
+		{day.Timings.Asr, &message.Timings.Asr},
+		{day.Timings.Maghrib, &message.Timings.Maghrib},
+		{day.Timings.Isha, &message.Timings.Isha},
+	}
+	for _, timing := range timings {
+		if timing.timing == "" {
+			continue
+		}
+		prayerTime, err := psched.PrayerTimeOnDay(date, timing.timing)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read timings of %s: %s", date.Format("2006-01-02"), err)
+		}
+		*timing.field = timestamppb.New(prayerTime)
+	}
+	return message, nil
+}
@@ -0,0 +1,316 @@
+package prayergrpc_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+	"github.com/moali87/prayer-schedule/prayergrpc"
+	"github.com/moali87/prayer-schedule/prayerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testMonthProvider returns Dhuhr at 12:30 and Isha at 19:30 every day of the month in Los Angeles
+type testMonthProvider struct {
+	location *time.Location
+}
+
+func (p *testMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	first := time.Date(input.CustTime.Year(), input.CustTime.Month(), 1, 0, 0, 0, 0, p.location)
+	output := &psched.PCalOutput{Code: 200, Status: "OK", Latitude: input.Latitude, Longitude: input.Longitude}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		at := func(hour int, minute int) string {
+			return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, p.location).Format("15:04 (MST)")
+		}
+		pcalDay := psched.PCalDay{
+			Timings: psched.FiveDailyPrayers{
+				Imsak:   at(5, 0),
+				Fajr:    at(5, 10),
+				Sunrise: at(6, 30),
+				Dhuhr:   at(12, 30),
+				Asr:     at(15, 45),
+				Maghrib: at(18, 15),
+				Isha:    at(19, 30),
+			},
+		}
+		pcalDay.Date.Gregorian.Date = day.Format("02-01-2006")
+		pcalDay.Meta.Timezone = p.location.String()
+		output.Data = append(output.Data, pcalDay)
+	}
+	return output, nil
+}
+
+// contextMonthProvider records the context of each lookup
+type contextMonthProvider struct {
+	testMonthProvider
+	mu       sync.Mutex
+	contexts []context.Context
+}
+
+func (p *contextMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.mu.Lock()
+	p.contexts = append(p.contexts, ctx)
+	p.mu.Unlock()
+	return p.testMonthProvider.MonthlyPrayers(ctx, input)
+}
+
+// testClient serves server over an in-memory connection
+func testClient(t *testing.T, server *prayergrpc.Server) (prayerpb.PrayerScheduleClient, *time.Location) {
+	t.Helper()
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Provider == nil {
+		server.Provider = &testMonthProvider{location: location}
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	prayerpb.RegisterPrayerScheduleServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return prayerpb.NewPrayerScheduleClient(conn), location
+}
+
+var testLocation = &prayerpb.Location{
+	Location: &prayerpb.Location_Coordinates{Coordinates: &prayerpb.Coordinates{Latitude: 34.103, Longitude: -118.4105}},
+}
+
+func TestGetCalendar(t *testing.T) {
+	client, location := testClient(t, &prayergrpc.Server{})
+	response, err := client.GetCalendar(context.Background(), &prayerpb.GetCalendarRequest{
+		Location: testLocation,
+		Start:    &prayerpb.Date{Year: 2022, Month: 10, Day: 30},
+		End:      &prayerpb.Date{Year: 2022, Month: 11, Day: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Days) != 4 {
+		t.Fatalf("calendar has %d days, want 4", len(response.Days))
+	}
+	last := response.Days[3]
+	if last.Date.Month != 11 || last.Date.Day != 2 || last.Timezone != "America/Los_Angeles" {
+		t.Errorf("last day is %v in %s", last.Date, last.Timezone)
+	}
+	if dhuhr := last.Timings.Dhuhr.AsTime(); !dhuhr.Equal(time.Date(2022, time.November, 2, 12, 30, 0, 0, location)) {
+		t.Errorf("Dhuhr is %s", dhuhr)
+	}
+	if last.Hijri == nil || last.Hijri.Year != 1444 || last.Hijri.MonthName == "" {
+		t.Errorf("Hijri date is %v", last.Hijri)
+	}
+}
+
+func TestGetDay(t *testing.T) {
+	client, location := testClient(t, &prayergrpc.Server{
+		// 20:00 on the 12th of October in Los Angeles
+		Clock: psched.NewFakeClock(time.Date(2022, time.October, 13, 3, 0, 0, 0, time.UTC)),
+	})
+	day, err := client.GetDay(context.Background(), &prayerpb.GetDayRequest{Location: testLocation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if day.Date.Day != 12 {
+		t.Errorf("today is the %dth, want the 12th", day.Date.Day)
+	}
+
+	day, err = client.GetDay(context.Background(), &prayerpb.GetDayRequest{Location: testLocation, Date: &prayerpb.Date{Year: 2022, Month: 12, Day: 25}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isha := day.Timings.Isha.AsTime(); !isha.Equal(time.Date(2022, time.December, 25, 19, 30, 0, 0, location)) {
+		t.Errorf("Isha is %s", isha)
+	}
+}
+
+func TestGetPrayerNow(t *testing.T) {
+	client, location := testClient(t, &prayergrpc.Server{
+		// 02:00 on the 13th of October in Los Angeles
+		Clock: psched.NewFakeClock(time.Date(2022, time.October, 13, 9, 0, 0, 0, time.UTC)),
+	})
+	now, err := client.GetPrayerNow(context.Background(), &prayerpb.GetPrayerNowRequest{Location: testLocation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if now.CurrentPrayer != "Isha" || !now.PreviousDayIsha || now.NextPrayer != "Fajr" {
+		t.Errorf("prayer now is %v", now)
+	}
+	if current := now.CurrentPrayerTime.AsTime(); !current.Equal(time.Date(2022, time.October, 12, 19, 30, 0, 0, location)) {
+		t.Errorf("current prayer began at %s", current)
+	}
+	if next := now.NextPrayerTime.AsTime(); !next.Equal(time.Date(2022, time.October, 13, 5, 10, 0, 0, location)) {
+		t.Errorf("next prayer begins at %s", next)
+	}
+	if until := now.TimeUntilNext.AsDuration(); until != 3*time.Hour+10*time.Minute {
+		t.Errorf("time until next prayer is %s", until)
+	}
+}
+
+func TestGetPrayerNowLookup(t *testing.T) {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &contextMonthProvider{testMonthProvider: testMonthProvider{location: location}}
+	client, _ := testClient(t, &prayergrpc.Server{
+		Provider: provider,
+		// 20:00 on the 12th of October in Los Angeles
+		Clock: psched.NewFakeClock(time.Date(2022, time.October, 13, 3, 0, 0, 0, time.UTC)),
+	})
+	if _, err := client.GetPrayerNow(context.Background(), &prayerpb.GetPrayerNowRequest{Location: testLocation}); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.contexts) != 1 {
+		t.Fatalf("made %d month lookups, want 1", len(provider.contexts))
+	}
+	// The lookup was made with the context of the call, which ends with it
+	if provider.contexts[0].Err() == nil {
+		t.Error("month was looked up with a context which outlived the call")
+	}
+}
+
+func TestCalendarLookupContext(t *testing.T) {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &contextMonthProvider{testMonthProvider: testMonthProvider{location: location}}
+	client, _ := testClient(t, &prayergrpc.Server{
+		Provider: provider,
+		// 20:00 on the 12th of October in Los Angeles
+		Clock: psched.NewFakeClock(time.Date(2022, time.October, 13, 3, 0, 0, 0, time.UTC)),
+	})
+	day := &prayerpb.Date{Year: 2022, Month: 10, Day: 12}
+	if _, err := client.GetCalendar(context.Background(), &prayerpb.GetCalendarRequest{Location: testLocation, Start: day, End: day}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetDay(context.Background(), &prayerpb.GetDayRequest{Location: testLocation, Date: day}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetDay(context.Background(), &prayerpb.GetDayRequest{Location: testLocation}); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.contexts) != 3 {
+		t.Fatalf("made %d month lookups, want 3", len(provider.contexts))
+	}
+	// Each lookup was made with the context of its call, so client deadlines and cancellation reach the provider
+	for i, ctx := range provider.contexts {
+		if _, ok := grpc.Method(ctx); !ok {
+			t.Errorf("lookup %d was not made with the context of the call", i+1)
+		}
+	}
+}
+
+func TestWatchPrayers(t *testing.T) {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 6, 29, 59, 800000000, location))
+	client, _ := testClient(t, &prayergrpc.Server{Clock: clock})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchPrayers(ctx, &prayerpb.WatchPrayersRequest{Location: testLocation, SendCurrent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Prayer != "Fajr" || !current.Current {
+		t.Errorf("first event is %v, want the current Fajr", current)
+	}
+	// Sunrise begins without an event
+	clock.BlockUntil(1)
+	clock.Advance(200 * time.Millisecond)
+	clock.BlockUntil(1)
+	clock.Advance(6 * time.Hour)
+	begun, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if begun.Prayer != "Dhuhr" || begun.Current || begun.Timezone != "America/Los_Angeles" {
+		t.Errorf("second event is %v, want Dhuhr beginning", begun)
+	}
+	if at := begun.Time.AsTime(); !at.Equal(time.Date(2022, time.October, 12, 12, 30, 0, 0, location)) {
+		t.Errorf("Dhuhr began at %s", at)
+	}
+	clock.BlockUntil(1)
+	clock.Advance(3*time.Hour + 15*time.Minute)
+	if asr, err := stream.Recv(); err != nil || asr.Prayer != "Asr" {
+		t.Errorf("third event is %v, %v, want Asr beginning", asr, err)
+	}
+
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("watch ended with %v after cancelling, want Canceled", err)
+	}
+}
+
+func TestWatchPrayersNoCurrentSunrise(t *testing.T) {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 29, 59, 800000000, location))
+	client, _ := testClient(t, &prayergrpc.Server{Clock: clock})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchPrayers(ctx, &prayerpb.WatchPrayersRequest{Location: testLocation, SendCurrent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No prayer is current between sunrise and Dhuhr, so the first event is Dhuhr beginning
+	clock.BlockUntil(1)
+	clock.Advance(200 * time.Millisecond)
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Prayer != "Dhuhr" || first.Current {
+		t.Errorf("first event is %v, want Dhuhr beginning", first)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	client, _ := testClient(t, &prayergrpc.Server{})
+	method := int32(99)
+	requests := map[string]*prayerpb.GetDayRequest{
+		"no location":     {},
+		"unknown method":  {Location: testLocation, Settings: &prayerpb.CalculationSettings{Method: &method}},
+		"invalid date":    {Location: testLocation, Date: &prayerpb.Date{Year: 2022, Month: 2, Day: 30}},
+		"postal disabled": {Location: &prayerpb.Location{Location: &prayerpb.Location_PostalCode{PostalCode: &prayerpb.PostalCode{CountryCode: "USA", PostalCode: "90069"}}}},
+	}
+	for name, request := range requests {
+		if _, err := client.GetDay(context.Background(), request); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s returned %v, want InvalidArgument", name, err)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+// Package prayermqtt publishes the prayer timings of a location to MQTT, with Home Assistant discovery
+package prayermqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// Defaults of a Publisher
+const (
+	DefaultTopicPrefix       = "prayer-schedule"
+	DefaultDiscoveryPrefix   = "homeassistant"
+	DefaultNodeID            = "home"
+	DefaultName              = "Prayer Times"
+	DefaultCountdownInterval = time.Minute
+	DefaultPublishTimeout    = 10 * time.Second
+)
+
+// Payloads of the status topic, the availability topic of every Home Assistant entity
+const (
+	StatusOnline  = "online"
+	StatusOffline = "offline"
+)
+
+// timingPrayers are the timings published for today, each to a topic of its lowercase name
+var timingPrayers = []string{"Imsak", "Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// eventPrayers are the prayers which an event is published for when they begin
+var eventPrayers = []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+/*
+Publisher publishes the prayer timings of a location to retained MQTT topics under TopicPrefix/NodeID:
+
+	imsak, fajr, sunrise, dhuhr, asr, maghrib, isha  Today's timings as RFC 3339 times
+	current_prayer, next_prayer                      Names of the current and next prayer
+	next_prayer_time                                 When the next prayer begins, as an RFC 3339 time
+	countdown                                        Minutes until the next prayer begins, rounded up
+	status                                           online while Run runs, and offline after
+
+Each time a prayer begins, a JSON message which is not retained is published to the event topic.  Home Assistant
+discovery configs are published under DiscoveryPrefix, so each topic is a sensor of one device and the event topic
+is an event entity.
+*/
+type Publisher struct {
+	Client            mqtt.Client                   // Connected client.  SetWill makes the status offline when it disconnects
+	Customer          *psched.CustomerLocationInput // Location, calculation settings and provider of the timings
+	Clock             psched.Clock                  // Defaults to psched.SystemClock
+	TopicPrefix       string                        // Defaults to DefaultTopicPrefix
+	DiscoveryPrefix   string                        // Defaults to DefaultDiscoveryPrefix
+	NodeID            string                        // Identifies the location in topics and unique IDs.  Defaults to DefaultNodeID
+	Name              string                        // Name of the Home Assistant device.  Defaults to DefaultName
+	QoS               byte                          // QoS of every message.  Defaults to 0
+	CountdownInterval time.Duration                 // How often the countdown is published.  Defaults to DefaultCountdownInterval
+	OnError           func(err error)               // Called when the state cannot be published, which is retried after CountdownInterval
+}
+
+// PrayerEvent is the payload of the event topic
+type PrayerEvent struct {
+	EventType string    `json:"event_type"` // Lowercase prayer name, the event type of the Home Assistant event entity
+	Prayer    string    `json:"prayer"`
+	Time      time.Time `json:"time"`
+	Timezone  string    `json:"timezone"`
+}
+
+// discoveryConfig is a Home Assistant MQTT discovery config
+type discoveryConfig struct {
+	Name              string          `json:"name"`
+	UniqueID          string          `json:"unique_id"`
+	ObjectID          string          `json:"object_id"`
+	StateTopic        string          `json:"state_topic"`
+	AvailabilityTopic string          `json:"availability_topic"`
+	DeviceClass       string          `json:"device_class,omitempty"`
+	UnitOfMeasurement string          `json:"unit_of_measurement,omitempty"`
+	Icon              string          `json:"icon,omitempty"`
+	EventTypes        []string        `json:"event_types,omitempty"`
+	Device            discoveryDevice `json:"device"`
+}
+
+// discoveryDevice is the device every entity of a Publisher belongs to
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+// Topic returns the topic of object, such as "fajr" or "countdown"
+func (p *Publisher) Topic(object string) string {
+	prefix, nodeID := p.TopicPrefix, p.NodeID
+	if prefix == "" {
+		prefix = DefaultTopicPrefix
+	}
+	if nodeID == "" {
+		nodeID = DefaultNodeID
+	}
+	return prefix + "/" + nodeID + "/" + object
+}
+
+// SetWill sets the status to offline, retained, when the client of options disconnects without Run ending
+func (p *Publisher) SetWill(options *mqtt.ClientOptions) {
+	options.SetWill(p.Topic("status"), StatusOffline, p.QoS, true)
+}
+
+/*
+Run publishes the discovery configs and the state of the location, and then keeps the state up to date until ctx is
+done.  The status is set to offline before Run returns the error of ctx, and errors of the discovery configs are
+returned at once.  Months of timings and the coordinates of a postal code are kept in memory while Run runs, so the
+provider of the customer is looked up about once a month, and its geocoder once.
+*/
+func (p *Publisher) Run(ctx context.Context) error {
+	if p.Client == nil || p.Customer == nil {
+		return fmt.Errorf("mqtt client and customer location are required")
+	}
+	clock := p.Clock
+	if clock == nil {
+		clock = psched.SystemClock
+	}
+	customer := *p.Customer
+	if _, cached := customer.Provider.(*psched.CachedProvider); !cached {
+		provider := customer.Provider
+		if provider == nil {
+			provider = &psched.AladhanProvider{}
+		}
+		cache, err := psched.NewCachedProvider(provider, 4, 0)
+		if err != nil {
+			return err
+		}
+		cache.Clock = clock
+		customer.Provider = cache
+	}
+	if customer.PostalCode != "" {
+		if _, cached := customer.Geocoder.(*psched.CachedGeocoder); !cached {
+			geocoder := customer.Geocoder
+			if geocoder == nil {
+				geocoder = &psched.HEREGeocoder{APIKey: customer.HEREAPIKey}
+			}
+			cache, err := psched.NewCachedGeocoder(geocoder, 1, 0)
+			if err != nil {
+				return err
+			}
+			customer.Geocoder = cache
+		}
+	}
+
+	if err := p.publishDiscovery(); err != nil {
+		return err
+	}
+	if err := p.publish("status", StatusOnline, true); err != nil {
+		return err
+	}
+
+	interval := p.CountdownInterval
+	if interval <= 0 {
+		interval = DefaultCountdownInterval
+	}
+	var current, next string
+	var begins time.Time
+	for {
+		determined, localNow, err := p.publishState(ctx, &customer, clock.Now())
+		if err != nil {
+			if p.OnError != nil {
+				p.OnError(err)
+			}
+			select {
+			case <-ctx.Done():
+				p.publish("status", StatusOffline, true)
+				return ctx.Err()
+			case <-clock.After(interval):
+			}
+			continue
+		}
+		if current != "" && determined.CurrentPrayerName != current && containsPrayer(eventPrayers, determined.CurrentPrayerName) {
+			// The event is of when the prayer began, unless the clock jumped past the prayer which was next
+			began := localNow
+			if determined.CurrentPrayerName == next {
+				began = begins.In(localNow.Location())
+			}
+			if err := p.publishEvent(determined.CurrentPrayerName, began); err != nil && p.OnError != nil {
+				p.OnError(err)
+			}
+		}
+		current, next = determined.CurrentPrayerName, determined.NextPrayerName
+		begins = localNow.Add(determined.TimeDiff)
+
+		wait := begins.Sub(clock.Now())
+		if wait > interval {
+			wait = interval
+		}
+		select {
+		case <-ctx.Done():
+			p.publish("status", StatusOffline, true)
+			return ctx.Err()
+		case <-clock.After(wait):
+		}
+	}
+}
+
+/*
+publishState publishes today's timings and the current and next prayer at now.  It returns the prayer now, and now in
+the timezone of the location
+*/
+func (p *Publisher) publishState(ctx context.Context, customer *psched.CustomerLocationInput, now time.Time) (*psched.DeterminedPrayerOutput, time.Time, error) {
+	moment, err := customer.PrayerAt(ctx, now)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	today, determined, localNow := moment.Day, moment.Prayer, moment.Instant
+	date, err := today.Day()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	timings := []struct {
+		prayer string
+		timing string
+	}{
+		{"Imsak", today.Timings.Imsak},
+		{"Fajr", today.Timings.Fajr},
+		{"Sunrise", today.Timings.Sunrise},
+		{"Dhuhr", today.Timings.Dhuhr},
+		{"Asr", today.Timings.Asr},
+		{"Maghrib", today.Timings.Maghrib},
+		{"Isha", today.Timings.Isha},
+	}
+	for _, timing := range timings {
+		if timing.timing == "" {
+			continue
+		}
+		prayerTime, err := psched.PrayerTimeOnDay(date, timing.timing)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if err := p.publish(strings.ToLower(timing.prayer), prayerTime.Format(time.RFC3339), true); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+
+	countdown := int((determined.TimeDiff + time.Minute - 1) / time.Minute)
+	state := []struct {
+		object  string
+		payload string
+	}{
+		{"current_prayer", determined.CurrentPrayerName},
+		{"next_prayer", determined.NextPrayerName},
+		{"next_prayer_time", localNow.Add(determined.TimeDiff).Format(time.RFC3339)},
+		{"countdown", strconv.Itoa(countdown)},
+	}
+	for _, value := range state {
+		if err := p.publish(value.object, value.payload, true); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	return determined, localNow, nil
+}
+
+// publishEvent publishes that prayer began at instant, which is in the timezone of the location
+func (p *Publisher) publishEvent(prayer string, instant time.Time) error {
+	payload, err := json.Marshal(PrayerEvent{
+		EventType: strings.ToLower(prayer),
+		Prayer:    prayer,
+		Time:      instant,
+		Timezone:  instant.Location().String(),
+	})
+	if err != nil {
+		return err
+	}
+	return p.publish("event", string(payload), false)
+}
+
+// publishDiscovery publishes the Home Assistant discovery config of every topic
+func (p *Publisher) publishDiscovery() error {
+	nodeID, name := p.NodeID, p.Name
+	if nodeID == "" {
+		nodeID = DefaultNodeID
+	}
+	if name == "" {
+		name = DefaultName
+	}
+	device := discoveryDevice{
+		Identifiers:  []string{"prayer_schedule_" + nodeID},
+		Name:         name,
+		Manufacturer: "prayer-schedule",
+		Model:        "Prayer timings",
+	}
+
+	var configs []discoveryConfig
+	for _, prayer := range timingPrayers {
+		configs = append(configs, discoveryConfig{Name: prayer, DeviceClass: "timestamp", ObjectID: strings.ToLower(prayer)})
+	}
+	configs = append(configs,
+		discoveryConfig{Name: "Current prayer", ObjectID: "current_prayer", Icon: "mdi:clock-check-outline"},
+		discoveryConfig{Name: "Next prayer", ObjectID: "next_prayer", Icon: "mdi:clock-outline"},
+		discoveryConfig{Name: "Next prayer time", ObjectID: "next_prayer_time", DeviceClass: "timestamp"},
+		discoveryConfig{Name: "Next prayer countdown", ObjectID: "countdown", DeviceClass: "duration", UnitOfMeasurement: "min", Icon: "mdi:timer-sand"},
+	)
+	for _, config := range configs {
+		if err := p.publishDiscoveryConfig("sensor", config, nodeID, device); err != nil {
+			return err
+		}
+	}
+
+	eventTypes := make([]string, len(eventPrayers))
+	for i, prayer := range eventPrayers {
+		eventTypes[i] = strings.ToLower(prayer)
+	}
+	return p.publishDiscoveryConfig("event", discoveryConfig{Name: "Prayer", ObjectID: "event", EventTypes: eventTypes}, nodeID, device)
+}
+
+func (p *Publisher) publishDiscoveryConfig(component string, config discoveryConfig, nodeID string, device discoveryDevice) error {
+	object := config.ObjectID
+	config.UniqueID = "prayer_schedule_" + nodeID + "_" + object
+	config.ObjectID = "prayer_" + nodeID + "_" + object
+	config.StateTopic = p.Topic(object)
+	config.AvailabilityTopic = p.Topic("status")
+	config.Device = device
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	prefix := p.DiscoveryPrefix
+	if prefix == "" {
+		prefix = DefaultDiscoveryPrefix
+	}
+	topic := prefix + "/" + component + "/prayer_schedule_" + nodeID + "/" + object + "/config"
+	return p.publishTopic(topic, string(payload), true)
+}
+
+// publish publishes payload to the topic of object
+func (p *Publisher) publish(object string, payload string, retained bool) error {
+	return p.publishTopic(p.Topic(object), payload, retained)
+}
+
+func (p *Publisher) publishTopic(topic string, payload string, retained bool) error {
+	token := p.Client.Publish(topic, p.QoS, retained, payload)
+	if !token.WaitTimeout(DefaultPublishTimeout) {
+		return fmt.Errorf("timed out publishing to %s", topic)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("unable to publish to %s: %s", topic, err)
+	}
+	return nil
+}
+
+func containsPrayer(prayers []string, prayer string) bool {
+	for _, p := range prayers {
+		if p == prayer {
+			return true
+		}
+	}
+	return false
+}
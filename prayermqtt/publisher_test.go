@@ -0,0 +1,335 @@
+package prayermqtt_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	psched "github.com/moali87/prayer-schedule"
+	"github.com/moali87/prayer-schedule/prayermqtt"
+)
+
+// testMonthProvider returns Fajr at 05:10, Dhuhr at 12:30 and Isha at 19:30 every day of the month in Los Angeles
+type testMonthProvider struct {
+	location *time.Location
+}
+
+func (p *testMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	first := time.Date(input.CustTime.Year(), input.CustTime.Month(), 1, 0, 0, 0, 0, p.location)
+	output := &psched.PCalOutput{Code: 200, Status: "OK", Latitude: input.Latitude, Longitude: input.Longitude}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		at := func(hour int, minute int) string {
+			return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, p.location).Format("15:04 (MST)")
+		}
+		pcalDay := psched.PCalDay{
+			Timings: psched.FiveDailyPrayers{
+				Imsak:   at(5, 0),
+				Fajr:    at(5, 10),
+				Sunrise: at(6, 30),
+				Dhuhr:   at(12, 30),
+				Asr:     at(15, 45),
+				Maghrib: at(18, 15),
+				Isha:    at(19, 30),
+			},
+		}
+		pcalDay.Date.Gregorian.Date = day.Format("02-01-2006")
+		pcalDay.Meta.Timezone = p.location.String()
+		output.Data = append(output.Data, pcalDay)
+	}
+	return output, nil
+}
+
+// testMessage is a message published to a testBroker
+type testMessage struct {
+	topic    string
+	payload  string
+	retained bool
+}
+
+// testBroker is a local MQTT broker which accepts every client and records what they publish
+type testBroker struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []testMessage
+	retained map[string]string
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := &testBroker{listener: listener, retained: map[string]string{}}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+	return broker
+}
+
+func (b *testBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		var reply packets.ControlPacket
+		switch packet := packet.(type) {
+		case *packets.ConnectPacket:
+			reply = packets.NewControlPacket(packets.Connack)
+		case *packets.PublishPacket:
+			b.mu.Lock()
+			b.messages = append(b.messages, testMessage{topic: packet.TopicName, payload: string(packet.Payload), retained: packet.Retain})
+			if packet.Retain {
+				b.retained[packet.TopicName] = string(packet.Payload)
+			}
+			b.mu.Unlock()
+			if packet.Qos == 1 {
+				puback := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				puback.MessageID = packet.MessageID
+				reply = puback
+			}
+		case *packets.PingreqPacket:
+			reply = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			return
+		}
+		if reply != nil {
+			if err := reply.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// retainedPayload returns the retained payload of topic
+func (b *testBroker) retainedPayload(topic string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.retained[topic]
+}
+
+// published returns the messages published to topic
+func (b *testBroker) published(topic string) []testMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var messages []testMessage
+	for _, message := range b.messages {
+		if message.topic == topic {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+func TestPublisher(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := newTestBroker(t)
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 29, 30, 0, losAngeles))
+	publisher := &prayermqtt.Publisher{
+		Customer: &psched.CustomerLocationInput{
+			Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+			Provider:    &testMonthProvider{location: losAngeles},
+		},
+		Clock:  clock,
+		NodeID: "la",
+		QoS:    1,
+		OnError: func(err error) {
+			t.Error(err)
+		},
+	}
+
+	options := mqtt.NewClientOptions().AddBroker("tcp://" + broker.listener.Addr().String()).SetClientID("prayer-schedule-test")
+	publisher.SetWill(options)
+	client := mqtt.NewClient(options)
+	if token := client.Connect(); !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("unable to connect: %v", token.Error())
+	}
+	defer client.Disconnect(0)
+	publisher.Client = client
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- publisher.Run(ctx) }()
+	clock.BlockUntil(1)
+
+	state := map[string]string{
+		"prayer-schedule/la/status":           prayermqtt.StatusOnline,
+		"prayer-schedule/la/fajr":             "2022-10-12T05:10:00-07:00",
+		"prayer-schedule/la/isha":             "2022-10-12T19:30:00-07:00",
+		"prayer-schedule/la/current_prayer":   "Sunrise",
+		"prayer-schedule/la/next_prayer":      "Dhuhr",
+		"prayer-schedule/la/next_prayer_time": "2022-10-12T12:30:00-07:00",
+		"prayer-schedule/la/countdown":        "1",
+	}
+	for topic, want := range state {
+		if got := broker.retainedPayload(topic); got != want {
+			t.Errorf("%s = %q, want %q", topic, got, want)
+		}
+	}
+
+	var countdown struct {
+		UniqueID          string `json:"unique_id"`
+		StateTopic        string `json:"state_topic"`
+		AvailabilityTopic string `json:"availability_topic"`
+		DeviceClass       string `json:"device_class"`
+		UnitOfMeasurement string `json:"unit_of_measurement"`
+		Device            struct {
+			Identifiers []string `json:"identifiers"`
+		} `json:"device"`
+	}
+	if err := json.Unmarshal([]byte(broker.retainedPayload("homeassistant/sensor/prayer_schedule_la/countdown/config")), &countdown); err != nil {
+		t.Fatal(err)
+	}
+	if countdown.UniqueID != "prayer_schedule_la_countdown" || countdown.StateTopic != "prayer-schedule/la/countdown" ||
+		countdown.AvailabilityTopic != "prayer-schedule/la/status" || countdown.DeviceClass != "duration" ||
+		countdown.UnitOfMeasurement != "min" || len(countdown.Device.Identifiers) != 1 || countdown.Device.Identifiers[0] != "prayer_schedule_la" {
+		t.Errorf("countdown discovery config = %+v", countdown)
+	}
+	var event struct {
+		StateTopic string   `json:"state_topic"`
+		EventTypes []string `json:"event_types"`
+	}
+	if err := json.Unmarshal([]byte(broker.retainedPayload("homeassistant/event/prayer_schedule_la/event/config")), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.StateTopic != "prayer-schedule/la/event" || len(event.EventTypes) != 5 || event.EventTypes[1] != "dhuhr" {
+		t.Errorf("event discovery config = %+v", event)
+	}
+	if messages := broker.published("prayer-schedule/la/event"); len(messages) != 0 {
+		t.Fatalf("events before Dhuhr = %+v", messages)
+	}
+
+	// Dhuhr begins
+	clock.Advance(30 * time.Second)
+	clock.BlockUntil(1)
+	messages := broker.published("prayer-schedule/la/event")
+	if len(messages) != 1 {
+		t.Fatalf("events = %+v, want one", messages)
+	}
+	if messages[0].retained {
+		t.Error("event is retained")
+	}
+	var dhuhr prayermqtt.PrayerEvent
+	if err := json.Unmarshal([]byte(messages[0].payload), &dhuhr); err != nil {
+		t.Fatal(err)
+	}
+	if dhuhr.EventType != "dhuhr" || dhuhr.Prayer != "Dhuhr" || dhuhr.Timezone != "America/Los_Angeles" ||
+		!dhuhr.Time.Equal(time.Date(2022, time.October, 12, 12, 30, 0, 0, losAngeles)) {
+		t.Errorf("event = %+v", dhuhr)
+	}
+	if got := broker.retainedPayload("prayer-schedule/la/current_prayer"); got != "Dhuhr" {
+		t.Errorf("current prayer = %q, want Dhuhr", got)
+	}
+	if got := broker.retainedPayload("prayer-schedule/la/countdown"); got != "195" {
+		t.Errorf("countdown = %q, want 195", got)
+	}
+
+	// The countdown is published every minute between prayers, without another event
+	clock.Advance(time.Minute)
+	clock.BlockUntil(1)
+	if got := broker.retainedPayload("prayer-schedule/la/countdown"); got != "194" {
+		t.Errorf("countdown = %q, want 194", got)
+	}
+	if messages := broker.published("prayer-schedule/la/event"); len(messages) != 1 {
+		t.Errorf("events = %+v, want one", messages)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+	if got := broker.retainedPayload("prayer-schedule/la/status"); got != prayermqtt.StatusOffline {
+		t.Errorf("status = %q, want offline", got)
+	}
+}
+
+// testGeocoder returns fixed coordinates for every postal code, counting lookups
+type testGeocoder struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (g *testGeocoder) Geocode(ctx context.Context, countryCode string, postalCode string) (*psched.CustomerCoordinatesOutput, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls++
+	return &psched.CustomerCoordinatesOutput{Lat: 34.103, Lng: -118.4105}, nil
+}
+
+func TestPublisherGeocodesOnce(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := newTestBroker(t)
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 13, 0, 0, 0, losAngeles))
+	geocoder := &testGeocoder{}
+	publisher := &prayermqtt.Publisher{
+		Customer: &psched.CustomerLocationInput{
+			CountryCode: "US",
+			PostalCode:  "90210",
+			Geocoder:    geocoder,
+			Provider:    &testMonthProvider{location: losAngeles},
+		},
+		Clock: clock,
+		QoS:   1,
+		OnError: func(err error) {
+			t.Error(err)
+		},
+	}
+
+	client := mqtt.NewClient(mqtt.NewClientOptions().AddBroker("tcp://" + broker.listener.Addr().String()).SetClientID("prayer-schedule-test"))
+	if token := client.Connect(); !token.WaitTimeout(5*time.Second) || token.Error() != nil {
+		t.Fatalf("unable to connect: %v", token.Error())
+	}
+	defer client.Disconnect(0)
+	publisher.Client = client
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- publisher.Run(ctx) }()
+
+	// The state is published every minute with the coordinates of the first lookup
+	for i := 0; i < 3; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute)
+	}
+	clock.BlockUntil(1)
+	cancel()
+	<-done
+
+	geocoder.mu.Lock()
+	defer geocoder.mu.Unlock()
+	if geocoder.calls != 1 {
+		t.Errorf("postal code was geocoded %d times, want once", geocoder.calls)
+	}
+	if got := broker.retainedPayload("prayer-schedule/home/current_prayer"); got != "Dhuhr" {
+		t.Errorf("current prayer = %q, want Dhuhr", got)
+	}
+}
+
+func TestPublisherRequiresClient(t *testing.T) {
+	if err := (&prayermqtt.Publisher{}).Run(context.Background()); err == nil {
+		t.Error("Run without a client succeeded")
+	}
+}
@@ -0,0 +1,4 @@
+// Package prayerpb contains the protobuf messages and gRPC service of proto/prayerschedule/v1/prayer_schedule.proto
+package prayerpb
+
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=github.com/moali87/prayer-schedule --go-grpc_out=.. --go-grpc_opt=module=github.com/moali87/prayer-schedule prayerschedule/v1/prayer_schedule.proto
@@ -0,0 +1,1766 @@
+// Prayer schedules of a location over gRPC.  Served by the prayergrpc package, which looks timings up with the same
+// provider and geocoder as CustomerLocationInput.PrayerCalendar.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: prayerschedule/v1/prayer_schedule.proto
+
+package prayerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HijriCalendar int32
+
+const (
+	HijriCalendar_HIJRI_CALENDAR_UMM_AL_QURA HijriCalendar = 0
+	HijriCalendar_HIJRI_CALENDAR_TABULAR     HijriCalendar = 1
+)
+
+// Enum value maps for HijriCalendar.
+var (
+	HijriCalendar_name = map[int32]string{
+		0: "HIJRI_CALENDAR_UMM_AL_QURA",
+		1: "HIJRI_CALENDAR_TABULAR",
+	}
+	HijriCalendar_value = map[string]int32{
+		"HIJRI_CALENDAR_UMM_AL_QURA": 0,
+		"HIJRI_CALENDAR_TABULAR":     1,
+	}
+)
+
+func (x HijriCalendar) Enum() *HijriCalendar {
+	p := new(HijriCalendar)
+	*p = x
+	return p
+}
+
+func (x HijriCalendar) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HijriCalendar) Descriptor() protoreflect.EnumDescriptor {
+	return file_prayerschedule_v1_prayer_schedule_proto_enumTypes[0].Descriptor()
+}
+
+func (HijriCalendar) Type() protoreflect.EnumType {
+	return &file_prayerschedule_v1_prayer_schedule_proto_enumTypes[0]
+}
+
+func (x HijriCalendar) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HijriCalendar.Descriptor instead.
+func (HijriCalendar) EnumDescriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+// Location is given either as coordinates or as a postal code looked up by the server's geocoder
+type Location struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Location:
+	//	*Location_Coordinates
+	//	*Location_PostalCode
+	Location isLocation_Location `protobuf_oneof:"location"`
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *Location) GetLocation() isLocation_Location {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
+func (x *Location) GetCoordinates() *Coordinates {
+	if x, ok := x.GetLocation().(*Location_Coordinates); ok {
+		return x.Coordinates
+	}
+	return nil
+}
+
+func (x *Location) GetPostalCode() *PostalCode {
+	if x, ok := x.GetLocation().(*Location_PostalCode); ok {
+		return x.PostalCode
+	}
+	return nil
+}
+
+type isLocation_Location interface {
+	isLocation_Location()
+}
+
+type Location_Coordinates struct {
+	Coordinates *Coordinates `protobuf:"bytes,1,opt,name=coordinates,proto3,oneof"`
+}
+
+type Location_PostalCode struct {
+	PostalCode *PostalCode `protobuf:"bytes,2,opt,name=postal_code,json=postalCode,proto3,oneof"`
+}
+
+func (*Location_Coordinates) isLocation_Location() {}
+
+func (*Location_PostalCode) isLocation_Location() {}
+
+type Coordinates struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitude  float32 `protobuf:"fixed32,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float32 `protobuf:"fixed32,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+}
+
+func (x *Coordinates) Reset() {
+	*x = Coordinates{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Coordinates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coordinates) ProtoMessage() {}
+
+func (x *Coordinates) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coordinates.ProtoReflect.Descriptor instead.
+func (*Coordinates) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Coordinates) GetLatitude() float32 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Coordinates) GetLongitude() float32 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type PostalCode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ISO 3166 country code, such as US
+	CountryCode string `protobuf:"bytes,1,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	PostalCode  string `protobuf:"bytes,2,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+}
+
+func (x *PostalCode) Reset() {
+	*x = PostalCode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PostalCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostalCode) ProtoMessage() {}
+
+func (x *PostalCode) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostalCode.ProtoReflect.Descriptor instead.
+func (*PostalCode) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PostalCode) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *PostalCode) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+type CalculationSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Aladhan calculation method.  Defaults to 2, the Islamic Society of North America
+	Method *int32 `protobuf:"varint,1,opt,name=method,proto3,oneof" json:"method,omitempty"`
+	// Asr juristic school.  0 for Shafi and 1 for Hanafi
+	School int32 `protobuf:"varint,2,opt,name=school,proto3" json:"school,omitempty"`
+	// High latitude rule.  0 for the provider default, 1 middle of the night, 2 one seventh, 3 angle based
+	LatitudeAdjustmentMethod int32 `protobuf:"varint,3,opt,name=latitude_adjustment_method,json=latitudeAdjustmentMethod,proto3" json:"latitude_adjustment_method,omitempty"`
+	// Minutes added to each calculated time
+	Offsets       *Offsets      `protobuf:"bytes,4,opt,name=offsets,proto3" json:"offsets,omitempty"`
+	HijriCalendar HijriCalendar `protobuf:"varint,5,opt,name=hijri_calendar,json=hijriCalendar,proto3,enum=prayerschedule.v1.HijriCalendar" json:"hijri_calendar,omitempty"`
+	// Days added to Hijri dates for local moon sighting, between -2 and 2
+	HijriAdjustment int32 `protobuf:"varint,6,opt,name=hijri_adjustment,json=hijriAdjustment,proto3" json:"hijri_adjustment,omitempty"`
+}
+
+func (x *CalculationSettings) Reset() {
+	*x = CalculationSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CalculationSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculationSettings) ProtoMessage() {}
+
+func (x *CalculationSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculationSettings.ProtoReflect.Descriptor instead.
+func (*CalculationSettings) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CalculationSettings) GetMethod() int32 {
+	if x != nil && x.Method != nil {
+		return *x.Method
+	}
+	return 0
+}
+
+func (x *CalculationSettings) GetSchool() int32 {
+	if x != nil {
+		return x.School
+	}
+	return 0
+}
+
+func (x *CalculationSettings) GetLatitudeAdjustmentMethod() int32 {
+	if x != nil {
+		return x.LatitudeAdjustmentMethod
+	}
+	return 0
+}
+
+func (x *CalculationSettings) GetOffsets() *Offsets {
+	if x != nil {
+		return x.Offsets
+	}
+	return nil
+}
+
+func (x *CalculationSettings) GetHijriCalendar() HijriCalendar {
+	if x != nil {
+		return x.HijriCalendar
+	}
+	return HijriCalendar_HIJRI_CALENDAR_UMM_AL_QURA
+}
+
+func (x *CalculationSettings) GetHijriAdjustment() int32 {
+	if x != nil {
+		return x.HijriAdjustment
+	}
+	return 0
+}
+
+type Offsets struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Imsak   int32 `protobuf:"varint,1,opt,name=imsak,proto3" json:"imsak,omitempty"`
+	Fajr    int32 `protobuf:"varint,2,opt,name=fajr,proto3" json:"fajr,omitempty"`
+	Sunrise int32 `protobuf:"varint,3,opt,name=sunrise,proto3" json:"sunrise,omitempty"`
+	Dhuhr   int32 `protobuf:"varint,4,opt,name=dhuhr,proto3" json:"dhuhr,omitempty"`
+	Asr     int32 `protobuf:"varint,5,opt,name=asr,proto3" json:"asr,omitempty"`
+	Maghrib int32 `protobuf:"varint,6,opt,name=maghrib,proto3" json:"maghrib,omitempty"`
+	Isha    int32 `protobuf:"varint,7,opt,name=isha,proto3" json:"isha,omitempty"`
+}
+
+func (x *Offsets) Reset() {
+	*x = Offsets{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Offsets) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Offsets) ProtoMessage() {}
+
+func (x *Offsets) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Offsets.ProtoReflect.Descriptor instead.
+func (*Offsets) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Offsets) GetImsak() int32 {
+	if x != nil {
+		return x.Imsak
+	}
+	return 0
+}
+
+func (x *Offsets) GetFajr() int32 {
+	if x != nil {
+		return x.Fajr
+	}
+	return 0
+}
+
+func (x *Offsets) GetSunrise() int32 {
+	if x != nil {
+		return x.Sunrise
+	}
+	return 0
+}
+
+func (x *Offsets) GetDhuhr() int32 {
+	if x != nil {
+		return x.Dhuhr
+	}
+	return 0
+}
+
+func (x *Offsets) GetAsr() int32 {
+	if x != nil {
+		return x.Asr
+	}
+	return 0
+}
+
+func (x *Offsets) GetMaghrib() int32 {
+	if x != nil {
+		return x.Maghrib
+	}
+	return 0
+}
+
+func (x *Offsets) GetIsha() int32 {
+	if x != nil {
+		return x.Isha
+	}
+	return 0
+}
+
+// Date is a Gregorian calendar day
+type Date struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Year  int32 `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month int32 `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	Day   int32 `protobuf:"varint,3,opt,name=day,proto3" json:"day,omitempty"`
+}
+
+func (x *Date) Reset() {
+	*x = Date{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Date) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Date) ProtoMessage() {}
+
+func (x *Date) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Date.ProtoReflect.Descriptor instead.
+func (*Date) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Date) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Date) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *Date) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+type HijriDate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Year      int32  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month     int32  `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	Day       int32  `protobuf:"varint,3,opt,name=day,proto3" json:"day,omitempty"`
+	MonthName string `protobuf:"bytes,4,opt,name=month_name,json=monthName,proto3" json:"month_name,omitempty"`
+}
+
+func (x *HijriDate) Reset() {
+	*x = HijriDate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HijriDate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HijriDate) ProtoMessage() {}
+
+func (x *HijriDate) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HijriDate.ProtoReflect.Descriptor instead.
+func (*HijriDate) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HijriDate) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *HijriDate) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *HijriDate) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *HijriDate) GetMonthName() string {
+	if x != nil {
+		return x.MonthName
+	}
+	return ""
+}
+
+type Timings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Imsak   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=imsak,proto3" json:"imsak,omitempty"`
+	Fajr    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=fajr,proto3" json:"fajr,omitempty"`
+	Sunrise *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=sunrise,proto3" json:"sunrise,omitempty"`
+	Dhuhr   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=dhuhr,proto3" json:"dhuhr,omitempty"`
+	Asr     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=asr,proto3" json:"asr,omitempty"`
+	Maghrib *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=maghrib,proto3" json:"maghrib,omitempty"`
+	Isha    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=isha,proto3" json:"isha,omitempty"`
+}
+
+func (x *Timings) Reset() {
+	*x = Timings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Timings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timings) ProtoMessage() {}
+
+func (x *Timings) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timings.ProtoReflect.Descriptor instead.
+func (*Timings) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Timings) GetImsak() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Imsak
+	}
+	return nil
+}
+
+func (x *Timings) GetFajr() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Fajr
+	}
+	return nil
+}
+
+func (x *Timings) GetSunrise() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Sunrise
+	}
+	return nil
+}
+
+func (x *Timings) GetDhuhr() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Dhuhr
+	}
+	return nil
+}
+
+func (x *Timings) GetAsr() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Asr
+	}
+	return nil
+}
+
+func (x *Timings) GetMaghrib() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Maghrib
+	}
+	return nil
+}
+
+func (x *Timings) GetIsha() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Isha
+	}
+	return nil
+}
+
+type Day struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Date *Date `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// IANA timezone of the timings, such as America/Los_Angeles
+	Timezone string     `protobuf:"bytes,2,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Timings  *Timings   `protobuf:"bytes,3,opt,name=timings,proto3" json:"timings,omitempty"`
+	Hijri    *HijriDate `protobuf:"bytes,4,opt,name=hijri,proto3" json:"hijri,omitempty"`
+	// Islamic events on the day, such as Ashura
+	Events []string `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *Day) Reset() {
+	*x = Day{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Day) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Day) ProtoMessage() {}
+
+func (x *Day) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Day.ProtoReflect.Descriptor instead.
+func (*Day) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Day) GetDate() *Date {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *Day) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Day) GetTimings() *Timings {
+	if x != nil {
+		return x.Timings
+	}
+	return nil
+}
+
+func (x *Day) GetHijri() *HijriDate {
+	if x != nil {
+		return x.Hijri
+	}
+	return nil
+}
+
+func (x *Day) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type GetCalendarRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Location            `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Settings *CalculationSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	Start    *Date                `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End      *Date                `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+}
+
+func (x *GetCalendarRequest) Reset() {
+	*x = GetCalendarRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCalendarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCalendarRequest) ProtoMessage() {}
+
+func (x *GetCalendarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCalendarRequest.ProtoReflect.Descriptor instead.
+func (*GetCalendarRequest) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCalendarRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetCalendarRequest) GetSettings() *CalculationSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *GetCalendarRequest) GetStart() *Date {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *GetCalendarRequest) GetEnd() *Date {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type GetCalendarResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Days        []*Day       `protobuf:"bytes,1,rep,name=days,proto3" json:"days,omitempty"`
+	Coordinates *Coordinates `protobuf:"bytes,2,opt,name=coordinates,proto3" json:"coordinates,omitempty"`
+}
+
+func (x *GetCalendarResponse) Reset() {
+	*x = GetCalendarResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCalendarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCalendarResponse) ProtoMessage() {}
+
+func (x *GetCalendarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCalendarResponse.ProtoReflect.Descriptor instead.
+func (*GetCalendarResponse) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCalendarResponse) GetDays() []*Day {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *GetCalendarResponse) GetCoordinates() *Coordinates {
+	if x != nil {
+		return x.Coordinates
+	}
+	return nil
+}
+
+type GetDayRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Location            `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Settings *CalculationSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	// Defaults to today at the location
+	Date *Date `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+}
+
+func (x *GetDayRequest) Reset() {
+	*x = GetDayRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDayRequest) ProtoMessage() {}
+
+func (x *GetDayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDayRequest.ProtoReflect.Descriptor instead.
+func (*GetDayRequest) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetDayRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetDayRequest) GetSettings() *CalculationSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *GetDayRequest) GetDate() *Date {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+type GetPrayerNowRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Location            `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Settings *CalculationSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+}
+
+func (x *GetPrayerNowRequest) Reset() {
+	*x = GetPrayerNowRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPrayerNowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPrayerNowRequest) ProtoMessage() {}
+
+func (x *GetPrayerNowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPrayerNowRequest.ProtoReflect.Descriptor instead.
+func (*GetPrayerNowRequest) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetPrayerNowRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *GetPrayerNowRequest) GetSettings() *CalculationSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type PrayerNow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CurrentPrayer     string                 `protobuf:"bytes,1,opt,name=current_prayer,json=currentPrayer,proto3" json:"current_prayer,omitempty"`
+	CurrentPrayerTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=current_prayer_time,json=currentPrayerTime,proto3" json:"current_prayer_time,omitempty"`
+	// Whether the current prayer is Isha of the previous day
+	PreviousDayIsha bool                   `protobuf:"varint,3,opt,name=previous_day_isha,json=previousDayIsha,proto3" json:"previous_day_isha,omitempty"`
+	NextPrayer      string                 `protobuf:"bytes,4,opt,name=next_prayer,json=nextPrayer,proto3" json:"next_prayer,omitempty"`
+	NextPrayerTime  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=next_prayer_time,json=nextPrayerTime,proto3" json:"next_prayer_time,omitempty"`
+	TimeUntilNext   *durationpb.Duration   `protobuf:"bytes,6,opt,name=time_until_next,json=timeUntilNext,proto3" json:"time_until_next,omitempty"`
+}
+
+func (x *PrayerNow) Reset() {
+	*x = PrayerNow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrayerNow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrayerNow) ProtoMessage() {}
+
+func (x *PrayerNow) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrayerNow.ProtoReflect.Descriptor instead.
+func (*PrayerNow) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PrayerNow) GetCurrentPrayer() string {
+	if x != nil {
+		return x.CurrentPrayer
+	}
+	return ""
+}
+
+func (x *PrayerNow) GetCurrentPrayerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CurrentPrayerTime
+	}
+	return nil
+}
+
+func (x *PrayerNow) GetPreviousDayIsha() bool {
+	if x != nil {
+		return x.PreviousDayIsha
+	}
+	return false
+}
+
+func (x *PrayerNow) GetNextPrayer() string {
+	if x != nil {
+		return x.NextPrayer
+	}
+	return ""
+}
+
+func (x *PrayerNow) GetNextPrayerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextPrayerTime
+	}
+	return nil
+}
+
+func (x *PrayerNow) GetTimeUntilNext() *durationpb.Duration {
+	if x != nil {
+		return x.TimeUntilNext
+	}
+	return nil
+}
+
+type WatchPrayersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Location            `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Settings *CalculationSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	// Send the current prayer as soon as the call starts, unless it is Sunrise, as well as each prayer as it begins
+	SendCurrent bool `protobuf:"varint,3,opt,name=send_current,json=sendCurrent,proto3" json:"send_current,omitempty"`
+}
+
+func (x *WatchPrayersRequest) Reset() {
+	*x = WatchPrayersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchPrayersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPrayersRequest) ProtoMessage() {}
+
+func (x *WatchPrayersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPrayersRequest.ProtoReflect.Descriptor instead.
+func (*WatchPrayersRequest) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchPrayersRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *WatchPrayersRequest) GetSettings() *CalculationSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *WatchPrayersRequest) GetSendCurrent() bool {
+	if x != nil {
+		return x.SendCurrent
+	}
+	return false
+}
+
+type PrayerEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prayer string                 `protobuf:"bytes,1,opt,name=prayer,proto3" json:"prayer,omitempty"`
+	Time   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// IANA timezone of the location
+	Timezone string `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Whether the event is the current prayer sent because of send_current rather than a prayer beginning
+	Current bool `protobuf:"varint,4,opt,name=current,proto3" json:"current,omitempty"`
+}
+
+func (x *PrayerEvent) Reset() {
+	*x = PrayerEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrayerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrayerEvent) ProtoMessage() {}
+
+func (x *PrayerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_prayerschedule_v1_prayer_schedule_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrayerEvent.ProtoReflect.Descriptor instead.
+func (*PrayerEvent) Descriptor() ([]byte, []int) {
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PrayerEvent) GetPrayer() string {
+	if x != nil {
+		return x.Prayer
+	}
+	return ""
+}
+
+func (x *PrayerEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *PrayerEvent) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *PrayerEvent) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+var File_prayerschedule_v1_prayer_schedule_proto protoreflect.FileDescriptor
+
+var file_prayerschedule_v1_prayer_schedule_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x70, 0x72, 0x61, 0x79, 0x65,
+	0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9c, 0x01,
+	0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x0b, 0x63, 0x6f,
+	0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x48,
+	0x00, 0x52, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x12, 0x40,
+	0x0a, 0x0b, 0x70, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x43, 0x6f,
+	0x64, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65,
+	0x42, 0x0a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x47, 0x0a, 0x0b,
+	0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c,
+	0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x6c,
+	0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x50, 0x0a, 0x0a, 0x50, 0x6f, 0x73, 0x74, 0x61, 0x6c, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x6f, 0x73, 0x74, 0x61, 0x6c,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f, 0x73,
+	0x74, 0x61, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xbd, 0x02, 0x0a, 0x13, 0x43, 0x61, 0x6c, 0x63,
+	0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12,
+	0x1b, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x00, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x63, 0x68, 0x6f, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x63,
+	0x68, 0x6f, 0x6f, 0x6c, 0x12, 0x3c, 0x0a, 0x1a, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65,
+	0x5f, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x18, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x12, 0x34, 0x0a, 0x07, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73, 0x52,
+	0x07, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x73, 0x12, 0x47, 0x0a, 0x0e, 0x68, 0x69, 0x6a, 0x72,
+	0x69, 0x5f, 0x63, 0x61, 0x6c, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x20, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x6a, 0x72, 0x69, 0x43, 0x61, 0x6c, 0x65, 0x6e, 0x64,
+	0x61, 0x72, 0x52, 0x0d, 0x68, 0x69, 0x6a, 0x72, 0x69, 0x43, 0x61, 0x6c, 0x65, 0x6e, 0x64, 0x61,
+	0x72, 0x12, 0x29, 0x0a, 0x10, 0x68, 0x69, 0x6a, 0x72, 0x69, 0x5f, 0x61, 0x64, 0x6a, 0x75, 0x73,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x68, 0x69, 0x6a,
+	0x72, 0x69, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x09, 0x0a, 0x07,
+	0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0xa3, 0x01, 0x0a, 0x07, 0x4f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x73, 0x61, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x69, 0x6d, 0x73, 0x61, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x61, 0x6a,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x66, 0x61, 0x6a, 0x72, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x75, 0x6e, 0x72, 0x69, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x73, 0x75, 0x6e, 0x72, 0x69, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x68, 0x75, 0x68, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x68, 0x75, 0x68, 0x72, 0x12, 0x10, 0x0a,
+	0x03, 0x61, 0x73, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x61, 0x73, 0x72, 0x12,
+	0x18, 0x0a, 0x07, 0x6d, 0x61, 0x67, 0x68, 0x72, 0x69, 0x62, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x6d, 0x61, 0x67, 0x68, 0x72, 0x69, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x73, 0x68,
+	0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x69, 0x73, 0x68, 0x61, 0x22, 0x42, 0x0a,
+	0x04, 0x44, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x12,
+	0x10, 0x0a, 0x03, 0x64, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x64, 0x61,
+	0x79, 0x22, 0x66, 0x0a, 0x09, 0x48, 0x69, 0x6a, 0x72, 0x69, 0x44, 0x61, 0x74, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65,
+	0x61, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x61, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x64, 0x61, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x6f,
+	0x6e, 0x74, 0x68, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xe7, 0x02, 0x0a, 0x07, 0x54, 0x69,
+	0x6d, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x69, 0x6d, 0x73, 0x61, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x05, 0x69, 0x6d, 0x73, 0x61, 0x6b, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x61, 0x6a, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x04, 0x66, 0x61, 0x6a, 0x72, 0x12, 0x34, 0x0a, 0x07, 0x73, 0x75, 0x6e, 0x72, 0x69,
+	0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x73, 0x75, 0x6e, 0x72, 0x69, 0x73, 0x65, 0x12, 0x30, 0x0a,
+	0x05, 0x64, 0x68, 0x75, 0x68, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x64, 0x68, 0x75, 0x68, 0x72, 0x12,
+	0x2c, 0x0a, 0x03, 0x61, 0x73, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x61, 0x73, 0x72, 0x12, 0x34, 0x0a,
+	0x07, 0x6d, 0x61, 0x67, 0x68, 0x72, 0x69, 0x62, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x6d, 0x61, 0x67, 0x68,
+	0x72, 0x69, 0x62, 0x12, 0x2e, 0x0a, 0x04, 0x69, 0x73, 0x68, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x69,
+	0x73, 0x68, 0x61, 0x22, 0xd0, 0x01, 0x0a, 0x03, 0x44, 0x61, 0x79, 0x12, 0x2b, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61,
+	0x74, 0x65, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65,
+	0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65,
+	0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67,
+	0x73, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x32, 0x0a, 0x05, 0x68, 0x69,
+	0x6a, 0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69,
+	0x6a, 0x72, 0x69, 0x44, 0x61, 0x74, 0x65, 0x52, 0x05, 0x68, 0x69, 0x6a, 0x72, 0x69, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xeb, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x61,
+	0x6c, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65,
+	0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6c,
+	0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73,
+	0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61,
+	0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x29, 0x0a, 0x03, 0x65, 0x6e, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x65, 0x52,
+	0x03, 0x65, 0x6e, 0x64, 0x22, 0x83, 0x01, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x43, 0x61, 0x6c, 0x65,
+	0x6e, 0x64, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x04,
+	0x64, 0x61, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x61,
+	0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x61, 0x79, 0x52, 0x04, 0x64, 0x61, 0x79, 0x73, 0x12, 0x40, 0x0a, 0x0b, 0x63, 0x6f, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x52, 0x0b, 0x63,
+	0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x22, 0xb9, 0x01, 0x0a, 0x0d, 0x47,
+	0x65, 0x74, 0x44, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x08,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6c, 0x63,
+	0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52,
+	0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2b, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x74, 0x65,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x22, 0x92, 0x01, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x61, 0x79, 0x65, 0x72, 0x4e, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37,
+	0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69,
+	0x6e, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61,
+	0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67,
+	0x73, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22, 0xd4, 0x02, 0x0a, 0x09,
+	0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x6f, 0x77, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72,
+	0x12, 0x4a, 0x0a, 0x13, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x72, 0x61, 0x79,
+	0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x11, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x11,
+	0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x5f, 0x69, 0x73, 0x68,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75,
+	0x73, 0x44, 0x61, 0x79, 0x49, 0x73, 0x68, 0x61, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74,
+	0x5f, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e,
+	0x65, 0x78, 0x74, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x12, 0x44, 0x0a, 0x10, 0x6e, 0x65, 0x78,
+	0x74, 0x5f, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0e, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x41, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x6e, 0x65,
+	0x78, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x4e, 0x65,
+	0x78, 0x74, 0x22, 0xb5, 0x01, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70,
+	0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x5f,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73,
+	0x65, 0x6e, 0x64, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x8b, 0x01, 0x0a, 0x0b, 0x50,
+	0x72, 0x61, 0x79, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72,
+	0x61, 0x79, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x61, 0x79,
+	0x65, 0x72, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x2a, 0x4b, 0x0a, 0x0d, 0x48, 0x69, 0x6a, 0x72,
+	0x69, 0x43, 0x61, 0x6c, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x12, 0x1e, 0x0a, 0x1a, 0x48, 0x49, 0x4a,
+	0x52, 0x49, 0x5f, 0x43, 0x41, 0x4c, 0x45, 0x4e, 0x44, 0x41, 0x52, 0x5f, 0x55, 0x4d, 0x4d, 0x5f,
+	0x41, 0x4c, 0x5f, 0x51, 0x55, 0x52, 0x41, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x48, 0x49, 0x4a,
+	0x52, 0x49, 0x5f, 0x43, 0x41, 0x4c, 0x45, 0x4e, 0x44, 0x41, 0x52, 0x5f, 0x54, 0x41, 0x42, 0x55,
+	0x4c, 0x41, 0x52, 0x10, 0x01, 0x32, 0xe2, 0x02, 0x0a, 0x0e, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x5c, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43,
+	0x61, 0x6c, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x12, 0x25, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43,
+	0x61, 0x6c, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26,
+	0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x6c, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x44, 0x61, 0x79,
+	0x12, 0x20, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x61, 0x79, 0x12, 0x54, 0x0a, 0x0c, 0x47, 0x65,
+	0x74, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x6f, 0x77, 0x12, 0x26, 0x2e, 0x70, 0x72, 0x61,
+	0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x4e, 0x6f, 0x77,
+	0x12, 0x58, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73,
+	0x12, 0x26, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x61, 0x79, 0x65,
+	0x72, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x61,
+	0x79, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x6f, 0x61, 0x6c, 0x69, 0x38, 0x37,
+	0x2f, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x2d, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x61, 0x79, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_prayerschedule_v1_prayer_schedule_proto_rawDescOnce sync.Once
+	file_prayerschedule_v1_prayer_schedule_proto_rawDescData = file_prayerschedule_v1_prayer_schedule_proto_rawDesc
+)
+
+func file_prayerschedule_v1_prayer_schedule_proto_rawDescGZIP() []byte {
+	file_prayerschedule_v1_prayer_schedule_proto_rawDescOnce.Do(func() {
+		file_prayerschedule_v1_prayer_schedule_proto_rawDescData = protoimpl.X.CompressGZIP(file_prayerschedule_v1_prayer_schedule_proto_rawDescData)
+	})
+	return file_prayerschedule_v1_prayer_schedule_proto_rawDescData
+}
+
+var file_prayerschedule_v1_prayer_schedule_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_prayerschedule_v1_prayer_schedule_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_prayerschedule_v1_prayer_schedule_proto_goTypes = []interface{}{
+	(HijriCalendar)(0),            // 0: prayerschedule.v1.HijriCalendar
+	(*Location)(nil),              // 1: prayerschedule.v1.Location
+	(*Coordinates)(nil),           // 2: prayerschedule.v1.Coordinates
+	(*PostalCode)(nil),            // 3: prayerschedule.v1.PostalCode
+	(*CalculationSettings)(nil),   // 4: prayerschedule.v1.CalculationSettings
+	(*Offsets)(nil),               // 5: prayerschedule.v1.Offsets
+	(*Date)(nil),                  // 6: prayerschedule.v1.Date
+	(*HijriDate)(nil),             // 7: prayerschedule.v1.HijriDate
+	(*Timings)(nil),               // 8: prayerschedule.v1.Timings
+	(*Day)(nil),                   // 9: prayerschedule.v1.Day
+	(*GetCalendarRequest)(nil),    // 10: prayerschedule.v1.GetCalendarRequest
+	(*GetCalendarResponse)(nil),   // 11: prayerschedule.v1.GetCalendarResponse
+	(*GetDayRequest)(nil),         // 12: prayerschedule.v1.GetDayRequest
+	(*GetPrayerNowRequest)(nil),   // 13: prayerschedule.v1.GetPrayerNowRequest
+	(*PrayerNow)(nil),             // 14: prayerschedule.v1.PrayerNow
+	(*WatchPrayersRequest)(nil),   // 15: prayerschedule.v1.WatchPrayersRequest
+	(*PrayerEvent)(nil),           // 16: prayerschedule.v1.PrayerEvent
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 18: google.protobuf.Duration
+}
+var file_prayerschedule_v1_prayer_schedule_proto_depIdxs = []int32{
+	2,  // 0: prayerschedule.v1.Location.coordinates:type_name -> prayerschedule.v1.Coordinates
+	3,  // 1: prayerschedule.v1.Location.postal_code:type_name -> prayerschedule.v1.PostalCode
+	5,  // 2: prayerschedule.v1.CalculationSettings.offsets:type_name -> prayerschedule.v1.Offsets
+	0,  // 3: prayerschedule.v1.CalculationSettings.hijri_calendar:type_name -> prayerschedule.v1.HijriCalendar
+	17, // 4: prayerschedule.v1.Timings.imsak:type_name -> google.protobuf.Timestamp
+	17, // 5: prayerschedule.v1.Timings.fajr:type_name -> google.protobuf.Timestamp
+	17, // 6: prayerschedule.v1.Timings.sunrise:type_name -> google.protobuf.Timestamp
+	17, // 7: prayerschedule.v1.Timings.dhuhr:type_name -> google.protobuf.Timestamp
+	17, // 8: prayerschedule.v1.Timings.asr:type_name -> google.protobuf.Timestamp
+	17, // 9: prayerschedule.v1.Timings.maghrib:type_name -> google.protobuf.Timestamp
+	17, // 10: prayerschedule.v1.Timings.isha:type_name -> google.protobuf.Timestamp
+	6,  // 11: prayerschedule.v1.Day.date:type_name -> prayerschedule.v1.Date
+	8,  // 12: prayerschedule.v1.Day.timings:type_name -> prayerschedule.v1.Timings
+	7,  // 13: prayerschedule.v1.Day.hijri:type_name -> prayerschedule.v1.HijriDate
+	1,  // 14: prayerschedule.v1.GetCalendarRequest.location:type_name -> prayerschedule.v1.Location
+	4,  // 15: prayerschedule.v1.GetCalendarRequest.settings:type_name -> prayerschedule.v1.CalculationSettings
+	6,  // 16: prayerschedule.v1.GetCalendarRequest.start:type_name -> prayerschedule.v1.Date
+	6,  // 17: prayerschedule.v1.GetCalendarRequest.end:type_name -> prayerschedule.v1.Date
+	9,  // 18: prayerschedule.v1.GetCalendarResponse.days:type_name -> prayerschedule.v1.Day
+	2,  // 19: prayerschedule.v1.GetCalendarResponse.coordinates:type_name -> prayerschedule.v1.Coordinates
+	1,  // 20: prayerschedule.v1.GetDayRequest.location:type_name -> prayerschedule.v1.Location
+	4,  // 21: prayerschedule.v1.GetDayRequest.settings:type_name -> prayerschedule.v1.CalculationSettings
+	6,  // 22: prayerschedule.v1.GetDayRequest.date:type_name -> prayerschedule.v1.Date
+	1,  // 23: prayerschedule.v1.GetPrayerNowRequest.location:type_name -> prayerschedule.v1.Location
+	4,  // 24: prayerschedule.v1.GetPrayerNowRequest.settings:type_name -> prayerschedule.v1.CalculationSettings
+	17, // 25: prayerschedule.v1.PrayerNow.current_prayer_time:type_name -> google.protobuf.Timestamp
+	17, // 26: prayerschedule.v1.PrayerNow.next_prayer_time:type_name -> google.protobuf.Timestamp
+	18, // 27: prayerschedule.v1.PrayerNow.time_until_next:type_name -> google.protobuf.Duration
+	1,  // 28: prayerschedule.v1.WatchPrayersRequest.location:type_name -> prayerschedule.v1.Location
+	4,  // 29: prayerschedule.v1.WatchPrayersRequest.settings:type_name -> prayerschedule.v1.CalculationSettings
+	17, // 30: prayerschedule.v1.PrayerEvent.time:type_name -> google.protobuf.Timestamp
+	10, // 31: prayerschedule.v1.PrayerSchedule.GetCalendar:input_type -> prayerschedule.v1.GetCalendarRequest
+	12, // 32: prayerschedule.v1.PrayerSchedule.GetDay:input_type -> prayerschedule.v1.GetDayRequest
+	13, // 33: prayerschedule.v1.PrayerSchedule.GetPrayerNow:input_type -> prayerschedule.v1.GetPrayerNowRequest
+	15, // 34: prayerschedule.v1.PrayerSchedule.WatchPrayers:input_type -> prayerschedule.v1.WatchPrayersRequest
+	11, // 35: prayerschedule.v1.PrayerSchedule.GetCalendar:output_type -> prayerschedule.v1.GetCalendarResponse
+	9,  // 36: prayerschedule.v1.PrayerSchedule.GetDay:output_type -> prayerschedule.v1.Day
+	14, // 37: prayerschedule.v1.PrayerSchedule.GetPrayerNow:output_type -> prayerschedule.v1.PrayerNow
+	16, // 38: prayerschedule.v1.PrayerSchedule.WatchPrayers:output_type -> prayerschedule.v1.PrayerEvent
+	35, // [35:39] is the sub-list for method output_type
+	31, // [31:35] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_prayerschedule_v1_prayer_schedule_proto_init() }
+func file_prayerschedule_v1_prayer_schedule_proto_init() {
+	if File_prayerschedule_v1_prayer_schedule_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Location); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Coordinates); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PostalCode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CalculationSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Offsets); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Date); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HijriDate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Timings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Day); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCalendarRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCalendarResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDayRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPrayerNowRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrayerNow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchPrayersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_prayerschedule_v1_prayer_schedule_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrayerEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_prayerschedule_v1_prayer_schedule_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Location_Coordinates)(nil),
+		(*Location_PostalCode)(nil),
+	}
+	file_prayerschedule_v1_prayer_schedule_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_prayerschedule_v1_prayer_schedule_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_prayerschedule_v1_prayer_schedule_proto_goTypes,
+		DependencyIndexes: file_prayerschedule_v1_prayer_schedule_proto_depIdxs,
+		EnumInfos:         file_prayerschedule_v1_prayer_schedule_proto_enumTypes,
+		MessageInfos:      file_prayerschedule_v1_prayer_schedule_proto_msgTypes,
+	}.Build()
+	File_prayerschedule_v1_prayer_schedule_proto = out.File
+	file_prayerschedule_v1_prayer_schedule_proto_rawDesc = nil
+	file_prayerschedule_v1_prayer_schedule_proto_goTypes = nil
+	file_prayerschedule_v1_prayer_schedule_proto_depIdxs = nil
+}
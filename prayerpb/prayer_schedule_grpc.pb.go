@@ -0,0 +1,261 @@
+// Prayer schedules of a location over gRPC.  Served by the prayergrpc package, which looks timings up with the same
+// provider and geocoder as CustomerLocationInput.PrayerCalendar.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: prayerschedule/v1/prayer_schedule.proto
+
+package prayerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PrayerSchedule_GetCalendar_FullMethodName  = "/prayerschedule.v1.PrayerSchedule/GetCalendar"
+	PrayerSchedule_GetDay_FullMethodName       = "/prayerschedule.v1.PrayerSchedule/GetDay"
+	PrayerSchedule_GetPrayerNow_FullMethodName = "/prayerschedule.v1.PrayerSchedule/GetPrayerNow"
+	PrayerSchedule_WatchPrayers_FullMethodName = "/prayerschedule.v1.PrayerSchedule/WatchPrayers"
+)
+
+// PrayerScheduleClient is the client API for PrayerSchedule service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PrayerScheduleClient interface {
+	// GetCalendar returns the timings of every day from start to end inclusive, at most 366 days
+	GetCalendar(ctx context.Context, in *GetCalendarRequest, opts ...grpc.CallOption) (*GetCalendarResponse, error)
+	// GetDay returns the timings of a single day
+	GetDay(ctx context.Context, in *GetDayRequest, opts ...grpc.CallOption) (*Day, error)
+	// GetPrayerNow returns the current and next prayer
+	GetPrayerNow(ctx context.Context, in *GetPrayerNowRequest, opts ...grpc.CallOption) (*PrayerNow, error)
+	// WatchPrayers sends an event each time one of the five daily prayers begins at the location, until the call is cancelled.
+	// Sunrise is not a prayer, so it has no events
+	WatchPrayers(ctx context.Context, in *WatchPrayersRequest, opts ...grpc.CallOption) (PrayerSchedule_WatchPrayersClient, error)
+}
+
+type prayerScheduleClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPrayerScheduleClient(cc grpc.ClientConnInterface) PrayerScheduleClient {
+	return &prayerScheduleClient{cc}
+}
+
+func (c *prayerScheduleClient) GetCalendar(ctx context.Context, in *GetCalendarRequest, opts ...grpc.CallOption) (*GetCalendarResponse, error) {
+	out := new(GetCalendarResponse)
+	err := c.cc.Invoke(ctx, PrayerSchedule_GetCalendar_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prayerScheduleClient) GetDay(ctx context.Context, in *GetDayRequest, opts ...grpc.CallOption) (*Day, error) {
+	out := new(Day)
+	err := c.cc.Invoke(ctx, PrayerSchedule_GetDay_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prayerScheduleClient) GetPrayerNow(ctx context.Context, in *GetPrayerNowRequest, opts ...grpc.CallOption) (*PrayerNow, error) {
+	out := new(PrayerNow)
+	err := c.cc.Invoke(ctx, PrayerSchedule_GetPrayerNow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prayerScheduleClient) WatchPrayers(ctx context.Context, in *WatchPrayersRequest, opts ...grpc.CallOption) (PrayerSchedule_WatchPrayersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PrayerSchedule_ServiceDesc.Streams[0], PrayerSchedule_WatchPrayers_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &prayerScheduleWatchPrayersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PrayerSchedule_WatchPrayersClient interface {
+	Recv() (*PrayerEvent, error)
+	grpc.ClientStream
+}
+
+type prayerScheduleWatchPrayersClient struct {
+	grpc.ClientStream
+}
+
+func (x *prayerScheduleWatchPrayersClient) Recv() (*PrayerEvent, error) {
+	m := new(PrayerEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PrayerScheduleServer is the server API for PrayerSchedule service.
+// All implementations must embed UnimplementedPrayerScheduleServer
+// for forward compatibility
+type PrayerScheduleServer interface {
+	// GetCalendar returns the timings of every day from start to end inclusive, at most 366 days
+	GetCalendar(context.Context, *GetCalendarRequest) (*GetCalendarResponse, error)
+	// GetDay returns the timings of a single day
+	GetDay(context.Context, *GetDayRequest) (*Day, error)
+	// GetPrayerNow returns the current and next prayer
+	GetPrayerNow(context.Context, *GetPrayerNowRequest) (*PrayerNow, error)
+	// WatchPrayers sends an event each time one of the five daily prayers begins at the location, until the call is cancelled.
+	// Sunrise is not a prayer, so it has no events
+	WatchPrayers(*WatchPrayersRequest, PrayerSchedule_WatchPrayersServer) error
+	mustEmbedUnimplementedPrayerScheduleServer()
+}
+
+// UnimplementedPrayerScheduleServer must be embedded to have forward compatible implementations.
+type UnimplementedPrayerScheduleServer struct {
+}
+
+func (UnimplementedPrayerScheduleServer) GetCalendar(context.Context, *GetCalendarRequest) (*GetCalendarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCalendar not implemented")
+}
+func (UnimplementedPrayerScheduleServer) GetDay(context.Context, *GetDayRequest) (*Day, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDay not implemented")
+}
+func (UnimplementedPrayerScheduleServer) GetPrayerNow(context.Context, *GetPrayerNowRequest) (*PrayerNow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrayerNow not implemented")
+}
+func (UnimplementedPrayerScheduleServer) WatchPrayers(*WatchPrayersRequest, PrayerSchedule_WatchPrayersServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPrayers not implemented")
+}
+func (UnimplementedPrayerScheduleServer) mustEmbedUnimplementedPrayerScheduleServer() {}
+
+// UnsafePrayerScheduleServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PrayerScheduleServer will
+// result in compilation errors.
+type UnsafePrayerScheduleServer interface {
+	mustEmbedUnimplementedPrayerScheduleServer()
+}
+
+func RegisterPrayerScheduleServer(s grpc.ServiceRegistrar, srv PrayerScheduleServer) {
+	s.RegisterService(&PrayerSchedule_ServiceDesc, srv)
+}
+
+func _PrayerSchedule_GetCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrayerScheduleServer).GetCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrayerSchedule_GetCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrayerScheduleServer).GetCalendar(ctx, req.(*GetCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrayerSchedule_GetDay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrayerScheduleServer).GetDay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrayerSchedule_GetDay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrayerScheduleServer).GetDay(ctx, req.(*GetDayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrayerSchedule_GetPrayerNow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrayerNowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrayerScheduleServer).GetPrayerNow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrayerSchedule_GetPrayerNow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrayerScheduleServer).GetPrayerNow(ctx, req.(*GetPrayerNowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrayerSchedule_WatchPrayers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPrayersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PrayerScheduleServer).WatchPrayers(m, &prayerScheduleWatchPrayersServer{stream})
+}
+
+type PrayerSchedule_WatchPrayersServer interface {
+	Send(*PrayerEvent) error
+	grpc.ServerStream
+}
+
+type prayerScheduleWatchPrayersServer struct {
+	grpc.ServerStream
+}
+
+func (x *prayerScheduleWatchPrayersServer) Send(m *PrayerEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PrayerSchedule_ServiceDesc is the grpc.ServiceDesc for PrayerSchedule service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PrayerSchedule_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "prayerschedule.v1.PrayerSchedule",
+	HandlerType: (*PrayerScheduleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCalendar",
+			Handler:    _PrayerSchedule_GetCalendar_Handler,
+		},
+		{
+			MethodName: "GetDay",
+			Handler:    _PrayerSchedule_GetDay_Handler,
+		},
+		{
+			MethodName: "GetPrayerNow",
+			Handler:    _PrayerSchedule_GetPrayerNow_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPrayers",
+			Handler:       _PrayerSchedule_WatchPrayers_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "prayerschedule/v1/prayer_schedule.proto",
+}
@@ -0,0 +1,261 @@
+package schedule
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// printablePrayers are the timings printed in a timetable, in column order
+var printablePrayers = []string{"Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// PrintableTimetable is a printable A4 timetable of a date range, such as a mosque's monthly timetable
+type PrintableTimetable struct {
+	MosqueName     string       // Title printed above the timetable
+	Logo           []byte       // PNG or JPEG logo printed next to the title
+	Days           []PCalDay    // Days of the timetable, such as the Days of a PCalRangeOutput
+	Iqamah         *IqamahRules // Adds the iqamah time of each prayer with a rule next to its adhan time
+	IqamahAdjacent []PCalDay    // Days around Days, such as the rest of the first and last week, only used to keep weekly iqamah times across the whole week
+	Hijri          bool         // Adds a column with the Hijri date of each day
+	Jumuah         []string     // HH:MM times of the Jumu'ah prayers, printed in a row after every Friday
+	TwelveHour     bool         // Print times such as 5:38 PM instead of 17:38
+	Today          time.Time    // Day highlighted as the current day.  Defaults to today at the time of Clock
+	Clock          Clock        // Defaults to SystemClock
+}
+
+// printableColumn is a column of a printed timetable.  Iqamah columns have the prayer as Group and "Iqamah" as Heading
+type printableColumn struct {
+	Group   string
+	Heading string
+}
+
+// printableRow is a day of a printed timetable
+type printableRow struct {
+	Cells  []string
+	Friday bool
+	Today  bool
+	Jumuah string // Jumu'ah times printed after the row, only set on Fridays
+}
+
+// printableTable is a timetable laid out for printing, shared by the HTML and PDF renditions
+type printableTable struct {
+	Title    string
+	Subtitle string
+	Columns  []printableColumn
+	Grouped  bool // Whether any prayer has an iqamah column, so the header needs two rows
+	Rows     []printableRow
+}
+
+// printableGroup is a heading of the first header row of a grouped timetable
+type printableGroup struct {
+	Heading string
+	Span    int
+}
+
+// Groups returns the headings of the first header row of a grouped timetable
+func (t *printableTable) Groups() []printableGroup {
+	var groups []printableGroup
+	for i := 0; i < len(t.Columns); {
+		span := 1
+		for i+span < len(t.Columns) && t.Columns[i+span].Group == t.Columns[i].Group && t.Columns[i].Group != "" {
+			span++
+		}
+		heading := t.Columns[i].Group
+		if heading == "" {
+			heading = t.Columns[i].Heading
+		}
+		groups = append(groups, printableGroup{Heading: heading, Span: span})
+		i += span
+	}
+	return groups
+}
+
+// table lays out the timetable
+func (p *PrintableTimetable) table() (*printableTable, error) {
+	if len(p.Days) == 0 {
+		return nil, fmt.Errorf("timetable has no days")
+	}
+	exported, err := exportDays(p.Days, ExportOptions{TwelveHour: p.TwelveHour, Iqamah: p.Iqamah, IqamahAdjacent: p.IqamahAdjacent})
+	if err != nil {
+		return nil, err
+	}
+	timeLayout := "15:04"
+	if p.TwelveHour {
+		timeLayout = "3:04 PM"
+	}
+
+	dates := make([]time.Time, len(p.Days))
+	for i := range p.Days {
+		if dates[i], err = p.Days[i].Day(); err != nil {
+			return nil, err
+		}
+	}
+	today := p.Today
+	if today.IsZero() {
+		today = clockOrSystem(p.Clock).Now()
+	}
+	today = today.In(dates[0].Location())
+
+	jumuah := make([]string, len(p.Jumuah))
+	for i, timing := range p.Jumuah {
+		jumuahTime, err := time.Parse("15:04", timing)
+		if err != nil {
+			return nil, fmt.Errorf("jumu'ah time %q is not HH:MM", timing)
+		}
+		jumuah[i] = jumuahTime.Format(timeLayout)
+	}
+
+	table := &printableTable{Title: p.MosqueName, Subtitle: printableRangeName(dates[0], dates[len(dates)-1])}
+	if table.Title == "" {
+		table.Title = "Prayer Timetable"
+	}
+	table.Columns = append(table.Columns, printableColumn{Heading: "Date"}, printableColumn{Heading: "Day"})
+	if p.Hijri {
+		table.Columns = append(table.Columns, printableColumn{Heading: "Hijri"})
+	}
+	iqamahPrayers := make(map[string]bool)
+	for _, prayer := range printablePrayers {
+		if p.Iqamah != nil && p.Iqamah.rule(prayer) != nil {
+			iqamahPrayers[prayer] = true
+			table.Grouped = true
+			table.Columns = append(table.Columns, printableColumn{Group: prayer, Heading: "Adhan"}, printableColumn{Group: prayer, Heading: "Iqamah"})
+		} else {
+			table.Columns = append(table.Columns, printableColumn{Heading: prayer})
+		}
+	}
+
+	for i, day := range exported {
+		row := printableRow{
+			Cells:  []string{dates[i].Format("2 Jan"), dates[i].Format("Mon")},
+			Friday: dates[i].Weekday() == time.Friday,
+			Today:  dates[i].Year() == today.Year() && dates[i].YearDay() == today.YearDay(),
+		}
+		if p.Hijri {
+			row.Cells = append(row.Cells, fmt.Sprintf("%d %s", day.HijriDate.Day, day.HijriDate.MonthName()))
+		}
+		for _, prayer := range printablePrayers {
+			row.Cells = append(row.Cells, day.Timings.timing(prayer))
+			if iqamahPrayers[prayer] {
+				row.Cells = append(row.Cells, day.Iqamah.timing(prayer))
+			}
+		}
+		if row.Friday && len(jumuah) > 0 {
+			row.Jumuah = "Jumu'ah: " + strings.Join(jumuah, ", ")
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// printableRangeName names the range from first to last, such as "October 2022" for a whole month
+func printableRangeName(first time.Time, last time.Time) string {
+	if first.Day() == 1 && last.AddDate(0, 0, 1).Day() == 1 && first.Year() == last.Year() && first.Month() == last.Month() {
+		return first.Format("January 2006")
+	}
+	return first.Format("2 January 2006") + " to " + last.Format("2 January 2006")
+}
+
+var printableHTMLTemplate = template.Must(template.New("timetable").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Table.Title}} - {{.Table.Subtitle}}</title>
+<style>
+@page { size: A4 portrait; margin: 12mm; }
+body { font-family: Helvetica, Arial, sans-serif; color: #222; margin: 0; }
+header { display: flex; align-items: center; gap: 6mm; margin-bottom: 4mm; }
+header img { max-height: 22mm; max-width: 40mm; }
+h1 { font-size: 18pt; margin: 0; }
+h2 { font-size: 12pt; font-weight: normal; margin: 1mm 0 0; }
+table { width: 100%; border-collapse: collapse; font-size: 9pt; }
+thead { display: table-header-group; }
+tr { page-break-inside: avoid; }
+th, td { border: 0.5pt solid #888; padding: 1mm 1.5mm; text-align: center; }
+th { background: #2f5d50; color: #fff; }
+tr.friday td { background: #e0f2e0; }
+tr.jumuah td { background: #e0f2e0; font-style: italic; text-align: left; }
+tr.today td { background: #fff2b3; font-weight: bold; }
+@media print { th, tr.friday td, tr.jumuah td, tr.today td { -webkit-print-color-adjust: exact; print-color-adjust: exact; } }
+</style>
+</head>
+<body>
+<header>
+{{- if .Logo}}
+<img src="{{.Logo}}" alt="">
+{{- end}}
+<div>
+<h1>{{.Table.Title}}</h1>
+<h2>{{.Table.Subtitle}}</h2>
+</div>
+</header>
+<table>
+<thead>
+{{- if .Table.Grouped}}
+<tr>
+{{- range .Table.Groups}}
+<th{{if eq .Span 1}} rowspan="2"{{else}} colspan="{{.Span}}"{{end}}>{{.Heading}}</th>
+{{- end}}
+</tr>
+<tr>
+{{- range .Table.Columns}}{{if .Group}}
+<th>{{.Heading}}</th>
+{{- end}}{{end}}
+</tr>
+{{- else}}
+<tr>
+{{- range .Table.Columns}}
+<th>{{.Heading}}</th>
+{{- end}}
+</tr>
+{{- end}}
+</thead>
+<tbody>
+{{- range .Table.Rows}}
+<tr{{if .Today}} class="today"{{else if .Friday}} class="friday"{{end}}>
+{{- range .Cells}}
+<td>{{.}}</td>
+{{- end}}
+</tr>
+{{- if .Jumuah}}
+<tr class="jumuah"><td colspan="{{len .Cells}}">{{.Jumuah}}</td></tr>
+{{- end}}
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+/*
+WriteHTML writes the timetable as a styled HTML page sized for printing on A4.  Fridays and today are highlighted,
+the header row is repeated on every printed page, and the logo is embedded in the page.
+*/
+func (p *PrintableTimetable) WriteHTML(w io.Writer) error {
+	table, err := p.table()
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Table *printableTable
+		Logo  template.URL
+	}{Table: table}
+	if len(p.Logo) > 0 {
+		contentType := http.DetectContentType(p.Logo)
+		if contentType != "image/png" && contentType != "image/jpeg" {
+			return fmt.Errorf("logo is %s, not a PNG or JPEG image", contentType)
+		}
+		data.Logo = template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(p.Logo))
+	}
+
+	var page bytes.Buffer
+	if err := printableHTMLTemplate.Execute(&page, data); err != nil {
+		return err
+	}
+	_, err = page.WriteTo(w)
+	return err
+}
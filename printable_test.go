@@ -0,0 +1,92 @@
+package schedule_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// printableTestLogo returns a small PNG image
+func printableTestLogo(t *testing.T) []byte {
+	t.Helper()
+	logo := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	logo.Set(0, 0, color.NRGBA{R: 0x2f, G: 0x5d, B: 0x50, A: 0xff})
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, logo); err != nil {
+		t.Fatal(err)
+	}
+	return encoded.Bytes()
+}
+
+func TestPrintableTimetableHTML(t *testing.T) {
+	month, location := exportTestMonth(t)
+	timetable := &psched.PrintableTimetable{
+		MosqueName: "Masjid <Al-Noor>",
+		Logo:       printableTestLogo(t),
+		Days:       month.Data,
+		Iqamah:     &psched.IqamahRules{Fajr: &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 20}},
+		Hijri:      true,
+		Jumuah:     []string{"13:15", "14:00"},
+		TwelveHour: true,
+		Today:      time.Date(2022, time.October, 12, 9, 0, 0, 0, location),
+	}
+	var output bytes.Buffer
+	if err := timetable.WriteHTML(&output); err != nil {
+		t.Fatal(err)
+	}
+	page := output.String()
+
+	for _, expected := range []string{
+		"<h1>Masjid &lt;Al-Noor&gt;</h1>",
+		"<h2>October 2022</h2>",
+		`<img src="data:image/png;base64,`,
+		`<th colspan="2">Fajr</th>`,
+		`<th rowspan="2">Sunrise</th>`,
+		"<td>1 Oct</td>\n<td>Sat</td>\n<td>5 Rabi al-Awwal</td>\n<td>5:10 AM</td>\n<td>5:30 AM</td>\n<td>6:30 AM</td>",
+		"Jumu&#39;ah: 1:15 PM, 2:00 PM",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("page does not contain %q", expected)
+		}
+	}
+	// October 2022 has four Fridays, and the 12th is a Wednesday
+	if count := strings.Count(page, `<tr class="friday">`); count != 4 {
+		t.Errorf("expected 4 highlighted Fridays, got %d", count)
+	}
+	if count := strings.Count(page, `<tr class="jumuah">`); count != 4 {
+		t.Errorf("expected 4 jumu'ah rows, got %d", count)
+	}
+	if count := strings.Count(page, `<tr class="today">`); count != 1 || !strings.Contains(page, "<tr class=\"today\">\n<td>12 Oct</td>") {
+		t.Errorf("expected the 12th to be highlighted once, got %d highlighted days", count)
+	}
+
+	timetable.Jumuah = []string{"1pm"}
+	if err := timetable.WriteHTML(&output); err == nil {
+		t.Error("invalid jumu'ah time did not return an error")
+	}
+	if err := (&psched.PrintableTimetable{}).WriteHTML(&output); err == nil {
+		t.Error("timetable without days did not return an error")
+	}
+}
+
+func TestPrintableTimetableClock(t *testing.T) {
+	month, _ := exportTestMonth(t)
+	// 20:00 on the 19th of October in Los Angeles is already the 20th in UTC
+	timetable := &psched.PrintableTimetable{
+		Days:  month.Data,
+		Clock: psched.NewFakeClock(time.Date(2022, time.October, 20, 3, 0, 0, 0, time.UTC)),
+	}
+	var output bytes.Buffer
+	if err := timetable.WriteHTML(&output); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output.String(), "<tr class=\"today\">\n<td>19 Oct</td>") {
+		t.Error("the 19th at the time of the clock is not highlighted")
+	}
+}
@@ -0,0 +1,21 @@
+package schedule
+
+import "context"
+
+// MonthProvider returns the prayer timings of the month of input.CustTime at the input coordinates
+type MonthProvider interface {
+	MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error)
+}
+
+// YearProvider is implemented by providers which can return every month of the year of input.CustTime at once
+type YearProvider interface {
+	YearlyPrayers(ctx context.Context, input *PCalInput) ([]*PCalOutput, error)
+}
+
+// provider returns the customer MonthProvider, falling back to aladhan
+func (c *CustomerLocationInput) provider() MonthProvider {
+	if c.Provider != nil {
+		return c.Provider
+	}
+	return &AladhanProvider{}
+}
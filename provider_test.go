@@ -0,0 +1,133 @@
+package schedule_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// testMonthProvider generates a month of timings for any input, with Fajr and Maghrib a minute later every day.
+// Timings are in location, or in the location of input.CustTime when location is nil.
+type testMonthProvider struct {
+	location *time.Location
+	mu       sync.Mutex
+	calls    int
+}
+
+func (p *testMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+
+	location := p.location
+	if location == nil {
+		location = input.CustTime.Location()
+	}
+	first := time.Date(input.CustTime.Year(), input.CustTime.Month(), 1, 0, 0, 0, 0, location)
+	output := &psched.PCalOutput{Code: 200, Status: "OK", Latitude: input.Latitude, Longitude: input.Longitude}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		at := func(hour int, minute int) string {
+			return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, location).Format("15:04 (MST)")
+		}
+		pcalDay := psched.PCalDay{
+			Timings: psched.FiveDailyPrayers{
+				Imsak:   at(5, day.Day()-1),
+				Fajr:    at(5, day.Day()+9),
+				Sunrise: at(6, 30),
+				Dhuhr:   at(12, 30),
+				Asr:     at(15, 45),
+				Maghrib: at(18, day.Day()),
+				Isha:    at(19, 30),
+			},
+		}
+		pcalDay.Date.Gregorian.Date = day.Format("02-01-2006")
+		pcalDay.Meta.Timezone = location.String()
+		pcalDay.Meta.Latitude = float64(input.Latitude)
+		pcalDay.Meta.Longitude = float64(input.Longitude)
+		output.Data = append(output.Data, pcalDay)
+	}
+	return output, nil
+}
+
+func (p *testMonthProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestAladhanProvider(t *testing.T) {
+	var requestURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURL = r.URL.String()
+		fmt.Fprint(w, `{"code":200,"status":"OK","data":[{"timings":{"Fajr":"05:38 (PDT)","Sunrise":"06:58 (PDT)",`+
+			`"Dhuhr":"12:43 (PDT)","Asr":"15:59 (PDT)","Maghrib":"18:27 (PDT)","Isha":"19:43 (PDT)","Imsak":"05:28 (PDT)"},`+
+			`"date":{"readable":"01 Oct 2022","gregorian":{"date":"01-10-2022"}},"meta":{"timezone":"America/Los_Angeles"}}]}`)
+	}))
+	defer server.Close()
+
+	provider := &psched.AladhanProvider{BaseURL: server.URL}
+	input := &psched.PCalInput{
+		CustTime:    time.Date(2022, time.October, 22, 10, 10, 0, 0, time.UTC),
+		Institution: 2,
+		Latitude:    34.103,
+		Longitude:   -118.4105,
+	}
+	output, err := provider.MonthlyPrayers(context.Background(), input)
+	if err != nil {
+		t.Fatalf("aladhan provider returned an error: %s", err)
+	}
+
+	if requestURL != "/calendar?latitude=34.103&longitude=-118.4105&method=2&month=10&year=2022" {
+		t.Errorf("unexpected aladhan request: %s", requestURL)
+	}
+	if len(output.Data) != 1 || output.Data[0].Timings.Imsak != "05:28 (PDT)" || output.Data[0].Meta.Timezone != "America/Los_Angeles" {
+		t.Errorf("aladhan response was not decoded: %+v", output.Data)
+	}
+}
+
+func TestAladhanProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code":400,"status":"BAD_REQUEST","data":"Please specify a valid latitude"}`)
+	}))
+	defer server.Close()
+
+	provider := &psched.AladhanProvider{BaseURL: server.URL}
+	if _, err := provider.MonthlyPrayers(context.Background(), &psched.PCalInput{}); err == nil {
+		t.Error("aladhan error response did not return an error")
+	}
+}
+
+func TestAladhanProviderSettings(t *testing.T) {
+	var requestURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURL = r.URL.String()
+		fmt.Fprint(w, `{"code":200,"status":"OK","data":[]}`)
+	}))
+	defer server.Close()
+
+	provider := &psched.AladhanProvider{BaseURL: server.URL}
+	input := &psched.PCalInput{
+		CustTime:                 time.Date(2022, time.October, 22, 10, 10, 0, 0, time.UTC),
+		Institution:              2,
+		Latitude:                 51.5,
+		Longitude:                -0.12,
+		School:                   1,
+		LatitudeAdjustmentMethod: 3,
+		Offsets:                  psched.PrayerOffsets{Fajr: -2, Isha: 5},
+	}
+	if _, err := provider.MonthlyPrayers(context.Background(), input); err != nil {
+		t.Fatalf("aladhan provider returned an error: %s", err)
+	}
+
+	expected := "/calendar?latitude=51.5&longitude=-0.12&method=2&month=10&year=2022&school=1&latitudeAdjustmentMethod=3&tune=0,-2,0,0,0,0,0,5,0"
+	if requestURL != expected {
+		t.Errorf("unexpected aladhan request: %s", requestURL)
+	}
+}
@@ -0,0 +1,141 @@
+package schedule
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	kaabaLatitude  = 21.422487
+	kaabaLongitude = 39.826206
+	earthRadiusKm  = 6371.0088 // Mean earth radius
+)
+
+// QiblaOutput contains the direction and distance from a location to the Kaaba
+type QiblaOutput struct {
+	Bearing             float64 // Great circle initial bearing in degrees clockwise from true north
+	DistanceKm          float64 // Great circle distance to the Kaaba
+	HasMagnetic         bool    // Whether MagneticBearing and MagneticDeclination were calculated
+	MagneticBearing     float64 // Bearing in degrees clockwise from magnetic north
+	MagneticDeclination float64 // Degrees magnetic north is east of true north
+	SunOverKaaba        [2]time.Time
+	SunTowardsQibla     []time.Time // Times of the day the sun's azimuth is the qibla bearing
+	SunAwayFromQibla    []time.Time // Times of the day shadows point towards the qibla
+}
+
+/*
+Qibla returns the qibla bearing and distance from the coordinates, the two times of the year of day when the sun is
+directly over the Kaaba, and the times on day when the sun lines up with the qibla.  Times are in the location of day.
+When magnetic is given and covers day, the bearing is also returned relative to magnetic north on day.  Otherwise
+HasMagnetic is false.
+*/
+func (p PrayerCalendarInputCoordinates) Qibla(day time.Time, magnetic *MagneticModel) (*QiblaOutput, error) {
+	latitude, longitude := float64(p.Latitude), float64(p.Longitude)
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, fmt.Errorf("coordinates are out of range: %v", p)
+	}
+
+	output := &QiblaOutput{
+		Bearing:    qiblaBearing(latitude, longitude),
+		DistanceKm: greatCircleDistanceKm(latitude, longitude, kaabaLatitude, kaabaLongitude),
+	}
+
+	if magnetic != nil && magnetic.Covers(day) {
+		declination, err := magnetic.Declination(latitude, longitude, day)
+		if err != nil {
+			return nil, err
+		}
+		output.HasMagnetic = true
+		output.MagneticDeclination = declination
+		output.MagneticBearing = fixAngle(output.Bearing - declination)
+	}
+
+	sunOverKaaba, err := SunOverKaaba(day.Year())
+	if err != nil {
+		return nil, err
+	}
+	output.SunOverKaaba[0] = sunOverKaaba[0].In(day.Location())
+	output.SunOverKaaba[1] = sunOverKaaba[1].In(day.Location())
+
+	output.SunTowardsQibla = sunAzimuthTimes(day, latitude, longitude, output.Bearing)
+	output.SunAwayFromQibla = sunAzimuthTimes(day, latitude, longitude, fixAngle(output.Bearing+180))
+	return output, nil
+}
+
+/*
+SunOverKaaba returns the two times in year when the sun passes directly over the Kaaba, around the end of May and
+the middle of July.  At those moments every shadow in daylight points away from the qibla.
+*/
+func SunOverKaaba(year int) ([2]time.Time, error) {
+	var times [2]time.Time
+	found := 0
+	previous := math.Inf(1)
+	falling := true
+	for day := time.Date(year, time.May, 1, 0, 0, 0, 0, time.UTC); day.Month() < time.August; day = day.AddDate(0, 0, 1) {
+		noon := solarNoon(day, kaabaLongitude)
+		declination, _ := sunPosition(julianDate(noon))
+		difference := math.Abs(declination - kaabaLatitude)
+		if difference > previous && falling {
+			times[found] = solarNoon(day.AddDate(0, 0, -1), kaabaLongitude).Truncate(time.Second)
+			found++
+			if found == len(times) {
+				return times, nil
+			}
+		}
+		falling = difference <= previous
+		previous = difference
+	}
+	return times, fmt.Errorf("unable to find when the sun is over the kaaba in %d", year)
+}
+
+// qiblaBearing returns the great circle initial bearing from the coordinates to the Kaaba
+func qiblaBearing(latitude float64, longitude float64) float64 {
+	longitudeDifference := kaabaLongitude - longitude
+	return fixAngle(darctan2(
+		dsin(longitudeDifference)*dcos(kaabaLatitude),
+		dcos(latitude)*dsin(kaabaLatitude)-dsin(latitude)*dcos(kaabaLatitude)*dcos(longitudeDifference),
+	))
+}
+
+// greatCircleDistanceKm returns the haversine distance between two coordinates
+func greatCircleDistanceKm(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64) float64 {
+	latitudeHalf := dsin((latitude2 - latitude1) / 2)
+	longitudeHalf := dsin((longitude2 - longitude1) / 2)
+	a := latitudeHalf*latitudeHalf + dcos(latitude1)*dcos(latitude2)*longitudeHalf*longitudeHalf
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// sunAzimuthTimes returns the daylight times on the calendar day of day when the sun's azimuth crosses bearing
+func sunAzimuthTimes(day time.Time, latitude float64, longitude float64, bearing float64) []time.Time {
+	var times []time.Time
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	offset := func(t time.Time) (float64, bool) {
+		altitude, azimuth := sunHorizontal(t, latitude, longitude)
+		return math.Remainder(azimuth-bearing, 360), altitude > 0
+	}
+
+	previous := start
+	previousOffset, _ := offset(previous)
+	for current := start.Add(time.Minute); !current.After(end); current = current.Add(time.Minute) {
+		currentOffset, daylight := offset(current)
+		// A sign change far from zero is the azimuth wrapping around the opposite bearing, not a crossing
+		if previousOffset*currentOffset <= 0 && math.Abs(previousOffset-currentOffset) < 90 && daylight {
+			low, high := previous, current
+			for high.Sub(low) > time.Second {
+				middle := low.Add(high.Sub(low) / 2)
+				middleOffset, _ := offset(middle)
+				if middleOffset*previousOffset <= 0 {
+					high = middle
+				} else {
+					low = middle
+				}
+			}
+			times = append(times, low.Truncate(time.Second))
+		}
+		previous, previousOffset = current, currentOffset
+	}
+	return times
+}
@@ -0,0 +1,99 @@
+package schedule_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func TestQibla(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for Europe/London: %s", err)
+	}
+
+	coordinates := psched.PrayerCalendarInputCoordinates{Latitude: 51.5074, Longitude: -0.1278}
+	qibla, err := coordinates.Qibla(time.Date(2023, time.June, 1, 0, 0, 0, 0, london), nil)
+	if err != nil {
+		t.Fatalf("unable to calculate qibla: %s", err)
+	}
+
+	if math.Abs(qibla.Bearing-118.99) > 0.05 {
+		t.Errorf("london qibla bearing is %.2f, want 118.99", qibla.Bearing)
+	}
+	if math.Abs(qibla.DistanceKm-4794) > 5 {
+		t.Errorf("london distance to the kaaba is %.0f km, want 4794 km", qibla.DistanceKm)
+	}
+	if qibla.HasMagnetic {
+		t.Error("magnetic bearing was calculated without a magnetic model")
+	}
+
+	// The sun is over the kaaba at 12:18 on the 28th of May and 12:27 on the 16th of July Makkah time
+	wantOverKaaba := []string{"2023-05-28 10:18", "2023-07-16 10:27"}
+	for i, want := range wantOverKaaba {
+		got := qibla.SunOverKaaba[i].Add(30 * time.Second).Format("2006-01-02 15:04")
+		if got != want {
+			t.Errorf("sun over the kaaba %d is %s, want %s", i+1, got, want)
+		}
+	}
+
+	if len(qibla.SunTowardsQibla) != 1 || len(qibla.SunAwayFromQibla) != 1 {
+		t.Fatalf("expected one sun alignment each way, got %v and %v", qibla.SunTowardsQibla, qibla.SunAwayFromQibla)
+	}
+	for _, aligned := range []time.Time{qibla.SunTowardsQibla[0], qibla.SunAwayFromQibla[0]} {
+		if aligned.Location() != london || aligned.Day() != 1 {
+			t.Errorf("sun alignment %s is not on the requested day in london", aligned)
+		}
+	}
+	if qibla.SunTowardsQibla[0].Hour() != 10 {
+		t.Errorf("sun points towards the qibla at %s, want around 10:20", qibla.SunTowardsQibla[0].Format("15:04"))
+	}
+}
+
+func TestQiblaMagnetic(t *testing.T) {
+	model, err := psched.DefaultMagneticModel()
+	if err != nil {
+		t.Fatalf("unable to load the embedded magnetic model: %s", err)
+	}
+
+	coordinates := psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105}
+	qibla, err := coordinates.Qibla(time.Date(2022, time.October, 22, 0, 0, 0, 0, time.UTC), model)
+	if err != nil {
+		t.Fatalf("unable to calculate qibla: %s", err)
+	}
+
+	if !qibla.HasMagnetic {
+		t.Fatal("magnetic bearing was not calculated")
+	}
+	if math.Abs(qibla.MagneticDeclination-11.6) > 0.2 {
+		t.Errorf("los angeles declination is %.2f, want about 11.6", qibla.MagneticDeclination)
+	}
+	if math.Abs(qibla.MagneticBearing-(qibla.Bearing-qibla.MagneticDeclination)) > 1e-9 {
+		t.Errorf("magnetic bearing %.2f does not match true bearing %.2f less declination %.2f", qibla.MagneticBearing, qibla.Bearing, qibla.MagneticDeclination)
+	}
+}
+
+func TestQiblaMagneticOutsideModel(t *testing.T) {
+	model, err := psched.DefaultMagneticModel()
+	if err != nil {
+		t.Fatalf("unable to load the embedded magnetic model: %s", err)
+	}
+
+	day := time.Date(int(model.Epoch)+6, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if model.Covers(day) {
+		t.Fatalf("%s covers %s", model.Name, day.Format("2006-01-02"))
+	}
+	coordinates := psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105}
+	qibla, err := coordinates.Qibla(day, model)
+	if err != nil {
+		t.Fatalf("qibla on a day the magnetic model does not cover returned an error: %s", err)
+	}
+	if qibla.HasMagnetic {
+		t.Error("magnetic bearing was calculated outside of the model lifespan")
+	}
+	if math.Abs(qibla.Bearing-24) > 1 {
+		t.Errorf("los angeles qibla bearing is %.2f, want about 24", qibla.Bearing)
+	}
+}
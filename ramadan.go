@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SuhoorEnd selects the time at which suhoor ends in a Ramadan timetable
+type SuhoorEnd int
+
+const (
+	// SuhoorEndsAtImsak ends suhoor at Imsak, a precaution shortly before Fajr
+	SuhoorEndsAtImsak SuhoorEnd = iota
+	// SuhoorEndsAtFajr ends suhoor at the Fajr adhan
+	SuhoorEndsAtFajr
+)
+
+// RamadanDay contains the fasting times of a single day of Ramadan.
+// LastTenNights and OddNight describe the night preceding the fast, which begins at Maghrib of the previous day.
+type RamadanDay struct {
+	Hijri           HijriDate
+	Date            time.Time
+	SuhoorEnd       string
+	Fajr            string
+	Iftar           string // Maghrib
+	FastingDuration time.Duration
+	LastTenNights   bool
+	OddNight        bool // One of the odd nights of the last ten, on which Laylat al-Qadr is sought
+}
+
+// RamadanTimetable contains every day of Ramadan of a Hijri year
+type RamadanTimetable struct {
+	HijriYear int
+	Days      []RamadanDay
+}
+
+/*
+RamadanTimetable returns the fasting times of every day of Ramadan in hijriYear at the customer location.
+The location is resolved once and the Gregorian months spanned by Ramadan are each looked up with it.
+*/
+func (c *CustomerLocationInput) RamadanTimetable(hijriYear int, suhoorEnd SuhoorEnd) (*RamadanTimetable, error) {
+	monthLength, err := HijriMonthLength(hijriYear, 9, c.HijriCalendar)
+	if err != nil {
+		return nil, err
+	}
+
+	location := c.CustTime.Location()
+	firstDay, err := HijriDate{Year: hijriYear, Month: 9, Day: 1}.ToGregorian(c.HijriCalendar, c.HijriAdjustment, location)
+	if err != nil {
+		return nil, err
+	}
+
+	ramadanInput := *c
+	ramadanInput.CustTime = firstDay
+	input, err := ramadanInput.resolvePCalInput(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	provider := c.provider()
+
+	output := &RamadanTimetable{HijriYear: hijriYear}
+	months := make(map[string]*PCalOutput)
+	for day := 1; day <= monthLength; day++ {
+		date := firstDay.AddDate(0, 0, day-1)
+		monthKey := date.Format("01-2006")
+		month, ok := months[monthKey]
+		if !ok {
+			monthInput := *input
+			monthInput.CustTime = date
+			month, err = provider.MonthlyPrayers(context.Background(), &monthInput)
+			if err != nil {
+				return nil, fmt.Errorf("unable to look up prayer calendar of %s: %s", monthKey, err)
+			}
+			months[monthKey] = month
+		}
+
+		ramadanDay, err := newRamadanDay(month, date, suhoorEnd)
+		if err != nil {
+			return nil, err
+		}
+		ramadanDay.Hijri = HijriDate{Year: hijriYear, Month: 9, Day: day}
+		ramadanDay.LastTenNights = day >= 21
+		ramadanDay.OddNight = day >= 21 && day%2 == 1
+		output.Days = append(output.Days, *ramadanDay)
+	}
+
+	return output, nil
+}
+
+// newRamadanDay returns the fasting times of date from the monthly prayer data containing it
+func newRamadanDay(month *PCalOutput, date time.Time, suhoorEnd SuhoorEnd) (*RamadanDay, error) {
+	dateKey := date.Format("02-01-2006")
+	for i := range month.Data {
+		if month.Data[i].Date.Gregorian.Date != dateKey {
+			continue
+		}
+
+		timings := month.Data[i].Timings
+		day, err := month.Data[i].Day()
+		if err != nil {
+			return nil, err
+		}
+
+		output := &RamadanDay{Date: day, Fajr: timings.Fajr, Iftar: timings.Maghrib, SuhoorEnd: timings.Fajr}
+		if suhoorEnd == SuhoorEndsAtImsak {
+			if timings.Imsak == "" {
+				return nil, fmt.Errorf("no imsak time was given for %s", dateKey)
+			}
+			output.SuhoorEnd = timings.Imsak
+		}
+
+		fastStart, err := PrayerTimeOnDay(day, output.SuhoorEnd)
+		if err != nil {
+			return nil, err
+		}
+		fastEnd, err := PrayerTimeOnDay(day, output.Iftar)
+		if err != nil {
+			return nil, err
+		}
+		output.FastingDuration = fastEnd.Sub(fastStart)
+		return output, nil
+	}
+	return nil, fmt.Errorf("no prayer timings were found for %s", dateKey)
+}
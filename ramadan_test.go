@@ -0,0 +1,91 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func TestRamadanTimetable(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for America/Los_Angeles: %s", err)
+	}
+
+	provider := &testMonthProvider{}
+	customerInput := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		CustTime:    time.Date(2023, time.January, 1, 0, 0, 0, 0, losAngeles),
+		Provider:    provider,
+	}
+
+	timetable, err := customerInput.RamadanTimetable(1444, psched.SuhoorEndsAtImsak)
+	if err != nil {
+		t.Fatalf("unable to build ramadan timetable: %s", err)
+	}
+
+	// Ramadan 1444 is the 23rd of March to the 20th of April 2023
+	if len(timetable.Days) != 29 {
+		t.Fatalf("ramadan 1444 has %d days, want 29", len(timetable.Days))
+	}
+	if provider.Calls() != 2 {
+		t.Errorf("ramadan spanning two months made %d calendar lookups, want 2", provider.Calls())
+	}
+
+	first := timetable.Days[0]
+	if first.Date.Format("2006-01-02") != "2023-03-23" || first.Hijri != (psched.HijriDate{Year: 1444, Month: 9, Day: 1}) {
+		t.Errorf("first day of ramadan is %s %s, want 2023-03-23 1 Ramadan 1444 AH", first.Date.Format("2006-01-02"), first.Hijri)
+	}
+	if first.SuhoorEnd != "05:22 (PDT)" || first.Iftar != "18:23 (PDT)" {
+		t.Errorf("first day suhoor and iftar are %s and %s, want 05:22 (PDT) and 18:23 (PDT)", first.SuhoorEnd, first.Iftar)
+	}
+	if first.FastingDuration != 13*time.Hour+time.Minute {
+		t.Errorf("first day fasting duration is %s, want 13h1m", first.FastingDuration)
+	}
+
+	last := timetable.Days[28]
+	if last.Date.Format("2006-01-02") != "2023-04-20" {
+		t.Errorf("last day of ramadan is %s, want 2023-04-20", last.Date.Format("2006-01-02"))
+	}
+
+	for _, day := range timetable.Days {
+		if day.LastTenNights != (day.Hijri.Day >= 21) {
+			t.Errorf("%s last ten nights is %v", day.Hijri, day.LastTenNights)
+		}
+		if day.OddNight != (day.Hijri.Day >= 21 && day.Hijri.Day%2 == 1) {
+			t.Errorf("%s odd night is %v", day.Hijri, day.OddNight)
+		}
+	}
+
+	fajrTimetable, err := customerInput.RamadanTimetable(1444, psched.SuhoorEndsAtFajr)
+	if err != nil {
+		t.Fatalf("unable to build ramadan timetable: %s", err)
+	}
+	if fajrTimetable.Days[0].SuhoorEnd != fajrTimetable.Days[0].Fajr || fajrTimetable.Days[0].FastingDuration != 12*time.Hour+51*time.Minute {
+		t.Errorf("suhoor ending at fajr is %s lasting %s", fajrTimetable.Days[0].SuhoorEnd, fajrTimetable.Days[0].FastingDuration)
+	}
+}
+
+func TestRamadanTimetableGeocodesOnce(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for America/Los_Angeles: %s", err)
+	}
+
+	geocoder := &testGeocoder{}
+	customerInput := &psched.CustomerLocationInput{
+		CountryCode: "US",
+		PostalCode:  "90210",
+		CustTime:    time.Date(2023, time.January, 1, 0, 0, 0, 0, losAngeles),
+		Provider:    &testMonthProvider{},
+		Geocoder:    geocoder,
+	}
+
+	if _, err := customerInput.RamadanTimetable(1444, psched.SuhoorEndsAtImsak); err != nil {
+		t.Fatalf("unable to build ramadan timetable: %s", err)
+	}
+	if geocoder.calls != 1 {
+		t.Errorf("ramadan spanning two months geocoded the postal code %d times, want once", geocoder.calls)
+	}
+}
@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultRangeWorkers is the number of months looked up at once by PrayerCalendarRange
+const defaultRangeWorkers = 4
+
+// PCalRangeOutput contains the prayer timings of every day of a date range in date order
+type PCalRangeOutput struct {
+	Days      []PCalDay
+	Latitude  float32
+	Longitude float32
+}
+
+/*
+PrayerCalendarRange returns the prayer timings of every day from start to end inclusive.
+Every month within the range is looked up with at most workers concurrent provider requests.  A range covering a
+whole Gregorian year is requested in one call when the provider implements YearProvider.
+*/
+func (c *CustomerLocationInput) PrayerCalendarRange(start time.Time, end time.Time, workers int) (*PCalRangeOutput, error) {
+	return c.PrayerCalendarRangeContext(context.Background(), start, end, workers)
+}
+
+// PrayerCalendarRangeContext is PrayerCalendarRange with the location and month lookups given up when ctx is done
+func (c *CustomerLocationInput) PrayerCalendarRangeContext(ctx context.Context, start time.Time, end time.Time, workers int) (*PCalRangeOutput, error) {
+	firstDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	lastDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+	if lastDay.Before(firstDay) {
+		return nil, fmt.Errorf("range end %s is before range start %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	if workers <= 0 {
+		workers = defaultRangeWorkers
+	}
+
+	rangeInput := *c
+	rangeInput.CustTime = firstDay
+	input, err := rangeInput.resolvePCalInput(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	months, err := c.rangeMonths(ctx, input, firstDay, lastDay, workers)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &PCalRangeOutput{Latitude: input.Latitude, Longitude: input.Longitude}
+	first, last := firstDay.Format("20060102"), lastDay.Format("20060102")
+	for _, month := range months {
+		if err := month.SetHijriDates(c.HijriCalendar, c.HijriAdjustment); err != nil {
+			return nil, fmt.Errorf("unable to set hijri dates: %s", err)
+		}
+		for _, day := range month.Data {
+			date, err := day.Day()
+			if err != nil {
+				return nil, err
+			}
+			if dateKey := date.Format("20060102"); dateKey >= first && dateKey <= last {
+				output.Days = append(output.Days, day)
+			}
+		}
+	}
+	return output, nil
+}
+
+// rangeMonths looks up every month from firstDay to lastDay in month order
+func (c *CustomerLocationInput) rangeMonths(ctx context.Context, input *PCalInput, firstDay time.Time, lastDay time.Time, workers int) ([]*PCalOutput, error) {
+	provider := c.provider()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wholeYear := firstDay.Year() == lastDay.Year() &&
+		firstDay.Month() == time.January && firstDay.Day() == 1 &&
+		lastDay.Month() == time.December && lastDay.Day() == 31
+	if yearProvider, ok := provider.(YearProvider); ok && wholeYear {
+		return yearProvider.YearlyPrayers(ctx, input)
+	}
+
+	var monthStarts []time.Time
+	for month := time.Date(firstDay.Year(), firstDay.Month(), 1, 0, 0, 0, 0, firstDay.Location()); !month.After(lastDay); month = month.AddDate(0, 1, 0) {
+		monthStarts = append(monthStarts, month)
+	}
+
+	months := make([]*PCalOutput, len(monthStarts))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var failOnce sync.Once
+	var failure error
+	for worker := 0; worker < workers && worker < len(monthStarts); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				monthInput := *input
+				monthInput.CustTime = monthStarts[i]
+				month, err := provider.MonthlyPrayers(ctx, &monthInput)
+				if err != nil {
+					// Only the first failure is reported, the rest are likely caused by the cancellation
+					failOnce.Do(func() {
+						failure = fmt.Errorf("unable to look up prayer calendar of %s: %s", monthStarts[i].Format("01-2006"), err)
+						cancel()
+					})
+					continue
+				}
+				months[i] = month
+			}
+		}()
+	}
+
+	for i := range monthStarts {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if failure != nil {
+		return nil, failure
+	}
+	return months, nil
+}
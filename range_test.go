@@ -0,0 +1,151 @@
+package schedule_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// concurrencyMonthProvider records the most month lookups running at once
+type concurrencyMonthProvider struct {
+	testMonthProvider
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (p *concurrencyMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.mu.Lock()
+	p.running++
+	if p.running > p.peak {
+		p.peak = p.running
+	}
+	p.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+	defer func() {
+		p.mu.Lock()
+		p.running--
+		p.mu.Unlock()
+	}()
+	return p.testMonthProvider.MonthlyPrayers(ctx, input)
+}
+
+// yearMonthProvider is a testMonthProvider which can also return a whole year at once
+type yearMonthProvider struct {
+	testMonthProvider
+	yearCalls int
+}
+
+func (p *yearMonthProvider) YearlyPrayers(ctx context.Context, input *psched.PCalInput) ([]*psched.PCalOutput, error) {
+	p.yearCalls++
+	var months []*psched.PCalOutput
+	for month := 1; month <= 12; month++ {
+		monthInput := *input
+		monthInput.CustTime = time.Date(input.CustTime.Year(), time.Month(month), 1, 0, 0, 0, 0, input.CustTime.Location())
+		output, err := p.testMonthProvider.MonthlyPrayers(ctx, &monthInput)
+		if err != nil {
+			return nil, err
+		}
+		months = append(months, output)
+	}
+	return months, nil
+}
+
+func TestPrayerCalendarRange(t *testing.T) {
+	provider := &concurrencyMonthProvider{}
+	customerInput := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    provider,
+	}
+
+	start := time.Date(2023, time.January, 25, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, time.June, 5, 0, 0, 0, 0, time.UTC)
+	output, err := customerInput.PrayerCalendarRange(start, end, 2)
+	if err != nil {
+		t.Fatalf("unable to look up prayer calendar range: %s", err)
+	}
+
+	if len(output.Days) != 132 {
+		t.Fatalf("range has %d days, want 132", len(output.Days))
+	}
+	for i, day := range output.Days {
+		date, err := day.Day()
+		if err != nil {
+			t.Fatalf("unable to read day date: %s", err)
+		}
+		if want := start.AddDate(0, 0, i); !date.Equal(want) {
+			t.Fatalf("day %d is %s, want %s", i, date.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+		if day.Hijri.IsZero() {
+			t.Errorf("day %s has no hijri date", date.Format("2006-01-02"))
+		}
+	}
+
+	if provider.Calls() != 6 {
+		t.Errorf("range made %d month lookups, want 6", provider.Calls())
+	}
+	if provider.peak > 2 {
+		t.Errorf("range ran %d month lookups at once, want at most 2", provider.peak)
+	}
+
+	if _, err := customerInput.PrayerCalendarRange(end, start, 2); err == nil {
+		t.Error("range ending before it starts did not return an error")
+	}
+}
+
+func TestPrayerCalendarRangeWholeYear(t *testing.T) {
+	provider := &yearMonthProvider{}
+	customerInput := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    provider,
+	}
+
+	output, err := customerInput.PrayerCalendarRange(
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+		0,
+	)
+	if err != nil {
+		t.Fatalf("unable to look up prayer calendar year: %s", err)
+	}
+
+	if len(output.Days) != 366 {
+		t.Errorf("2024 has %d days, want 366", len(output.Days))
+	}
+	if provider.yearCalls != 1 || provider.Calls() != 12 {
+		t.Errorf("whole year made %d year lookups, want 1", provider.yearCalls)
+	}
+}
+
+func TestAladhanProviderYearlyPrayers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("annual") != "true" || r.URL.Query().Get("year") != "2024" {
+			t.Errorf("unexpected aladhan annual request: %s", r.URL)
+		}
+		var months []string
+		for month := 1; month <= 12; month++ {
+			months = append(months, fmt.Sprintf(`"%d":[{"timings":{"Fajr":"05:00"},"date":{"gregorian":{"date":"01-%02d-2024"}}}]`, month, month))
+		}
+		fmt.Fprintf(w, `{"code":200,"status":"OK","data":{%s}}`, strings.Join(months, ","))
+	}))
+	defer server.Close()
+
+	provider := &psched.AladhanProvider{BaseURL: server.URL}
+	months, err := provider.YearlyPrayers(context.Background(), &psched.PCalInput{
+		CustTime: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("aladhan annual calendar returned an error: %s", err)
+	}
+	if len(months) != 12 || months[11].Data[0].Date.Gregorian.Date != "01-12-2024" {
+		t.Errorf("aladhan annual calendar was not split into months: %+v", months)
+	}
+}
@@ -0,0 +1,306 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// schedulerPrayers are the prayers with events when a Scheduler has no Prayers
+var schedulerPrayers = []string{"Fajr", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// Defaults of a Scheduler
+const (
+	DefaultSchedulerGrace        = time.Minute
+	DefaultSchedulerPollInterval = time.Minute
+)
+
+// SchedulerEvent is a prayer beginning, or a lead time before a prayer begins, at a location of a Scheduler
+type SchedulerEvent struct {
+	Location    string                         // Name the location was added with
+	Coordinates PrayerCalendarInputCoordinates // Coordinates of the location, looked up when it was added by postal code
+	Prayer      string                         // Name of the prayer, such as "Fajr"
+	Begins      time.Time                      // When the prayer begins, in the timezone of the location
+	Lead        time.Duration                  // How long before the prayer begins the event fires.  Zero when the prayer begins
+	Hijri       HijriDate                      // Hijri date of the day of the prayer, in the Hijri calendar of the location
+}
+
+// At returns when the event fires
+func (e SchedulerEvent) At() time.Time {
+	return e.Begins.Add(-e.Lead)
+}
+
+// SchedulerCallback is called with each event of a Scheduler
+type SchedulerCallback func(event SchedulerEvent)
+
+/*
+Scheduler fires callbacks the moment each prayer begins at one or more locations, and at lead times before.
+Events are found from the timings of the days around the clock, looked up month by month from the provider of
+each location, so Run carries on across months.  Events are compared as instants rather than clock times, so lead
+times stay exact across daylight saving changes.
+
+The clock is read again at least every PollInterval.  When it jumps forward, such as after the machine wakes from
+sleep, events missed by up to Grace still fire and older events are skipped.  When it jumps back, events which
+already fired do not fire again.
+*/
+type Scheduler struct {
+	Clock        Clock                            // Defaults to SystemClock
+	LeadTimes    []time.Duration                  // Events also fire this long before each prayer begins, such as 15 minutes
+	Prayers      []string                         // Prayers with events.  Defaults to Fajr, Dhuhr, Asr, Maghrib and Isha
+	Grace        time.Duration                    // How late an event may fire.  Defaults to DefaultSchedulerGrace
+	PollInterval time.Duration                    // Longest wait between reads of the clock.  Defaults to DefaultSchedulerPollInterval
+	OnError      func(location string, err error) // Called when the timings of a location cannot be looked up, which is retried at the next poll
+
+	mu        sync.Mutex
+	locations []*schedulerLocation
+	callbacks []SchedulerCallback
+	fired     map[schedulerEventKey]time.Time // Events which fired, with when they fire
+}
+
+// schedulerLocation is a location added to a Scheduler.  Its timings are looked up under its own mutex rather than
+// that of the Scheduler, so a slow provider does not hold up AddLocation, OnEvent or the other locations
+type schedulerLocation struct {
+	name     string
+	customer *CustomerLocationInput
+	mu       sync.Mutex
+	days     *adjacentDays
+}
+
+// schedulerEventKey identifies an event independently of the timezone it is in
+type schedulerEventKey struct {
+	location string
+	prayer   string
+	begins   int64
+	lead     time.Duration
+}
+
+// AddLocation adds a location with a unique name to the scheduler.  Locations may be added while Run is running
+func (s *Scheduler) AddLocation(name string, customer *CustomerLocationInput) error {
+	if customer == nil {
+		return fmt.Errorf("customer location of %s is required", name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, location := range s.locations {
+		if location.name == name {
+			return fmt.Errorf("location %s was already added", name)
+		}
+	}
+	s.locations = append(s.locations, &schedulerLocation{name: name, customer: customer})
+	return nil
+}
+
+/*
+OnEvent registers a callback called with every event.  Callbacks are called one at a time in the goroutine of Run,
+in the order the events fire, so a callback which takes long delays the events after it.
+*/
+func (s *Scheduler) OnEvent(callback SchedulerCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// Upcoming returns the events of every location which fire from start to end inclusive, in the order they fire
+func (s *Scheduler) Upcoming(start time.Time, end time.Time) ([]SchedulerEvent, error) {
+	s.mu.Lock()
+	locations := s.locations
+	s.mu.Unlock()
+
+	var events []SchedulerEvent
+	for _, location := range locations {
+		locationEvents, err := s.locationEvents(context.Background(), location, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("unable to schedule %s: %s", location.name, err)
+		}
+		events = append(events, locationEvents...)
+	}
+	sortSchedulerEvents(events)
+	return events, nil
+}
+
+// Run fires the events of every location until ctx is done, and then returns the error of ctx.  Lookups of timings
+// are given up when ctx is done
+func (s *Scheduler) Run(ctx context.Context) error {
+	clock := clockOrSystem(s.Clock)
+	for {
+		now := clock.Now()
+		due, next, errs := s.due(ctx, now)
+		for name, err := range errs {
+			if s.OnError != nil {
+				s.OnError(name, err)
+			}
+		}
+		s.mu.Lock()
+		callbacks := s.callbacks
+		s.mu.Unlock()
+		for _, event := range due {
+			for _, callback := range callbacks {
+				callback(event)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(next.Sub(now)):
+		}
+	}
+}
+
+/*
+due returns the events which are due at now and have not fired, when the clock should be read again, and the errors
+of the locations whose timings could not be looked up
+*/
+func (s *Scheduler) due(ctx context.Context, now time.Time) ([]SchedulerEvent, time.Time, map[string]error) {
+	grace, pollInterval := s.Grace, s.PollInterval
+	if grace <= 0 {
+		grace = DefaultSchedulerGrace
+	}
+	if pollInterval <= 0 {
+		pollInterval = DefaultSchedulerPollInterval
+	}
+	next := now.Add(pollInterval)
+
+	s.mu.Lock()
+	locations := s.locations
+	s.mu.Unlock()
+
+	// The timings are looked up before the Scheduler is locked to mark the events which fired
+	events := make([][]SchedulerEvent, len(locations))
+	errs := make(map[string]error)
+	for i, location := range locations {
+		locationEvents, err := s.locationEvents(ctx, location, now.Add(-grace), next)
+		if err != nil {
+			errs[location.name] = err
+			continue
+		}
+		events[i] = locationEvents
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fired == nil {
+		s.fired = make(map[schedulerEventKey]time.Time)
+	}
+	// Fired events are kept for a day, so they do not fire again when the clock jumps back
+	for key, at := range s.fired {
+		if now.Sub(at) > 24*time.Hour+grace {
+			delete(s.fired, key)
+		}
+	}
+
+	var due []SchedulerEvent
+	for _, locationEvents := range events {
+		for _, event := range locationEvents {
+			at := event.At()
+			if at.After(now) {
+				if at.Before(next) {
+					next = at
+				}
+				continue
+			}
+			key := schedulerEventKey{location: event.Location, prayer: event.Prayer, begins: event.Begins.Unix(), lead: event.Lead}
+			if _, ok := s.fired[key]; ok {
+				continue
+			}
+			s.fired[key] = at
+			due = append(due, event)
+		}
+	}
+	sortSchedulerEvents(due)
+	return due, next, errs
+}
+
+// locationEvents returns the events of location which fire from start to end inclusive, looking up its timings with ctx
+func (s *Scheduler) locationEvents(ctx context.Context, location *schedulerLocation, start time.Time, end time.Time) ([]SchedulerEvent, error) {
+	location.mu.Lock()
+	defer location.mu.Unlock()
+
+	if location.days == nil {
+		input, err := location.customer.resolvePCalInput(ctx)
+		if err != nil {
+			return nil, err
+		}
+		location.days = &adjacentDays{provider: location.customer.provider(), input: input, months: make(map[string]*PCalOutput)}
+	}
+	location.days.ctx = ctx
+	if _, err := location.days.local(start); err != nil {
+		return nil, err
+	}
+
+	prayers := s.Prayers
+	if len(prayers) == 0 {
+		prayers = schedulerPrayers
+	}
+	leads := append([]time.Duration{0}, s.LeadTimes...)
+	var longestLead time.Duration
+	for _, lead := range leads {
+		if lead > longestLead {
+			longestLead = lead
+		}
+	}
+
+	// Days are stepped at noon, which is never skipped or repeated by a daylight saving change
+	first, last := start.In(location.days.location), end.Add(longestLead).In(location.days.location)
+	first = time.Date(first.Year(), first.Month(), first.Day(), 12, 0, 0, 0, first.Location())
+	last = time.Date(last.Year(), last.Month(), last.Day(), 12, 0, 0, 0, last.Location())
+
+	// Only the months of the days looked up are kept
+	for monthKey := range location.days.months {
+		if monthKey != first.Format("01-2006") && monthKey != last.Format("01-2006") {
+			delete(location.days.months, monthKey)
+		}
+	}
+
+	var events []SchedulerEvent
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		pcalDay, err := location.days.day(day)
+		if err != nil {
+			return nil, err
+		}
+		hijri, err := ToHijri(day, location.customer.HijriCalendar, location.customer.HijriAdjustment)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set hijri date of %s: %s", pcalDay.Date.Gregorian.Date, err)
+		}
+		for _, prayer := range prayers {
+			timing := pcalDay.Timings.timing(prayer)
+			if timing == "" {
+				continue
+			}
+			begins, err := PrayerTimeOnDay(day, timing)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s timing of %s: %s", prayer, pcalDay.Date.Gregorian.Date, err)
+			}
+			for _, lead := range leads {
+				event := SchedulerEvent{
+					Location:    location.name,
+					Coordinates: PrayerCalendarInputCoordinates{Latitude: location.days.input.Latitude, Longitude: location.days.input.Longitude},
+					Prayer:      prayer,
+					Begins:      begins,
+					Lead:        lead,
+					Hijri:       hijri,
+				}
+				if at := event.At(); !at.Before(start) && !at.After(end) {
+					events = append(events, event)
+				}
+			}
+		}
+	}
+	return events, nil
+}
+
+// sortSchedulerEvents sorts events in the order they fire, and then by location and prayer
+func sortSchedulerEvents(events []SchedulerEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].At().Equal(events[j].At()) {
+			return events[i].At().Before(events[j].At())
+		}
+		if events[i].Location != events[j].Location {
+			return events[i].Location < events[j].Location
+		}
+		return events[i].Begins.Before(events[j].Begins)
+	})
+}
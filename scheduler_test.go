@@ -0,0 +1,215 @@
+package schedule_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func schedulerTestCustomer(t *testing.T) (*psched.CustomerLocationInput, *time.Location) {
+	t.Helper()
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unable to load timezone data for America/Los_Angeles: %s", err)
+	}
+	return &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    &testMonthProvider{location: losAngeles},
+	}, losAngeles
+}
+
+func schedulerEventNames(events []psched.SchedulerEvent) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = fmt.Sprintf("%s %s -%s at %s", event.Location, event.Prayer, event.Lead, event.At().Format("2006-01-02 15:04 MST"))
+	}
+	return names
+}
+
+func TestSchedulerUpcomingAcrossMonths(t *testing.T) {
+	customer, losAngeles := schedulerTestCustomer(t)
+	scheduler := &psched.Scheduler{LeadTimes: []time.Duration{15 * time.Minute}}
+	if err := scheduler.AddLocation("home", customer); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.AddLocation("home", customer); err == nil {
+		t.Error("added a second location named home")
+	}
+
+	events, err := scheduler.Upcoming(time.Date(2022, time.October, 31, 19, 0, 0, 0, losAngeles), time.Date(2022, time.November, 1, 6, 0, 0, 0, losAngeles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"home Isha -15m0s at 2022-10-31 19:15 PDT",
+		"home Isha -0s at 2022-10-31 19:30 PDT",
+		"home Fajr -15m0s at 2022-11-01 04:55 PDT",
+		"home Fajr -0s at 2022-11-01 05:10 PDT",
+	}
+	if got := schedulerEventNames(events); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("upcoming events are %q, want %q", got, want)
+	}
+	if fajr := events[3]; fajr.Hijri.String() != "7 Rabi al-Thani 1444 AH" || fajr.Coordinates != customer.Coordinates {
+		t.Errorf("Fajr on the 1st of November is on %s at %+v", fajr.Hijri, fajr.Coordinates)
+	}
+}
+
+func TestSchedulerUpcomingAcrossDaylightSaving(t *testing.T) {
+	customer, losAngeles := schedulerTestCustomer(t)
+	scheduler := &psched.Scheduler{Prayers: []string{"Isha", "Fajr"}, LeadTimes: []time.Duration{6 * time.Hour}}
+	if err := scheduler.AddLocation("home", customer); err != nil {
+		t.Fatal(err)
+	}
+
+	// Daylight saving time ends at 02:00 on the 6th of November 2022 in Los Angeles
+	events, err := scheduler.Upcoming(time.Date(2022, time.November, 5, 19, 0, 0, 0, losAngeles), time.Date(2022, time.November, 6, 6, 0, 0, 0, losAngeles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"home Isha -0s at 2022-11-05 19:30 PDT",
+		"home Fajr -6h0m0s at 2022-11-06 00:15 PDT",
+		"home Fajr -0s at 2022-11-06 05:15 PST",
+	}
+	if got := schedulerEventNames(events); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("upcoming events are %q, want %q", got, want)
+	}
+	if between := events[2].Begins.Sub(events[0].Begins); between != 10*time.Hour+45*time.Minute {
+		t.Errorf("Fajr is %s after Isha, want 10h45m", between)
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	customer, losAngeles := schedulerTestCustomer(t)
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 14, 0, 0, losAngeles))
+	scheduler := &psched.Scheduler{Clock: clock, LeadTimes: []time.Duration{15 * time.Minute}}
+	for _, name := range []string{"home", "mosque"} {
+		if err := scheduler.AddLocation(name, customer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var fired []psched.SchedulerEvent
+	scheduler.OnEvent(func(event psched.SchedulerEvent) { fired = append(fired, event) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	// advance moves the clock forward and returns the events fired once the scheduler waits again
+	advance := func(d time.Duration) []string {
+		clock.BlockUntil(1)
+		clock.Advance(d)
+		clock.BlockUntil(1)
+		names := schedulerEventNames(fired)
+		fired = nil
+		return names
+	}
+
+	if names := advance(59 * time.Second); len(names) != 0 {
+		t.Errorf("fired %q before the Dhuhr lead", names)
+	}
+	want := []string{"home Dhuhr -15m0s at 2022-10-12 12:15 PDT", "mosque Dhuhr -15m0s at 2022-10-12 12:15 PDT"}
+	if names := advance(time.Second); fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("fired %q at 12:15, want %q", names, want)
+	}
+	// A clock jump back does not fire the Dhuhr lead again
+	clock.Set(time.Date(2022, time.October, 12, 12, 14, 30, 0, losAngeles))
+	if names := advance(time.Minute); len(names) != 0 {
+		t.Errorf("fired %q again after the clock jumped back", names)
+	}
+	// Events up to a minute late fire
+	want = []string{"home Dhuhr -0s at 2022-10-12 12:30 PDT", "mosque Dhuhr -0s at 2022-10-12 12:30 PDT"}
+	if names := advance(15 * time.Minute); fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("fired %q at 12:30:30, want %q", names, want)
+	}
+	// Events missed by a clock jump forward are skipped
+	clock.Set(time.Date(2022, time.October, 12, 15, 50, 0, 0, losAngeles))
+	if names := advance(time.Minute); len(names) != 0 {
+		t.Errorf("fired %q after the clock jumped forward", names)
+	}
+	clock.Set(time.Date(2022, time.October, 12, 17, 56, 0, 0, losAngeles))
+	want = []string{"home Maghrib -15m0s at 2022-10-12 17:57 PDT", "mosque Maghrib -15m0s at 2022-10-12 17:57 PDT"}
+	if names := advance(time.Minute); fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("fired %q at 17:57, want %q", names, want)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("run returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSchedulerRunReportsErrors(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 0, 0, 0, losAngeles))
+	errs := make(chan string, 1)
+	scheduler := &psched.Scheduler{Clock: clock, OnError: func(location string, err error) { errs <- location }}
+	customer := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Provider:    &failingMonthProvider{},
+	}
+	if err := scheduler.AddLocation("down", customer); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Run(ctx)
+	if location := <-errs; location != "down" {
+		t.Errorf("error was reported for %s", location)
+	}
+	// The location is retried after the poll interval
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	if location := <-errs; location != "down" {
+		t.Errorf("error was reported for %s", location)
+	}
+}
+
+// contextBlockingMonthProvider holds every lookup until its context is done
+type contextBlockingMonthProvider struct {
+	started chan struct{}
+}
+
+func (p *contextBlockingMonthProvider) MonthlyPrayers(ctx context.Context, input *psched.PCalInput) (*psched.PCalOutput, error) {
+	p.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestSchedulerRunSlowProvider(t *testing.T) {
+	customer, losAngeles := schedulerTestCustomer(t)
+	provider := &contextBlockingMonthProvider{started: make(chan struct{}, 1)}
+	scheduler := &psched.Scheduler{Clock: psched.NewFakeClock(time.Date(2022, time.October, 12, 12, 0, 0, 0, losAngeles))}
+	if err := scheduler.AddLocation("slow", &psched.CustomerLocationInput{Coordinates: customer.Coordinates, Provider: provider}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- scheduler.Run(ctx) }()
+	<-provider.started
+
+	// Adding a location and a callback does not wait for the lookup in flight
+	if err := scheduler.AddLocation("home", customer); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.OnEvent(func(event psched.SchedulerEvent) {})
+
+	// Cancelling Run gives up the lookup in flight
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("run returned %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not give up the lookup in flight")
+	}
+}
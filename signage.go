@@ -0,0 +1,420 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSignageUpdateInterval = time.Second
+	defaultSignageSlideDuration  = 10 * time.Second
+	// signageRetryMilliseconds is how long browsers wait before reconnecting to the event stream
+	signageRetryMilliseconds = 5000
+)
+
+// Views of a SignageState
+const (
+	SignageViewPrayers = "prayers" // Clock, next prayer countdown, today's timings and announcements
+	SignageViewIqamah  = "iqamah"  // Countdown to the iqamah of the current prayer
+)
+
+/*
+SignageHandler is an http.Handler serving a full-screen page for mosque screens with the current time, a countdown
+to the next prayer, today's adhan and iqamah times, and rotating announcements.  The page is updated every second
+with Server-Sent Events, and switches to an iqamah countdown between the adhan and iqamah of each prayer.  It serves
+the page at / of wherever it is mounted, such as with http.StripPrefix, the event stream at events and the current
+state as JSON at state.
+*/
+type SignageHandler struct {
+	Customer       *CustomerLocationInput // Location, calculation settings and provider of the timings
+	MosqueName     string
+	Iqamah         *IqamahRules
+	Slides         []SignageSlide // Announcements shown in turn below the timings
+	TwelveHour     bool
+	UpdateInterval time.Duration // How often a state is pushed to each screen.  Defaults to a second
+	Clock          Clock         // Defaults to SystemClock
+
+	mu   sync.Mutex
+	days *adjacentDays
+}
+
+// SignageSlide is an announcement shown on the screen for Duration, which defaults to 10 seconds
+type SignageSlide struct {
+	Title    string        `json:"title"`
+	Text     string        `json:"text"`
+	Duration time.Duration `json:"-"`
+}
+
+// SignageState is everything a screen shows at an instant
+type SignageState struct {
+	View             string          `json:"view"`
+	MosqueName       string          `json:"mosque_name"`
+	Time             string          `json:"time"`
+	Date             string          `json:"date"`
+	Hijri            string          `json:"hijri"`
+	CurrentPrayer    string          `json:"current_prayer"`
+	NextPrayer       string          `json:"next_prayer"`
+	NextPrayerTime   string          `json:"next_prayer_time"`
+	Countdown        string          `json:"countdown"` // Time until the next prayer, such as 1:05:09
+	CountdownSeconds int             `json:"countdown_seconds"`
+	IqamahPrayer     string          `json:"iqamah_prayer,omitempty"` // Prayer whose iqamah is counted down in the iqamah view
+	IqamahTime       string          `json:"iqamah_time,omitempty"`
+	IqamahMinutes    int             `json:"iqamah_minutes,omitempty"` // Minutes until the iqamah, rounded up
+	Prayers          []SignagePrayer `json:"prayers"`
+	Slide            *SignageSlide   `json:"slide,omitempty"`
+}
+
+// SignagePrayer is a row of the timings shown on the screen
+type SignagePrayer struct {
+	Name    string `json:"name"`
+	Adhan   string `json:"adhan"`
+	Iqamah  string `json:"iqamah,omitempty"`
+	Current bool   `json:"current"`
+	Next    bool   `json:"next"`
+}
+
+// ServeHTTP serves the page, the event stream and the state
+func (s *SignageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimPrefix(r.URL.Path, "/") {
+	case "":
+		s.servePage(w, r)
+	case "events":
+		s.serveEvents(w, r)
+	case "state":
+		state, err := s.State(clockOrSystem(s.Clock).Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+/*
+State returns what the screens show at now.  The current and next prayer are determined with DetermineWhichPrayer
+from the timings of the previous, current and next day, which are looked up once per month and kept for as long as
+they are needed.
+*/
+func (s *SignageHandler) State(now time.Time) (*SignageState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.days == nil {
+		if s.Customer == nil {
+			return nil, fmt.Errorf("signage customer location is required")
+		}
+		input, err := s.Customer.resolvePCalInput(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		s.days = &adjacentDays{provider: s.Customer.provider(), input: input, months: make(map[string]*PCalOutput)}
+	}
+	localNow, err := s.days.local(now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the months of yesterday, today and tomorrow are kept
+	for monthKey := range s.days.months {
+		if monthKey != localNow.AddDate(0, 0, -1).Format("01-2006") && monthKey != localNow.Format("01-2006") && monthKey != localNow.AddDate(0, 0, 1).Format("01-2006") {
+			delete(s.days.months, monthKey)
+		}
+	}
+
+	previousDay, err := s.days.day(localNow.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	currentDay, err := s.days.day(localNow)
+	if err != nil {
+		return nil, err
+	}
+	nextDay, err := s.days.day(localNow.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	determined, err := DetermineWhichPrayer(&previousDay.Timings, &currentDay.Timings, &nextDay.Timings, &localNow)
+	if err != nil {
+		return nil, err
+	}
+
+	var iqamah FiveDailyPrayers
+	if s.Iqamah != nil {
+		month, err := s.days.month(localNow)
+		if err != nil {
+			return nil, err
+		}
+		// A weekly iqamah time depends on the days of a week running across the start or end of the month
+		var adjacent []*PCalOutput
+		if s.Iqamah.weekly() {
+			firstDay := time.Date(localNow.Year(), localNow.Month(), 1, 12, 0, 0, 0, localNow.Location())
+			for _, day := range []time.Time{firstDay.AddDate(0, 0, -1), firstDay.AddDate(0, 1, 0)} {
+				adjacentMonth, err := s.days.month(day)
+				if err != nil {
+					return nil, err
+				}
+				adjacent = append(adjacent, adjacentMonth)
+			}
+		}
+		timetable, err := s.Iqamah.TimetableWithAdjacent(month, adjacent...)
+		if err != nil {
+			return nil, err
+		}
+		for i := range month.Data {
+			if month.Data[i].Date.Gregorian.Date == currentDay.Date.Gregorian.Date {
+				iqamah = timetable.Days[i].Iqamah
+			}
+		}
+	}
+
+	timeLayout, clockLayout := "15:04", "15:04:05"
+	if s.TwelveHour {
+		timeLayout, clockLayout = "3:04 PM", "3:04:05 PM"
+	}
+	formatTiming := func(timing string) (string, error) {
+		if timing == "" {
+			return "", nil
+		}
+		prayerTime, err := PrayerTimeOnDay(localNow, timing)
+		if err != nil {
+			return "", err
+		}
+		return prayerTime.Format(timeLayout), nil
+	}
+
+	date, err := currentDay.Day()
+	if err != nil {
+		return nil, err
+	}
+	hijri, err := currentDay.hijriDate(date)
+	if err != nil {
+		return nil, err
+	}
+	countdown := determined.TimeDiff.Truncate(time.Second)
+	state := &SignageState{
+		View:             SignageViewPrayers,
+		MosqueName:       s.MosqueName,
+		Time:             localNow.Format(clockLayout),
+		Date:             localNow.Format("Monday 2 January 2006"),
+		Hijri:            hijri.String(),
+		CurrentPrayer:    determined.CurrentPrayerName,
+		NextPrayer:       determined.NextPrayerName,
+		Countdown:        formatSignageDuration(countdown),
+		CountdownSeconds: int(countdown.Seconds()),
+	}
+	if state.NextPrayerTime, err = formatTiming(determined.NextPrayerTime); err != nil {
+		return nil, err
+	}
+
+	for _, prayer := range printablePrayers {
+		row := SignagePrayer{Name: prayer, Current: prayer == determined.CurrentPrayerName, Next: prayer == determined.NextPrayerName}
+		if row.Adhan, err = formatTiming(currentDay.Timings.timing(prayer)); err != nil {
+			return nil, err
+		}
+		if row.Iqamah, err = formatTiming(iqamah.timing(prayer)); err != nil {
+			return nil, err
+		}
+		state.Prayers = append(state.Prayers, row)
+	}
+
+	// Between the adhan and iqamah of today's current prayer the screen counts down to the iqamah instead
+	if iqamahTiming := iqamah.timing(determined.CurrentPrayerName); iqamahTiming != "" && !determined.PreviousDayIsha {
+		iqamahTime, err := PrayerTimeOnDay(localNow, iqamahTiming)
+		if err != nil {
+			return nil, err
+		}
+		if localNow.Before(iqamahTime) {
+			state.View = SignageViewIqamah
+			state.IqamahPrayer = determined.CurrentPrayerName
+			state.IqamahTime = iqamahTime.Format(timeLayout)
+			state.IqamahMinutes = int((iqamahTime.Sub(localNow) + time.Minute - 1) / time.Minute)
+		}
+	}
+
+	state.Slide = s.slide(localNow)
+	return state, nil
+}
+
+// slide returns the slide shown at now.  Slides are timed from the Unix epoch so every screen shows the same slide
+func (s *SignageHandler) slide(now time.Time) *SignageSlide {
+	var cycle time.Duration
+	for _, slide := range s.Slides {
+		cycle += slideDuration(slide)
+	}
+	if cycle == 0 {
+		return nil
+	}
+	position := time.Duration(now.UnixNano()) % cycle
+	for i := range s.Slides {
+		if position < slideDuration(s.Slides[i]) {
+			return &s.Slides[i]
+		}
+		position -= slideDuration(s.Slides[i])
+	}
+	return nil
+}
+
+func slideDuration(slide SignageSlide) time.Duration {
+	if slide.Duration <= 0 {
+		return defaultSignageSlideDuration
+	}
+	return slide.Duration
+}
+
+// serveEvents streams a state event every UpdateInterval until the screen disconnects
+func (s *SignageHandler) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	fmt.Fprintf(w, "retry: %d\n\n", signageRetryMilliseconds)
+
+	interval := s.UpdateInterval
+	if interval <= 0 {
+		interval = defaultSignageUpdateInterval
+	}
+	clock := clockOrSystem(s.Clock)
+	for {
+		state, err := s.State(clock.Now())
+		if err != nil {
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		} else {
+			data, err := json.Marshal(state)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: state\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-clock.After(interval):
+		}
+	}
+}
+
+// servePage serves the page with the current state, so it shows the timings before the event stream connects
+func (s *SignageHandler) servePage(w http.ResponseWriter, r *http.Request) {
+	state, err := s.State(clockOrSystem(s.Clock).Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	signageTemplate.Execute(w, state)
+}
+
+// formatSignageDuration formats d as hours, minutes and seconds such as 1:05:09, or as 5:09 under an hour
+func formatSignageDuration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds < 0 {
+		seconds = 0
+	}
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+var signageTemplate = template.Must(template.New("signage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<!-- Reload every hour in case the event stream stops, such as after a network outage -->
+<meta http-equiv="refresh" content="3600">
+<title>{{.MosqueName}}</title>
+<style>
+html, body { margin: 0; height: 100%; background: #10231d; color: #f4f1e8; font-family: Helvetica, Arial, sans-serif; overflow: hidden; cursor: none; }
+body { display: flex; flex-direction: column; }
+header { display: flex; justify-content: space-between; align-items: baseline; padding: 2vh 3vw; font-size: 3vh; }
+#time { font-size: 12vh; font-weight: bold; text-align: center; }
+#next { font-size: 4.5vh; text-align: center; margin-bottom: 3vh; }
+#countdown { color: #f2c14e; font-weight: bold; }
+table { width: 90vw; margin: 0 auto; border-collapse: collapse; font-size: 5vh; }
+th { font-size: 3vh; font-weight: normal; color: #a9c5b8; }
+td, th { padding: 1vh 2vw; text-align: center; }
+tr.current td { background: #2f5d50; }
+tr.next td { color: #f2c14e; }
+#slide { margin: auto 5vw 4vh; padding: 2vh 2vw; border-top: 0.3vh solid #2f5d50; font-size: 3.5vh; text-align: center; min-height: 10vh; }
+#slide h2 { margin: 0 0 1vh; font-size: 4vh; }
+#iqamah { display: none; flex: 1; flex-direction: column; justify-content: center; align-items: center; font-size: 6vh; }
+#iqamah strong { font-size: 22vh; color: #f2c14e; }
+body.iqamah #prayers { display: none; }
+body.iqamah #iqamah { display: flex; }
+</style>
+</head>
+<body class="{{.View}}">
+<header><span id="mosque">{{.MosqueName}}</span><span><span id="date">{{.Date}}</span> &middot; <span id="hijri">{{.Hijri}}</span></span></header>
+<div id="prayers">
+<div id="time">{{.Time}}</div>
+<div id="next"><span id="next-prayer">{{.NextPrayer}}</span> at <span id="next-time">{{.NextPrayerTime}}</span> in <span id="countdown">{{.Countdown}}</span></div>
+<table>
+<thead><tr><th></th><th>Adhan</th><th>Iqamah</th></tr></thead>
+<tbody id="timings">
+{{- range .Prayers}}
+<tr{{if .Current}} class="current"{{else if .Next}} class="next"{{end}}><td>{{.Name}}</td><td>{{.Adhan}}</td><td>{{.Iqamah}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<div id="slide">{{with .Slide}}<h2>{{.Title}}</h2><div>{{.Text}}</div>{{end}}</div>
+</div>
+<div id="iqamah"><span id="iqamah-prayer">{{.IqamahPrayer}}</span> iqamah in<strong id="iqamah-minutes">{{.IqamahMinutes}}</strong>minutes</div>
+<script>
+(function () {
+  function text(id, value) { document.getElementById(id).textContent = value; }
+  function cell(row, value) { var td = document.createElement("td"); td.textContent = value; row.appendChild(td); }
+  var events = new EventSource("events");
+  events.addEventListener("state", function (event) {
+    var state = JSON.parse(event.data);
+    document.body.className = state.view;
+    text("mosque", state.mosque_name);
+    text("date", state.date);
+    text("hijri", state.hijri);
+    text("time", state.time);
+    text("next-prayer", state.next_prayer);
+    text("next-time", state.next_prayer_time);
+    text("countdown", state.countdown);
+    text("iqamah-prayer", state.iqamah_prayer || "");
+    text("iqamah-minutes", state.iqamah_minutes || "");
+    var timings = document.getElementById("timings");
+    timings.textContent = "";
+    state.prayers.forEach(function (prayer) {
+      var row = document.createElement("tr");
+      row.className = prayer.current ? "current" : prayer.next ? "next" : "";
+      cell(row, prayer.name);
+      cell(row, prayer.adhan);
+      cell(row, prayer.iqamah || "");
+      timings.appendChild(row);
+    });
+    var slide = document.getElementById("slide");
+    slide.textContent = "";
+    if (state.slide) {
+      var title = document.createElement("h2");
+      title.textContent = state.slide.title;
+      var body = document.createElement("div");
+      body.textContent = state.slide.text;
+      slide.appendChild(title);
+      slide.appendChild(body);
+    }
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,146 @@
+package schedule_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+func signageTestHandler(t *testing.T) (*psched.SignageHandler, *time.Location) {
+	t.Helper()
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &psched.SignageHandler{
+		Customer: &psched.CustomerLocationInput{
+			Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+			Provider:    &testMonthProvider{location: location},
+		},
+		MosqueName: "Masjid Al-Noor",
+		Iqamah:     &psched.IqamahRules{Fajr: &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 20}},
+		Slides: []psched.SignageSlide{
+			{Title: "Welcome", Text: "Please silence your phones"},
+			{Title: "Fundraiser", Text: "After Maghrib on Saturday", Duration: 5 * time.Second},
+		},
+	}, location
+}
+
+func TestSignageState(t *testing.T) {
+	handler, location := signageTestHandler(t)
+
+	// Fajr is at 05:21 on the 12th
+	state, err := handler.State(time.Date(2022, time.October, 12, 5, 15, 0, 0, location))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.View != psched.SignageViewPrayers || state.CurrentPrayer != "Isha" || state.NextPrayer != "Fajr" || state.NextPrayerTime != "05:21" || state.Countdown != "6:00" {
+		t.Errorf("unexpected state before fajr: %+v", state)
+	}
+	if state.Time != "05:15:00" || state.Date != "Wednesday 12 October 2022" || state.Hijri != "16 Rabi al-Awwal 1444 AH" {
+		t.Errorf("unexpected date and time: %s %s %s", state.Time, state.Date, state.Hijri)
+	}
+	if len(state.Prayers) != 6 || state.Prayers[0] != (psched.SignagePrayer{Name: "Fajr", Adhan: "05:21", Iqamah: "05:41", Next: true}) {
+		t.Errorf("unexpected prayers: %+v", state.Prayers)
+	}
+
+	state, err = handler.State(time.Date(2022, time.October, 12, 5, 25, 30, 0, location))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.View != psched.SignageViewIqamah || state.IqamahPrayer != "Fajr" || state.IqamahTime != "05:41" || state.IqamahMinutes != 16 {
+		t.Errorf("unexpected state between fajr adhan and iqamah: %+v", state)
+	}
+
+	handler.TwelveHour = true
+	state, err = handler.State(time.Date(2022, time.October, 12, 7, 0, 0, 0, location))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.View != psched.SignageViewPrayers || state.CurrentPrayer != "Sunrise" || state.NextPrayerTime != "12:30 PM" || state.Countdown != "5:30:00" || state.Time != "7:00:00 AM" {
+		t.Errorf("unexpected state after sunrise: %+v", state)
+	}
+
+	// Slides are shown in turn, each for its own duration
+	cycleStart := time.Unix(1665576000-1665576000%15, 0)
+	for offset, expected := range map[time.Duration]string{0: "Welcome", 9 * time.Second: "Welcome", 10 * time.Second: "Fundraiser", 15 * time.Second: "Welcome"} {
+		state, err := handler.State(cycleStart.Add(offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.Slide == nil || state.Slide.Title != expected {
+			t.Errorf("expected slide %s after %s, got %+v", expected, offset, state.Slide)
+		}
+	}
+}
+
+func TestSignageHandler(t *testing.T) {
+	handler, location := signageTestHandler(t)
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 5, 25, 0, 0, location))
+	handler.Clock = clock
+	handler.UpdateInterval = 10 * time.Millisecond
+	server := httptest.NewServer(http.StripPrefix("/screen", handler))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/screen/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if !strings.Contains(string(page), `<body class="iqamah">`) || !strings.Contains(string(page), `new EventSource("events")`) || !strings.Contains(string(page), "<td>Fajr</td><td>05:21</td><td>05:41</td>") {
+		t.Errorf("unexpected page:\n%s", page)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/screen/events", nil)
+	response, err = http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type %s", response.Header.Get("Content-Type"))
+	}
+	events := 0
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() && events < 2 {
+		if !strings.HasPrefix(scanner.Text(), "data: ") {
+			continue
+		}
+		state := new(psched.SignageState)
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(scanner.Text(), "data: ")), state); err != nil {
+			t.Fatal(err)
+		}
+		if state.View != psched.SignageViewIqamah || state.IqamahMinutes != 16 || state.Slide == nil {
+			t.Errorf("unexpected streamed state: %+v", state)
+		}
+		events++
+		// The next state is pushed once the update interval has passed
+		if events == 1 {
+			clock.BlockUntil(1)
+			clock.Advance(handler.UpdateInterval)
+		}
+	}
+	if events != 2 {
+		t.Errorf("expected 2 streamed states, got %d: %v", events, scanner.Err())
+	}
+
+	response, err = http.Get(server.URL + "/screen/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown path, got %d", response.StatusCode)
+	}
+}
@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"math"
+	"time"
+)
+
+// julianDate returns the julian date of t
+func julianDate(t time.Time) float64 {
+	return float64(t.Unix())/86400 + 2440587.5
+}
+
+// sunPosition returns the declination of the sun in degrees and the equation of time in hours at julian date jd.
+// Accurate to about a minute of time between 1950 and 2050, which is all prayer times need.
+func sunPosition(jd float64) (declination float64, equationOfTime float64) {
+	d := jd - 2451545.0
+	meanAnomaly := fixAngle(357.529 + 0.98560028*d)
+	meanLongitude := fixAngle(280.459 + 0.98564736*d)
+	eclipticLongitude := fixAngle(meanLongitude + 1.915*dsin(meanAnomaly) + 0.020*dsin(2*meanAnomaly))
+	obliquity := 23.439 - 0.00000036*d
+
+	rightAscension := fixHour(darctan2(dcos(obliquity)*dsin(eclipticLongitude), dcos(eclipticLongitude)) / 15)
+	declination = darcsin(dsin(obliquity) * dsin(eclipticLongitude))
+	equationOfTime = meanLongitude/15 - rightAscension
+	if equationOfTime > 12 {
+		equationOfTime -= 24
+	} else if equationOfTime < -12 {
+		equationOfTime += 24
+	}
+	return declination, equationOfTime
+}
+
+// sunHorizontal returns the altitude and azimuth of the sun in degrees at t, seen from latitude and longitude.
+// Azimuth is clockwise from true north.
+func sunHorizontal(t time.Time, latitude float64, longitude float64) (altitude float64, azimuth float64) {
+	declination, equationOfTime := sunPosition(julianDate(t))
+	utc := t.UTC()
+	utcHours := float64(utc.Hour()) + float64(utc.Minute())/60 + float64(utc.Second())/3600
+	hourAngle := (utcHours + longitude/15 + equationOfTime - 12) * 15
+
+	altitude = darcsin(dsin(latitude)*dsin(declination) + dcos(latitude)*dcos(declination)*dcos(hourAngle))
+	azimuth = fixAngle(darctan2(dsin(hourAngle), dcos(hourAngle)*dsin(latitude)-dtan(declination)*dcos(latitude)) + 180)
+	return altitude, azimuth
+}
+
+// solarNoon returns the time of solar noon in UTC on the UTC calendar day of day at longitude
+func solarNoon(day time.Time, longitude float64) time.Time {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	_, equationOfTime := sunPosition(julianDate(midnight.Add(12 * time.Hour)))
+	hours := 12 - longitude/15 - equationOfTime
+	return midnight.Add(time.Duration(hours * float64(time.Hour)))
+}
+
+func dsin(degrees float64) float64 {
+	return math.Sin(degrees * math.Pi / 180)
+}
+
+func dcos(degrees float64) float64 {
+	return math.Cos(degrees * math.Pi / 180)
+}
+
+func dtan(degrees float64) float64 {
+	return math.Tan(degrees * math.Pi / 180)
+}
+
+func darcsin(x float64) float64 {
+	return math.Asin(x) * 180 / math.Pi
+}
+
+func darccos(x float64) float64 {
+	return math.Acos(x) * 180 / math.Pi
+}
+
+func darctan2(y float64, x float64) float64 {
+	return math.Atan2(y, x) * 180 / math.Pi
+}
+
+// fixAngle returns degrees within 0 to 360
+func fixAngle(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+// fixHour returns hours within 0 to 24
+func fixHour(hours float64) float64 {
+	hours = math.Mod(hours, 24)
+	if hours < 0 {
+		hours += 24
+	}
+	return hours
+}
@@ -0,0 +1,301 @@
+package schedule
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SourceTimetable is the Source of months from an ImportedTimetable
+const SourceTimetable = "timetable"
+
+// defaultTimetableDateLayouts are tried in order when CSVTimetableFormat.DateLayouts is empty.  Dates are day first
+var defaultTimetableDateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"2/1/2006",
+	"02-01-2006",
+	"2-1-2006",
+	"02.01.2006",
+	"2 Jan 2006",
+	"2 January 2006",
+	"Mon 2 Jan 2006",
+	"Monday 2 January 2006",
+}
+
+// requiredTimetableColumns are the columns every timetable must have.  Imsak is optional
+var requiredTimetableColumns = []string{"Date", "Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}
+
+// CSVTimetableFormat describes the layout of a CSV timetable
+type CSVTimetableFormat struct {
+	Columns     map[string]string // Header of the "Date" column and of each timing, such as "Fajr": "Fajr Begins".  Defaults to the names themselves
+	DateLayouts []string          // Go time layouts dates are parsed with, such as "01/02/2006" for month first dates.  Defaults to common day first layouts
+	TwelveHour  bool              // Times without am or pm are 12 hour, with Dhuhr after 11 and every later prayer in the afternoon
+	Comma       rune              // Field separator.  Detected from the header row when 0
+}
+
+// ImportedTimetable is a MonthProvider serving the days of a published timetable, such as a mosque's own timetable
+type ImportedTimetable struct {
+	Location *time.Location
+	Days     []PCalDay // In date order
+}
+
+/*
+ImportCSVTimetable reads a timetable with a header row and a row for every day, such as a spreadsheet saved as CSV.
+Header names are matched case insensitively, the UTF-8 byte order mark and semicolon or tab separated files of some
+spreadsheet exports are accepted, and blank rows are skipped.  Times are read as times in location.
+*/
+func ImportCSVTimetable(r io.Reader, format CSVTimetableFormat, location *time.Location) (*ImportedTimetable, error) {
+	if location == nil {
+		return nil, fmt.Errorf("timetable location is required")
+	}
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read timetable: %s", err)
+	}
+	contents = bytes.TrimPrefix(contents, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(contents))
+	reader.Comma = format.Comma
+	if reader.Comma == 0 {
+		reader.Comma = detectTimetableComma(contents)
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read timetable header: %s", err)
+	}
+	columns, err := format.columnIndexes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	layouts := format.DateLayouts
+	if len(layouts) == 0 {
+		layouts = defaultTimetableDateLayouts
+	}
+
+	type importedDay struct {
+		date time.Time
+		day  PCalDay
+	}
+	var days []importedDay
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read timetable: %s", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		field := func(name string) string {
+			index, ok := columns[name]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		date, err := parseTimetableDate(field("Date"), layouts, location)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		dateKey := date.Format("02-01-2006")
+		if previous, ok := seen[dateKey]; ok {
+			return nil, fmt.Errorf("line %d: %s is already on line %d", line, date.Format("2006-01-02"), previous)
+		}
+		seen[dateKey] = line
+
+		day := PCalDay{Meta: PCalMeta{Timezone: location.String()}}
+		day.Date.Readable = date.Format("02 Jan 2006")
+		day.Date.Timestamp = fmt.Sprint(date.Unix())
+		day.Date.Gregorian.Date = dateKey
+
+		var previous, fajr time.Time
+		for _, name := range prayerTimingNames {
+			value := field(name)
+			if value == "" && name == "Imsak" {
+				continue
+			}
+			prayerTime, err := parseTimetableTime(value, name, format.TwelveHour, date, previous)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %s", line, name, err)
+			}
+			switch {
+			case name == "Isha" && !prayerTime.After(previous):
+				// Isha may be after midnight in the summer at high latitudes, but still before the next Fajr
+				if !prayerTime.Before(fajr) {
+					return nil, fmt.Errorf("line %d: Isha %s is neither after Maghrib nor after midnight before Fajr", line, value)
+				}
+			case !prayerTime.After(previous):
+				return nil, fmt.Errorf("line %d: %s %s is not after the previous time, are the times 12 hour?", line, name, value)
+			}
+			if name == "Fajr" {
+				fajr = prayerTime
+			}
+			previous = prayerTime
+			day.Timings.setTiming(name, prayerTime.Format("15:04 (MST)"))
+		}
+		days = append(days, importedDay{date: date, day: day})
+	}
+
+	if len(days) == 0 {
+		return nil, fmt.Errorf("timetable has no days")
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].date.Before(days[j].date) })
+	timetable := &ImportedTimetable{Location: location, Days: make([]PCalDay, len(days))}
+	for i := range days {
+		timetable.Days[i] = days[i].day
+	}
+	return timetable, nil
+}
+
+// MonthlyPrayers returns the timetable days of the month of input.CustTime
+func (t *ImportedTimetable) MonthlyPrayers(ctx context.Context, input *PCalInput) (*PCalOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	monthSuffix := input.CustTime.Format("-01-2006")
+	output := &PCalOutput{Code: 200, Status: "OK", Latitude: input.Latitude, Longitude: input.Longitude, Source: SourceTimetable}
+	for _, day := range t.Days {
+		if strings.HasSuffix(day.Date.Gregorian.Date, monthSuffix) {
+			day.Meta.Latitude = float64(input.Latitude)
+			day.Meta.Longitude = float64(input.Longitude)
+			output.Data = append(output.Data, day)
+		}
+	}
+	if len(output.Data) == 0 {
+		return nil, fmt.Errorf("timetable has no days in %s", input.CustTime.Format("01-2006"))
+	}
+	return output, nil
+}
+
+// columnIndexes returns the index of the Date column and of every timing column in header
+func (f CSVTimetableFormat) columnIndexes(header []string) (map[string]int, error) {
+	indexes := make(map[string]int)
+	for _, name := range append([]string{"Date"}, prayerTimingNames...) {
+		heading := name
+		if mapped, ok := f.Columns[name]; ok {
+			heading = mapped
+		}
+		for i, column := range header {
+			if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(heading)) {
+				indexes[name] = i
+				break
+			}
+		}
+	}
+	for _, name := range requiredTimetableColumns {
+		if _, ok := indexes[name]; !ok {
+			return nil, fmt.Errorf("timetable header has no %s column: %q", name, header)
+		}
+	}
+	return indexes, nil
+}
+
+// detectTimetableComma returns the separator used the most in the first line of contents
+func detectTimetableComma(contents []byte) rune {
+	firstLine, _ := bufio.NewReader(bytes.NewReader(contents)).ReadString('\n')
+	comma, most := ',', 0
+	for _, candidate := range []rune{',', ';', '\t'} {
+		if count := strings.Count(firstLine, string(candidate)); count > most {
+			comma, most = candidate, count
+		}
+	}
+	return comma
+}
+
+// parseTimetableDate parses value with the first of layouts which matches
+func parseTimetableDate(value string, layouts []string, location *time.Location) (time.Time, error) {
+	for _, layout := range layouts {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("date %q does not match any date layout", value)
+}
+
+/*
+parseTimetableTime parses a time such as 17:05, 5:05 pm, 5.05PM, 17:05 (PDT) or 5:05 on day.  Times without am or pm are 24 hour
+unless twelveHour is set, in which case prayers from Dhuhr onwards are moved to the afternoon.  A 12 hour Isha is only moved
+to the evening when it then comes after previous, the Maghrib time, and is otherwise read as after midnight.
+*/
+func parseTimetableTime(value string, prayer string, twelveHour bool, day time.Time, previous time.Time) (time.Time, error) {
+	// Times written with a timezone such as 17:05 (PDT) are already in the timetable location
+	if open := strings.Index(value, "("); open > 0 && strings.HasSuffix(value, ")") {
+		value = value[:open]
+	}
+	clock := strings.ToLower(strings.ReplaceAll(value, " ", ""))
+	clock = strings.ReplaceAll(clock, ".m.", "m")
+	meridiem := ""
+	if strings.HasSuffix(clock, "am") || strings.HasSuffix(clock, "pm") {
+		meridiem = clock[len(clock)-2:]
+		clock = clock[:len(clock)-2]
+	}
+
+	parts := strings.FieldsFunc(clock, func(r rune) bool { return r == ':' || r == '.' })
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("time %q is not hours and minutes", value)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time %q has invalid hours", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return time.Time{}, fmt.Errorf("time %q has invalid minutes", value)
+	}
+
+	switch {
+	case meridiem != "":
+		if hour < 1 || hour > 12 {
+			return time.Time{}, fmt.Errorf("time %q has invalid 12 hour hours", value)
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	case twelveHour:
+		if hour < 1 || hour > 12 {
+			return time.Time{}, fmt.Errorf("time %q has invalid 12 hour hours", value)
+		}
+		switch prayer {
+		case "Dhuhr":
+			if hour < 11 {
+				hour += 12
+			}
+		case "Asr", "Maghrib":
+			if hour != 12 {
+				hour += 12
+			}
+		case "Isha":
+			if evening := hour%12 + 12; evening*60+minute > previous.Hour()*60+previous.Minute() {
+				hour = evening
+			} else {
+				hour %= 12
+			}
+		default:
+			hour %= 12
+		}
+	default:
+		if hour < 0 || hour > 23 {
+			return time.Time{}, fmt.Errorf("time %q has invalid 24 hour hours", value)
+		}
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location()), nil
+}
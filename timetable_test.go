@@ -0,0 +1,136 @@
+package schedule_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+const testMosqueTimetable = "\xef\xbb\xbfDate;Fajr Begins;Sunrise;Zuhr;Asr;Maghrib;Isha\r\n" +
+	"02/10/2022;5:40;6:49;12:42;4:05;6:36;7:44\r\n" +
+	"01/10/2022;5:39;6:48;12:43;4:06;6:38;7:46\r\n" +
+	";;;;;;\r\n" +
+	"03/10/2022;5:41 am;6:50 AM;12:42 p.m.;4:04 PM;6:35pm;7:43 pm\r\n"
+
+func importTestTimetable(t *testing.T) *psched.ImportedTimetable {
+	t.Helper()
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	timetable, err := psched.ImportCSVTimetable(strings.NewReader(testMosqueTimetable), psched.CSVTimetableFormat{
+		Columns:    map[string]string{"Fajr": "Fajr Begins", "Dhuhr": "zuhr"},
+		TwelveHour: true,
+	}, location)
+	if err != nil {
+		t.Fatalf("unable to import timetable: %s", err)
+	}
+	return timetable
+}
+
+func TestImportCSVTimetable(t *testing.T) {
+	timetable := importTestTimetable(t)
+	if len(timetable.Days) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(timetable.Days))
+	}
+
+	first := timetable.Days[0]
+	if first.Date.Gregorian.Date != "01-10-2022" || first.Meta.Timezone != "America/Los_Angeles" {
+		t.Errorf("days are not in date order: %+v", first.Date)
+	}
+	expected := psched.FiveDailyPrayers{
+		Fajr:    "05:39 (PDT)",
+		Sunrise: "06:48 (PDT)",
+		Dhuhr:   "12:43 (PDT)",
+		Asr:     "16:06 (PDT)",
+		Maghrib: "18:38 (PDT)",
+		Isha:    "19:46 (PDT)",
+	}
+	if first.Timings != expected {
+		t.Errorf("unexpected timings: %+v", first.Timings)
+	}
+	if timetable.Days[2].Timings.Dhuhr != "12:42 (PDT)" || timetable.Days[2].Timings.Maghrib != "18:35 (PDT)" {
+		t.Errorf("am and pm times were not read: %+v", timetable.Days[2].Timings)
+	}
+}
+
+func TestImportCSVTimetableErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing column":      "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib\n2022-10-01,05:39,06:48,12:43,16:06,18:38\n",
+		"12 hour times":       "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n2022-10-01,5:39,6:48,12:43,4:06,6:38,7:46\n",
+		"invalid date":        "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n10/32/2022,05:39,06:48,12:43,16:06,18:38,19:46\n",
+		"duplicate date":      "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n2022-10-01,05:39,06:48,12:43,16:06,18:38,19:46\n2022-10-01,05:39,06:48,12:43,16:06,18:38,19:46\n",
+		"invalid time":        "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n2022-10-01,05:39,06:48,12:43,16:06,18:38,19:66\n",
+		"no days":             "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n",
+		"isha before maghrib": "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n2022-10-01,05:39,06:48,12:43,16:06,18:38,13:05\n",
+	}
+	for name, contents := range tests {
+		if _, err := psched.ImportCSVTimetable(strings.NewReader(contents), psched.CSVTimetableFormat{}, time.UTC); err == nil {
+			t.Errorf("%s did not return an error", name)
+		}
+	}
+}
+
+func TestImportCSVTimetableIshaAfterMidnight(t *testing.T) {
+	// Summer at a high latitude, where Isha is after midnight on some days
+	const contents = "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n" +
+		"2022-06-20,2:45,4:40,1:15,5:35,9:50,11:40\n" +
+		"2022-06-21,2:44,4:40,1:15,5:35,9:51,12:30\n" +
+		"2022-06-22,2:44,4:40,1:15,5:35,9:51,1:05\n"
+	timetable, err := psched.ImportCSVTimetable(strings.NewReader(contents), psched.CSVTimetableFormat{TwelveHour: true}, time.UTC)
+	if err != nil {
+		t.Fatalf("unable to import timetable: %s", err)
+	}
+	for i, want := range []string{"23:40 (UTC)", "00:30 (UTC)", "01:05 (UTC)"} {
+		if isha := timetable.Days[i].Timings.Isha; isha != want {
+			t.Errorf("isha of day %d is %s, want %s", i+1, isha, want)
+		}
+	}
+
+	// 4:05 can neither be in the evening after Maghrib nor after midnight before Fajr
+	invalid := "Date,Fajr,Sunrise,Dhuhr,Asr,Maghrib,Isha\n2022-06-20,2:45,4:40,1:15,5:35,9:50,4:05\n"
+	if _, err := psched.ImportCSVTimetable(strings.NewReader(invalid), psched.CSVTimetableFormat{TwelveHour: true}, time.UTC); err == nil {
+		t.Error("isha after fajr did not return an error")
+	}
+}
+
+func TestImportedTimetableProvider(t *testing.T) {
+	timetable := importTestTimetable(t)
+	customer := &psched.CustomerLocationInput{
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		CustTime:    time.Date(2022, time.October, 2, 12, 0, 0, 0, time.UTC),
+		Provider:    timetable,
+	}
+
+	month, err := customer.PrayerCalendar()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if month.Source != psched.SourceTimetable || len(month.Data) != 3 || month.Data[0].Meta.Latitude != float64(float32(34.103)) {
+		t.Errorf("unexpected month: source %q with %d days", month.Source, len(month.Data))
+	}
+
+	// 13:00 PDT on 2 October is between Dhuhr and Asr
+	now, err := customer.PrayerNow(time.Date(2022, time.October, 2, 20, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if now.CurrentPrayerName != "Dhuhr" || now.NextPrayerName != "Asr" {
+		t.Errorf("expected Dhuhr then Asr, got %s then %s", now.CurrentPrayerName, now.NextPrayerName)
+	}
+
+	iqamah, err := (&psched.IqamahRules{Maghrib: &psched.IqamahRule{Type: psched.IqamahAfterAdhan, Minutes: 5}}).Timetable(month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iqamah.Days[0].Iqamah.Maghrib != "18:43 (PDT)" {
+		t.Errorf("unexpected maghrib iqamah: %s", iqamah.Days[0].Iqamah.Maghrib)
+	}
+
+	customer.CustTime = time.Date(2022, time.November, 2, 12, 0, 0, 0, time.UTC)
+	if _, err := customer.PrayerCalendar(); err == nil {
+		t.Error("a month missing from the timetable did not return an error")
+	}
+}
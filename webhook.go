@@ -0,0 +1,467 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers of a webhook request
+const (
+	WebhookSignatureHeader = "X-Prayer-Signature" // "sha256=" and the hex HMAC-SHA256 of the timestamp, a dot and the body
+	WebhookTimestampHeader = "X-Prayer-Timestamp" // Unix time the request was signed at
+	WebhookDeliveryHeader  = "X-Prayer-Delivery"  // ID of the delivery, the same on every attempt
+)
+
+// Kinds of a WebhookPayload
+const (
+	WebhookEventBegins   = "prayer.begins"
+	WebhookEventUpcoming = "prayer.upcoming"
+)
+
+// Defaults of a WebhookNotifier
+const (
+	DefaultWebhookMaxAttempts    = 6
+	DefaultWebhookInitialBackoff = 5 * time.Second
+	DefaultWebhookMaxBackoff     = 10 * time.Minute
+	DefaultWebhookTimeout        = 10 * time.Second
+)
+
+// WebhookSubscriber receives the prayer events of a location as signed JSON requests
+type WebhookSubscriber struct {
+	ID        string          // Identifies the subscriber in payloads and dead letters
+	URL       string          // Requests are POSTed to URL
+	Secret    string          // Key of the HMAC-SHA256 signature of each request
+	Location  string          // Name of the Scheduler location the subscriber receives the events of
+	LeadTimes []time.Duration // Events are sent this long before each prayer begins.  Defaults to when it begins only
+	Prayers   []string        // Prayers the subscriber receives.  Defaults to every prayer of the Scheduler
+}
+
+// WebhookPayload is the JSON body of a webhook request
+type WebhookPayload struct {
+	ID          string          `json:"id"`
+	Subscriber  string          `json:"subscriber"`
+	Event       string          `json:"event"` // WebhookEventBegins, or WebhookEventUpcoming for lead times
+	Prayer      string          `json:"prayer"`
+	Time        time.Time       `json:"time"`         // When the prayer begins
+	LeadSeconds int64           `json:"lead_seconds"` // How long before the prayer begins the event was sent
+	Location    WebhookLocation `json:"location"`
+	Hijri       WebhookHijri    `json:"hijri"`
+}
+
+// WebhookLocation is the location of a WebhookPayload
+type WebhookLocation struct {
+	Name      string  `json:"name"`
+	Latitude  float32 `json:"latitude"`
+	Longitude float32 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+}
+
+// WebhookHijri is the Hijri date of a WebhookPayload
+type WebhookHijri struct {
+	Year      int    `json:"year"`
+	Month     int    `json:"month"`
+	Day       int    `json:"day"`
+	MonthName string `json:"month_name"`
+}
+
+// WebhookDeadLetter is a delivery which was given up on
+type WebhookDeadLetter struct {
+	Subscriber string
+	Payload    WebhookPayload
+	Attempts   int
+	Error      string    // Error of the last attempt
+	Failed     time.Time // When the delivery was given up on
+}
+
+/*
+WebhookNotifier sends the events of a Scheduler to subscribers as signed JSON requests.  Events are queued by Notify
+and sent by Run, which retries failed deliveries with exponential backoff.  A delivery is given up on and moved to
+the dead letters after MaxAttempts, or at once when the subscriber rejects it with a 4xx status other than 408 and 429.
+*/
+type WebhookNotifier struct {
+	Subscribers    []WebhookSubscriber
+	Client         *http.Client  // Defaults to a client with a timeout of DefaultWebhookTimeout
+	Clock          Clock         // Defaults to SystemClock
+	MaxAttempts    int           // Attempts of a delivery before it is dead.  Defaults to DefaultWebhookMaxAttempts
+	InitialBackoff time.Duration // Wait before the first retry, doubled for each retry.  Defaults to DefaultWebhookInitialBackoff
+	MaxBackoff     time.Duration // Longest wait between retries.  Defaults to DefaultWebhookMaxBackoff
+
+	mu          sync.Mutex
+	queue       []*webhookDelivery
+	deadLetters []WebhookDeadLetter
+	wake        chan struct{}
+}
+
+// webhookDelivery is a queued payload of a subscriber
+type webhookDelivery struct {
+	subscriber WebhookSubscriber
+	payload    WebhookPayload
+	body       []byte
+	attempts   int
+	next       time.Time // When the next attempt is due
+	sending    bool
+}
+
+// webhookRejected is the error of a delivery which is not retried
+type webhookRejected struct {
+	err error
+}
+
+func (e *webhookRejected) Error() string {
+	return e.err.Error()
+}
+
+/*
+Attach adds the lead times of every subscriber to scheduler and registers Notify as its callback.  Attach is called
+before the scheduler runs.
+*/
+func (n *WebhookNotifier) Attach(scheduler *Scheduler) {
+	for _, lead := range n.LeadTimes() {
+		if lead == 0 || containsDuration(scheduler.LeadTimes, lead) {
+			continue
+		}
+		scheduler.LeadTimes = append(scheduler.LeadTimes, lead)
+	}
+	scheduler.OnEvent(n.Notify)
+}
+
+// LeadTimes returns the lead times of every subscriber, for the LeadTimes of the Scheduler
+func (n *WebhookNotifier) LeadTimes() []time.Duration {
+	var leads []time.Duration
+	for _, subscriber := range n.Subscribers {
+		for _, lead := range subscriber.LeadTimes {
+			if !containsDuration(leads, lead) {
+				leads = append(leads, lead)
+			}
+		}
+	}
+	return leads
+}
+
+// Notify queues a delivery of event to every subscriber of its location, prayer and lead time
+func (n *WebhookNotifier) Notify(event SchedulerEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := clockOrSystem(n.Clock).Now()
+	for _, subscriber := range n.Subscribers {
+		if subscriber.Location != event.Location || !subscriber.wants(event) {
+			continue
+		}
+		payload := WebhookPayload{
+			ID:          fmt.Sprintf("%s-%s-%d-%d", subscriber.ID, event.Prayer, event.Begins.Unix(), int64(event.Lead/time.Second)),
+			Subscriber:  subscriber.ID,
+			Event:       WebhookEventBegins,
+			Prayer:      event.Prayer,
+			Time:        event.Begins,
+			LeadSeconds: int64(event.Lead / time.Second),
+			Location: WebhookLocation{
+				Name:      event.Location,
+				Latitude:  event.Coordinates.Latitude,
+				Longitude: event.Coordinates.Longitude,
+				Timezone:  event.Begins.Location().String(),
+			},
+			Hijri: WebhookHijri{Year: event.Hijri.Year, Month: event.Hijri.Month, Day: event.Hijri.Day, MonthName: event.Hijri.MonthName()},
+		}
+		if event.Lead > 0 {
+			payload.Event = WebhookEventUpcoming
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			n.deadLetters = append(n.deadLetters, WebhookDeadLetter{Subscriber: subscriber.ID, Payload: payload, Error: err.Error(), Failed: now})
+			continue
+		}
+		n.queue = append(n.queue, &webhookDelivery{subscriber: subscriber, payload: payload, body: body, next: now})
+	}
+	n.wakeUp()
+}
+
+// Pending returns the number of deliveries which have not been sent or given up on
+func (n *WebhookNotifier) Pending() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.queue)
+}
+
+// DeadLetters returns the deliveries which were given up on, oldest first
+func (n *WebhookNotifier) DeadLetters() []WebhookDeadLetter {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]WebhookDeadLetter(nil), n.deadLetters...)
+}
+
+// RetryDeadLetters moves the dead letters back to the queue with their attempts reset, and returns how many were moved
+func (n *WebhookNotifier) RetryDeadLetters() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := clockOrSystem(n.Clock).Now()
+	moved := 0
+	var kept []WebhookDeadLetter
+	for _, letter := range n.deadLetters {
+		subscriber, ok := n.subscriber(letter.Subscriber)
+		body, err := json.Marshal(letter.Payload)
+		if !ok || err != nil {
+			kept = append(kept, letter)
+			continue
+		}
+		n.queue = append(n.queue, &webhookDelivery{subscriber: subscriber, payload: letter.Payload, body: body, next: now})
+		moved++
+	}
+	n.deadLetters = kept
+	n.wakeUp()
+	return moved
+}
+
+/*
+Run sends the queued deliveries until ctx is done, and then returns the error of ctx once the attempts in flight
+have ended.  Each delivery is sent on its own, so a slow subscriber does not hold up the deliveries queued after it.
+Attempts cut short by ctx are not counted, and their deliveries stay queued for the next Run.
+*/
+func (n *WebhookNotifier) Run(ctx context.Context) error {
+	clock := clockOrSystem(n.Clock)
+	n.mu.Lock()
+	if n.wake == nil {
+		n.wake = make(chan struct{}, 1)
+	}
+	wake := n.wake
+	n.mu.Unlock()
+
+	// The timer of the next retry is kept while it is still the next, so a wake up for new deliveries does not start
+	// another timer
+	var timer <-chan time.Time
+	var timerAt time.Time
+	var sending sync.WaitGroup
+	for {
+		due, next := n.due(clock.Now())
+		for _, delivery := range due {
+			sending.Add(1)
+			go func(delivery *webhookDelivery) {
+				defer sending.Done()
+				err := n.send(ctx, delivery)
+				if err != nil && ctx.Err() != nil {
+					// An attempt cut short by shutting down is not counted, so the delivery stays queued as it was
+					n.mu.Lock()
+					delivery.sending = false
+					n.mu.Unlock()
+					return
+				}
+				n.finish(delivery, err, clock.Now())
+			}(delivery)
+		}
+
+		if !next.IsZero() && (timer == nil || !next.Equal(timerAt)) {
+			timer, timerAt = clock.After(next.Sub(clock.Now())), next
+		}
+		select {
+		case <-ctx.Done():
+			sending.Wait()
+			return ctx.Err()
+		case <-wake:
+		case <-timer:
+			timer = nil
+		}
+	}
+}
+
+// due marks the deliveries due at now as sending and returns them, with when the next delivery is due
+func (n *WebhookNotifier) due(now time.Time) ([]*webhookDelivery, time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var due []*webhookDelivery
+	var next time.Time
+	for _, delivery := range n.queue {
+		if delivery.sending {
+			continue
+		}
+		if !delivery.next.After(now) {
+			delivery.sending = true
+			due = append(due, delivery)
+		} else if next.IsZero() || delivery.next.Before(next) {
+			next = delivery.next
+		}
+	}
+	return due, next
+}
+
+// send makes an attempt of delivery
+func (n *WebhookNotifier) send(ctx context.Context, delivery *webhookDelivery) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.subscriber.URL, bytes.NewReader(delivery.body))
+	if err != nil {
+		return &webhookRejected{err}
+	}
+	timestamp := strconv.FormatInt(clockOrSystem(n.Clock).Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookTimestampHeader, timestamp)
+	request.Header.Set(WebhookSignatureHeader, WebhookSignature(delivery.subscriber.Secret, timestamp, delivery.body))
+	request.Header.Set(WebhookDeliveryHeader, delivery.payload.ID)
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return nil
+	case response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusRequestTimeout && response.StatusCode != http.StatusTooManyRequests:
+		return &webhookRejected{fmt.Errorf("subscriber rejected the delivery with status %d", response.StatusCode)}
+	}
+	return fmt.Errorf("subscriber responded with status %d", response.StatusCode)
+}
+
+/*
+finish removes a delivered delivery from the queue, or schedules its retry or moves it to the dead letters.  Run is
+woken up to wait for the retry.
+*/
+func (n *WebhookNotifier) finish(delivery *webhookDelivery, err error, now time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	defer n.wakeUp()
+
+	delivery.sending = false
+	delivery.attempts++
+	if err != nil {
+		maxAttempts := n.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = DefaultWebhookMaxAttempts
+		}
+		_, rejected := err.(*webhookRejected)
+		if !rejected && delivery.attempts < maxAttempts {
+			delivery.next = now.Add(n.backoff(delivery.attempts))
+			return
+		}
+		n.deadLetters = append(n.deadLetters, WebhookDeadLetter{
+			Subscriber: delivery.subscriber.ID,
+			Payload:    delivery.payload,
+			Attempts:   delivery.attempts,
+			Error:      err.Error(),
+			Failed:     now,
+		})
+	}
+	for i := range n.queue {
+		if n.queue[i] == delivery {
+			n.queue = append(n.queue[:i], n.queue[i+1:]...)
+			break
+		}
+	}
+}
+
+// backoff returns the wait after the attempts of a delivery failed
+func (n *WebhookNotifier) backoff(attempts int) time.Duration {
+	backoff, maxBackoff := n.InitialBackoff, n.MaxBackoff
+	if backoff <= 0 {
+		backoff = DefaultWebhookInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultWebhookMaxBackoff
+	}
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// wakeUp wakes Run to send new deliveries or wait for another retry
+func (n *WebhookNotifier) wakeUp() {
+	if n.wake == nil {
+		n.wake = make(chan struct{}, 1)
+	}
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (n *WebhookNotifier) subscriber(id string) (WebhookSubscriber, bool) {
+	for _, subscriber := range n.Subscribers {
+		if subscriber.ID == id {
+			return subscriber, true
+		}
+	}
+	return WebhookSubscriber{}, false
+}
+
+// wants returns whether the subscriber receives the prayer and lead time of event
+func (s *WebhookSubscriber) wants(event SchedulerEvent) bool {
+	if len(s.Prayers) > 0 && !containsString(s.Prayers, event.Prayer) {
+		return false
+	}
+	if len(s.LeadTimes) == 0 {
+		return event.Lead == 0
+	}
+	return containsDuration(s.LeadTimes, event.Lead)
+}
+
+/*
+WebhookSignature returns the value of the WebhookSignatureHeader of a request with body signed at timestamp, the
+Unix time of the WebhookTimestampHeader.  Subscribers compare it to the header with hmac.Equal.
+*/
+func WebhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+/*
+VerifyWebhook reads the body of a webhook request and checks its signature with secret.  Requests signed more than
+tolerance from now are rejected, so a captured request cannot be replayed later.
+*/
+func VerifyWebhook(r *http.Request, secret string, tolerance time.Duration, now time.Time) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read webhook body: %s", err)
+	}
+	timestamp := r.Header.Get(WebhookTimestampHeader)
+	signed, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("webhook timestamp %q is not a Unix time", timestamp)
+	}
+	if age := now.Sub(time.Unix(signed, 0)); age > tolerance || age < -tolerance {
+		return nil, fmt.Errorf("webhook was signed %s from now, more than %s", age, tolerance)
+	}
+	if !hmac.Equal([]byte(r.Header.Get(WebhookSignatureHeader)), []byte(WebhookSignature(secret, timestamp, body))) {
+		return nil, fmt.Errorf("webhook signature does not match")
+	}
+	return body, nil
+}
+
+func containsDuration(durations []time.Duration, duration time.Duration) bool {
+	for _, d := range durations {
+		if d == duration {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package schedule_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	psched "github.com/moali87/prayer-schedule"
+)
+
+// webhookReceiver is a subscriber endpoint which answers with the next of statuses, and then with 200
+type webhookReceiver struct {
+	t        *testing.T
+	clock    psched.Clock
+	secret   string
+	mu       sync.Mutex
+	statuses []int
+	payloads chan psched.WebhookPayload
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := psched.VerifyWebhook(req, r.secret, 5*time.Minute, r.clock.Now())
+	if err != nil {
+		r.t.Errorf("unable to verify webhook: %s", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	payload := psched.WebhookPayload{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		r.t.Errorf("unable to decode webhook: %s", err)
+	}
+	if req.Header.Get(psched.WebhookDeliveryHeader) != payload.ID {
+		r.t.Errorf("delivery header is %s, want %s", req.Header.Get(psched.WebhookDeliveryHeader), payload.ID)
+	}
+
+	r.mu.Lock()
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	r.mu.Unlock()
+	w.WriteHeader(status)
+	r.payloads <- payload
+}
+
+func webhookTestNotifier(t *testing.T, statuses ...int) (*psched.WebhookNotifier, *psched.FakeClock, *webhookReceiver) {
+	t.Helper()
+	clock := psched.NewFakeClock(time.Date(2022, time.October, 12, 19, 30, 0, 0, time.UTC))
+	receiver := &webhookReceiver{t: t, clock: clock, secret: "partner secret", statuses: statuses, payloads: make(chan psched.WebhookPayload, 10)}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	notifier := &psched.WebhookNotifier{
+		Subscribers: []psched.WebhookSubscriber{{ID: "partner", URL: server.URL, Secret: receiver.secret, Location: "mosque"}},
+		Clock:       clock,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		notifier.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return notifier, clock, receiver
+}
+
+func webhookTestEvent(t *testing.T, lead time.Duration) psched.SchedulerEvent {
+	t.Helper()
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return psched.SchedulerEvent{
+		Location:    "mosque",
+		Coordinates: psched.PrayerCalendarInputCoordinates{Latitude: 34.103, Longitude: -118.4105},
+		Prayer:      "Dhuhr",
+		Begins:      time.Date(2022, time.October, 12, 12, 30, 0, 0, losAngeles),
+		Lead:        lead,
+		Hijri:       psched.HijriDate{Year: 1444, Month: 3, Day: 16},
+	}
+}
+
+// webhookWaitFor waits for condition, which is met once the notifier has handled a response
+func webhookWaitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !condition(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the notifier")
+		}
+	}
+}
+
+func TestWebhookNotifierPayload(t *testing.T) {
+	notifier, _, receiver := webhookTestNotifier(t)
+	notifier.Notify(webhookTestEvent(t, 0))
+
+	payload := <-receiver.payloads
+	if payload.Event != psched.WebhookEventBegins || payload.Prayer != "Dhuhr" || payload.Subscriber != "partner" || payload.LeadSeconds != 0 {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	if !payload.Time.Equal(time.Date(2022, time.October, 12, 19, 30, 0, 0, time.UTC)) {
+		t.Errorf("prayer time is %s", payload.Time)
+	}
+	if payload.Location != (psched.WebhookLocation{Name: "mosque", Latitude: 34.103, Longitude: -118.4105, Timezone: "America/Los_Angeles"}) {
+		t.Errorf("location is %+v", payload.Location)
+	}
+	if payload.Hijri != (psched.WebhookHijri{Year: 1444, Month: 3, Day: 16, MonthName: "Rabi al-Awwal"}) {
+		t.Errorf("hijri date is %+v", payload.Hijri)
+	}
+	webhookWaitFor(t, func() bool { return notifier.Pending() == 0 })
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	notifier, clock, receiver := webhookTestNotifier(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+	notifier.Notify(webhookTestEvent(t, 0))
+
+	first := <-receiver.payloads
+	// The retry waits 5 seconds, and the next retry 10 seconds
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Second)
+	second := <-receiver.payloads
+	clock.BlockUntil(1)
+	clock.Advance(9 * time.Second)
+	select {
+	case <-receiver.payloads:
+		t.Fatal("retried before the backoff passed")
+	case <-time.After(20 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	third := <-receiver.payloads
+
+	if first.ID != second.ID || second.ID != third.ID {
+		t.Errorf("attempts have the IDs %s, %s and %s, want the same ID", first.ID, second.ID, third.ID)
+	}
+	webhookWaitFor(t, func() bool { return notifier.Pending() == 0 })
+	if letters := notifier.DeadLetters(); len(letters) != 0 {
+		t.Errorf("delivered webhook is a dead letter: %+v", letters)
+	}
+}
+
+func TestWebhookNotifierDeadLetters(t *testing.T) {
+	notifier, clock, receiver := webhookTestNotifier(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusBadRequest)
+	notifier.MaxAttempts = 2
+	notifier.Notify(webhookTestEvent(t, 0))
+	<-receiver.payloads
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Second)
+	<-receiver.payloads
+	webhookWaitFor(t, func() bool { return len(notifier.DeadLetters()) == 1 })
+
+	letter := notifier.DeadLetters()[0]
+	if letter.Subscriber != "partner" || letter.Attempts != 2 || letter.Payload.Prayer != "Dhuhr" || letter.Error != "subscriber responded with status 503" {
+		t.Errorf("unexpected dead letter %+v", letter)
+	}
+	if notifier.Pending() != 0 {
+		t.Errorf("%d deliveries are pending after the dead letter", notifier.Pending())
+	}
+
+	// A rejected delivery is not retried
+	if moved := notifier.RetryDeadLetters(); moved != 1 {
+		t.Errorf("moved %d dead letters, want 1", moved)
+	}
+	<-receiver.payloads
+	webhookWaitFor(t, func() bool { return len(notifier.DeadLetters()) == 1 })
+	if letter := notifier.DeadLetters()[0]; letter.Attempts != 1 || letter.Error != "subscriber rejected the delivery with status 400" {
+		t.Errorf("unexpected dead letter %+v", letter)
+	}
+}
+
+func TestWebhookNotifierLeadTimes(t *testing.T) {
+	notifier, _, receiver := webhookTestNotifier(t)
+	notifier.Subscribers[0].LeadTimes = []time.Duration{15 * time.Minute}
+	notifier.Subscribers = append(notifier.Subscribers,
+		psched.WebhookSubscriber{ID: "display", URL: notifier.Subscribers[0].URL, Secret: receiver.secret, Location: "mosque", Prayers: []string{"Fajr"}},
+		psched.WebhookSubscriber{ID: "elsewhere", URL: notifier.Subscribers[0].URL, Secret: receiver.secret, Location: "home"},
+	)
+
+	scheduler := &psched.Scheduler{LeadTimes: []time.Duration{15 * time.Minute}}
+	notifier.Attach(scheduler)
+	if len(scheduler.LeadTimes) != 1 {
+		t.Errorf("scheduler lead times are %s, want 15m0s once", scheduler.LeadTimes)
+	}
+
+	notifier.Notify(webhookTestEvent(t, 0))
+	notifier.Notify(webhookTestEvent(t, 15*time.Minute))
+	payload := <-receiver.payloads
+	if payload.Subscriber != "partner" || payload.Event != psched.WebhookEventUpcoming || payload.LeadSeconds != 900 {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	webhookWaitFor(t, func() bool { return notifier.Pending() == 0 })
+	select {
+	case payload := <-receiver.payloads:
+		t.Errorf("subscriber without the event received %+v", payload)
+	default:
+	}
+}
+
+func TestWebhookNotifierSlowSubscriber(t *testing.T) {
+	notifier, _, receiver := webhookTestNotifier(t)
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+	notifier.Subscribers[0].Prayers = []string{"Asr"}
+	notifier.Subscribers = append(notifier.Subscribers, psched.WebhookSubscriber{ID: "slow", URL: slow.URL, Location: "mosque", Prayers: []string{"Dhuhr"}})
+
+	// The delivery to the slow subscriber is still in flight when Asr is queued
+	notifier.Notify(webhookTestEvent(t, 0))
+	<-started
+	asr := webhookTestEvent(t, 0)
+	asr.Prayer = "Asr"
+	notifier.Notify(asr)
+
+	select {
+	case payload := <-receiver.payloads:
+		if payload.Prayer != "Asr" {
+			t.Errorf("unexpected payload %+v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery waited for the slow subscriber")
+	}
+}
+
+func TestWebhookNotifierShutdown(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+	notifier := &psched.WebhookNotifier{
+		Subscribers: []psched.WebhookSubscriber{{ID: "slow", URL: slow.URL, Location: "mosque"}},
+		Clock:       psched.NewFakeClock(time.Date(2022, time.October, 12, 19, 30, 0, 0, time.UTC)),
+		MaxAttempts: 1,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- notifier.Run(ctx) }()
+	notifier.Notify(webhookTestEvent(t, 0))
+	<-started
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("run returned %v, want %v", err, context.Canceled)
+	}
+
+	// The attempt in flight at shutdown is not a failed attempt, so the delivery is still queued rather than dead
+	if notifier.Pending() != 1 {
+		t.Errorf("%d deliveries are pending after shutdown, want 1", notifier.Pending())
+	}
+	if letters := notifier.DeadLetters(); len(letters) != 0 {
+		t.Errorf("delivery in flight at shutdown is a dead letter: %+v", letters)
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	now := time.Date(2022, time.October, 12, 19, 30, 0, 0, time.UTC)
+	body := []byte(`{"prayer":"Dhuhr"}`)
+	signature := psched.WebhookSignature("secret", "1665603000", body)
+
+	for name, test := range map[string]struct {
+		secret    string
+		timestamp string
+		valid     bool
+	}{
+		"valid":         {"secret", "1665603000", true},
+		"wrong secret":  {"other", "1665603000", false},
+		"replayed late": {"secret", "1665602000", false},
+	} {
+		request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		request.Header.Set(psched.WebhookTimestampHeader, test.timestamp)
+		request.Header.Set(psched.WebhookSignatureHeader, signature)
+		if test.timestamp != "1665603000" {
+			request.Header.Set(psched.WebhookSignatureHeader, psched.WebhookSignature("secret", test.timestamp, body))
+		}
+		if _, err := psched.VerifyWebhook(request, test.secret, 5*time.Minute, now); (err == nil) != test.valid {
+			t.Errorf("%s: verifying returned %v", name, err)
+		}
+	}
+}